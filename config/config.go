@@ -1,14 +1,17 @@
 package config
 
 type ConfigObj struct {
-	PAT       string
-	Showhome  bool
-	Imgstyle  string
-	Imgheight int
-	Imgwidth  int
-	Subtle    string
-	Highlight string
-	Text      string
-	Warning   string
-	Special   string
+	PAT        string
+	Host       string
+	UploadHost string
+	Timeout    int
+	Showhome   bool
+	Imgstyle   string
+	Imgheight  int
+	Imgwidth   int
+	Subtle     string
+	Highlight  string
+	Text       string
+	Warning    string
+	Special    string
 }
@@ -44,7 +44,9 @@ func Lexer(filepath string) []token {
 	for i := 0; i < len(charRunes); i++ {
 		char := charRunes[i]
 
-		if char == '/' && i+1 < len(charRunes) && charRunes[i+1] == '/' {
+		// "//" only starts a comment at the start of a line or after
+		// whitespace, so URLs such as https://ghe.example.com stay whole.
+		if char == '/' && i+1 < len(charRunes) && charRunes[i+1] == '/' && (i == 0 || unicode.IsSpace(charRunes[i-1])) {
 
 			if len(buff) > 0 {
 				tokens = append(tokens, token{value: string(buff), tokenType: int16(WORD)})
@@ -58,7 +60,7 @@ func Lexer(filepath string) []token {
 			continue
 		}
 
-		if unicode.IsLetter(char) || unicode.IsDigit(char) || char == '_' || char == '#' {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) || strings.ContainsRune("_#.-:/", char) {
 			buff = append(buff, char)
 		} else if string(char) == "=" {
 
@@ -113,6 +115,17 @@ func (l *Parser) Prase() ConfigObj {
 			switch previous.value {
 			case "PAT":
 				obj.PAT = next.value
+			case "Host":
+				obj.Host = next.value
+			case "UploadHost":
+				obj.UploadHost = next.value
+			case "Timeout":
+				var err error
+				obj.Timeout, err = strconv.Atoi(next.value)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(-1)
+				}
 			case "Showhome":
 				obj.Showhome = (next.value == "true")
 			case "Imgstyle":
@@ -121,14 +134,14 @@ func (l *Parser) Prase() ConfigObj {
 				var err error
 				obj.Imgheight, err = strconv.Atoi(next.value)
 				if err != nil {
-					fmt.Errorf("%s", err)
+					fmt.Fprintln(os.Stderr, err)
 					os.Exit(-1)
 				}
 			case "Imgw":
 				var err error
 				obj.Imgwidth, err = strconv.Atoi(next.value)
 				if err != nil {
-					fmt.Errorf("%s", err)
+					fmt.Fprintln(os.Stderr, err)
 					os.Exit(-1)
 				}
 
@@ -0,0 +1,138 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type LineKind int
+
+const (
+	Context LineKind = iota
+	Added
+	Deleted
+	NoNewline
+)
+
+// Line is a single patch line. OldNo and NewNo are zero on the side the
+// line does not exist on.
+type Line struct {
+	Kind  LineKind
+	Text  string
+	OldNo int
+	NewNo int
+}
+
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	// Section is the enclosing function or heading git prints after the
+	// range, if any.
+	Section string
+	Lines   []Line
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// Parse splits a unified diff for one file into hunks. GitHub's patch
+// fields start straight at the first hunk; any git file headers before it
+// are skipped.
+func Parse(patch string) ([]Hunk, error) {
+	var (
+		hunks []Hunk
+		cur   *Hunk
+		oldNo int
+		newNo int
+	)
+	for i, raw := range strings.Split(strings.TrimRight(patch, "\n"), "\n") {
+		if m := hunkHeader.FindStringSubmatch(raw); m != nil {
+			hunks = append(hunks, Hunk{
+				OldStart: atoiDefault(m[1], 0),
+				OldLines: atoiDefault(m[2], 1),
+				NewStart: atoiDefault(m[3], 0),
+				NewLines: atoiDefault(m[4], 1),
+				Section:  strings.TrimSpace(m[5]),
+			})
+			cur = &hunks[len(hunks)-1]
+			oldNo, newNo = cur.OldStart, cur.NewStart
+			continue
+		}
+		if cur == nil {
+			if strings.HasPrefix(raw, "@@") {
+				return nil, fmt.Errorf("line %d: malformed hunk header %q", i+1, raw)
+			}
+			continue
+		}
+
+		if raw == "" {
+			// Some producers drop the leading space of empty context lines.
+			raw = " "
+		}
+		switch raw[0] {
+		case '+':
+			cur.Lines = append(cur.Lines, Line{Kind: Added, Text: raw[1:], NewNo: newNo})
+			newNo++
+		case '-':
+			cur.Lines = append(cur.Lines, Line{Kind: Deleted, Text: raw[1:], OldNo: oldNo})
+			oldNo++
+		case '\\':
+			cur.Lines = append(cur.Lines, Line{Kind: NoNewline, Text: strings.TrimSpace(raw[1:])})
+		default:
+			cur.Lines = append(cur.Lines, Line{Kind: Context, Text: raw[1:], OldNo: oldNo, NewNo: newNo})
+			oldNo++
+			newNo++
+		}
+	}
+	return hunks, nil
+}
+
+// Row is one line of a side-by-side view; either side may be nil.
+type Row struct {
+	Left  *Line
+	Right *Line
+}
+
+// SideBySide lines up a hunk for two-column display, pairing each run of
+// deletions with the additions that follow it.
+func SideBySide(h Hunk) []Row {
+	var rows []Row
+	lines := h.Lines
+	for i := 0; i < len(lines); {
+		switch lines[i].Kind {
+		case Deleted, Added:
+			var dels, adds []*Line
+			for ; i < len(lines) && lines[i].Kind == Deleted; i++ {
+				dels = append(dels, &lines[i])
+			}
+			for ; i < len(lines) && lines[i].Kind == Added; i++ {
+				adds = append(adds, &lines[i])
+			}
+			for j := 0; j < max(len(dels), len(adds)); j++ {
+				var row Row
+				if j < len(dels) {
+					row.Left = dels[j]
+				}
+				if j < len(adds) {
+					row.Right = adds[j]
+				}
+				rows = append(rows, row)
+			}
+		default:
+			rows = append(rows, Row{Left: &lines[i], Right: &lines[i]})
+			i++
+		}
+	}
+	return rows
+}
@@ -0,0 +1,146 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  []Hunk
+	}{
+		{
+			name: "numbers lines on both sides",
+			patch: "@@ -10,4 +10,5 @@ func main() {\n" +
+				" a\n" +
+				"-b\n" +
+				"+B\n" +
+				"+C\n" +
+				" d\n" +
+				" e\n",
+			want: []Hunk{{
+				OldStart: 10, OldLines: 4, NewStart: 10, NewLines: 5,
+				Section: "func main() {",
+				Lines: []Line{
+					{Kind: Context, Text: "a", OldNo: 10, NewNo: 10},
+					{Kind: Deleted, Text: "b", OldNo: 11},
+					{Kind: Added, Text: "B", NewNo: 11},
+					{Kind: Added, Text: "C", NewNo: 12},
+					{Kind: Context, Text: "d", OldNo: 12, NewNo: 13},
+					{Kind: Context, Text: "e", OldNo: 13, NewNo: 14},
+				},
+			}},
+		},
+		{
+			name: "restarts numbering for each hunk",
+			patch: "@@ -1,2 +1,2 @@\n" +
+				"-x\n" +
+				"+y\n" +
+				" z\n" +
+				"@@ -20 +20,2 @@ section\n" +
+				" k\n" +
+				"+l\n",
+			want: []Hunk{
+				{
+					OldStart: 1, OldLines: 2, NewStart: 1, NewLines: 2,
+					Lines: []Line{
+						{Kind: Deleted, Text: "x", OldNo: 1},
+						{Kind: Added, Text: "y", NewNo: 1},
+						{Kind: Context, Text: "z", OldNo: 2, NewNo: 2},
+					},
+				},
+				{
+					OldStart: 20, OldLines: 1, NewStart: 20, NewLines: 2,
+					Section: "section",
+					Lines: []Line{
+						{Kind: Context, Text: "k", OldNo: 20, NewNo: 20},
+						{Kind: Added, Text: "l", NewNo: 21},
+					},
+				},
+			},
+		},
+		{
+			name: "new file",
+			patch: "@@ -0,0 +1,2 @@\n" +
+				"+one\n" +
+				"+two\n",
+			want: []Hunk{{
+				OldStart: 0, OldLines: 0, NewStart: 1, NewLines: 2,
+				Lines: []Line{
+					{Kind: Added, Text: "one", NewNo: 1},
+					{Kind: Added, Text: "two", NewNo: 2},
+				},
+			}},
+		},
+		{
+			name: "no newline marker and empty context line",
+			patch: "diff --git a/f b/f\n" +
+				"--- a/f\n" +
+				"+++ b/f\n" +
+				"@@ -1,3 +1,3 @@\n" +
+				" a\n" +
+				"\n" +
+				"-b\n" +
+				"\\ No newline at end of file\n" +
+				"+c\n",
+			want: []Hunk{{
+				OldStart: 1, OldLines: 3, NewStart: 1, NewLines: 3,
+				Lines: []Line{
+					{Kind: Context, Text: "a", OldNo: 1, NewNo: 1},
+					{Kind: Context, Text: "", OldNo: 2, NewNo: 2},
+					{Kind: Deleted, Text: "b", OldNo: 3},
+					{Kind: NoNewline, Text: "No newline at end of file"},
+					{Kind: Added, Text: "c", NewNo: 3},
+				},
+			}},
+		},
+		{
+			name:  "empty patch",
+			patch: "",
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.patch)
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMalformedHeader(t *testing.T) {
+	if _, err := Parse("@@ -a +b @@\n+x\n"); err == nil {
+		t.Error("Parse accepted a malformed hunk header")
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	hunks, err := Parse("@@ -1,4 +1,3 @@\n a\n-b\n-c\n+B\n d\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := SideBySide(hunks[0])
+
+	type pair struct{ left, right string }
+	text := func(l *Line) string {
+		if l == nil {
+			return "-"
+		}
+		return l.Text
+	}
+	var got []pair
+	for _, r := range rows {
+		got = append(got, pair{text(r.Left), text(r.Right)})
+	}
+	want := []pair{{"a", "a"}, {"b", "B"}, {"c", "-"}, {"d", "d"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SideBySide() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package diff
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// Highlighter colours source lines for the language guessed from a file
+// name. Lines are highlighted one at a time, so constructs spanning lines
+// such as block comments are only coloured where they start.
+type Highlighter struct {
+	lexer chroma.Lexer
+	style *chroma.Style
+}
+
+func NewHighlighter(filename string) *Highlighter {
+	lexer := lexers.Match(filename)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	return &Highlighter{lexer: chroma.Coalesce(lexer), style: styles.Get("monokai")}
+}
+
+func (h *Highlighter) Line(code string) string {
+	code = strings.ReplaceAll(code, "\t", "    ")
+	it, err := h.lexer.Tokenise(nil, code)
+	if err != nil {
+		return code
+	}
+	var sb strings.Builder
+	if err := formatters.TTY256.Format(&sb, h.style, it); err != nil {
+		return code
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// Tint gives s a background colour that survives the resets inside it.
+// bg is an SGR sequence such as "\x1b[48;5;22m".
+func Tint(s, bg string) string {
+	return bg + strings.ReplaceAll(s, "\x1b[0m", "\x1b[0m"+bg) + "\x1b[0m"
+}
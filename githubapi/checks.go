@@ -0,0 +1,62 @@
+package githubapi
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// CommitStatus is a status reported through the older commit statuses API.
+type CommitStatus struct {
+	Context     string    `json:"context"`
+	State       string    `json:"state"`
+	Description string    `json:"description"`
+	TargetURL   string    `json:"target_url"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CombinedStatus struct {
+	// State is failure, pending or success.
+	State      string         `json:"state"`
+	SHA        string         `json:"sha"`
+	TotalCount int            `json:"total_count"`
+	Statuses   []CommitStatus `json:"statuses"`
+}
+
+func (c *Client) GetCombinedStatus(repo Repository, ref string) (CombinedStatus, error) {
+	path := fmt.Sprintf("repos/%s/%s/commits/%s/status", repo.Owner.Login, repo.Name, url.PathEscape(ref))
+
+	var status CombinedStatus
+	if _, err := c.get(path, &status); err != nil {
+		return CombinedStatus{}, err
+	}
+	return status, nil
+}
+
+// CheckRun is a run reported through the checks API, which is what GitHub
+// Actions uses. Conclusion is empty until Status is completed.
+type CheckRun struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion"`
+	HTMLURL     string     `json:"html_url"`
+	DetailsURL  string     `json:"details_url"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+func (c *Client) ListCheckRuns(repo Repository, ref string, opts ListOptions) ([]CheckRun, *Response, error) {
+	v := url.Values{}
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/commits/%s/check-runs", repo.Owner.Login, repo.Name, url.PathEscape(ref)), v)
+
+	var result struct {
+		TotalCount int        `json:"total_count"`
+		CheckRuns  []CheckRun `json:"check_runs"`
+	}
+	resp, err := c.get(path, &result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return result.CheckRuns, resp, nil
+}
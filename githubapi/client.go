@@ -0,0 +1,299 @@
+package githubapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chirag-diwan/RemGit/config"
+)
+
+const (
+	defaultBaseURL   = "https://api.github.com/"
+	defaultUploadURL = "https://uploads.github.com/"
+	defaultWebURL    = "https://github.com/"
+	defaultGraphQL   = "https://api.github.com/graphql"
+	defaultUserAgent = "RemGit"
+	defaultTimeout   = 30 * time.Second
+)
+
+type Client struct {
+	BaseURL    *url.URL
+	UploadURL  *url.URL
+	WebURL     *url.URL
+	GraphQLURL *url.URL
+	Token      string
+	UserAgent  string
+
+	httpClient *http.Client
+
+	rateMu sync.Mutex
+	rates  map[string]Rate
+}
+
+type Option func(*Client) error
+
+func WithBaseURL(rawURL string) Option {
+	return func(c *Client) error {
+		u, err := parseBaseURL(rawURL)
+		if err != nil {
+			return err
+		}
+		c.BaseURL = u
+		c.GraphQLURL, _ = u.Parse("graphql")
+		return nil
+	}
+}
+
+// WithEnterpriseURLs points the client at a GitHub Enterprise Server
+// instance. host may be a bare hostname or already carry the /api/v3 path;
+// uploadHost is optional and defaults to host's /api/uploads endpoint.
+func WithEnterpriseURLs(host, uploadHost string) Option {
+	return func(c *Client) error {
+		web, err := parseBaseURL(withScheme(host))
+		if err != nil {
+			return err
+		}
+		web.Path = strings.TrimSuffix(strings.TrimSuffix(web.Path, "/"), "/api/v3") + "/"
+
+		base := *web
+		base.Path += "api/v3/"
+
+		upload := *web
+		upload.Path += "api/uploads/"
+		if uploadHost != "" {
+			u, err := parseBaseURL(withScheme(uploadHost))
+			if err != nil {
+				return err
+			}
+			if !strings.HasSuffix(u.Path, "/api/uploads/") {
+				u.Path += "api/uploads/"
+			}
+			upload = *u
+		}
+
+		graphql := *web
+		graphql.Path += "api/graphql"
+
+		c.BaseURL = &base
+		c.UploadURL = &upload
+		c.WebURL = web
+		c.GraphQLURL = &graphql
+		return nil
+	}
+}
+
+func withScheme(host string) string {
+	if strings.Contains(host, "://") {
+		return host
+	}
+	return "https://" + host
+}
+
+func isDotCom(host string) bool {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	return host == "" || host == "github.com" || host == "api.github.com"
+}
+
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) error {
+		c.httpClient.Transport = rt
+		return nil
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.httpClient.Timeout = timeout
+		return nil
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+func NewClient(cfg config.ConfigObj, opts ...Option) (*Client, error) {
+	baseURL, _ := url.Parse(defaultBaseURL)
+	uploadURL, _ := url.Parse(defaultUploadURL)
+	webURL, _ := url.Parse(defaultWebURL)
+	graphQLURL, _ := url.Parse(defaultGraphQL)
+
+	timeout := defaultTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	c := &Client{
+		BaseURL:    baseURL,
+		UploadURL:  uploadURL,
+		WebURL:     webURL,
+		GraphQLURL: graphQLURL,
+		Token:      cfg.PAT,
+		UserAgent:  defaultUserAgent,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+
+	if !isDotCom(cfg.Host) {
+		opts = append([]Option{WithEnterpriseURLs(cfg.Host, cfg.UploadHost)}, opts...)
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func parseBaseURL(rawURL string) (*url.URL, error) {
+	if !strings.HasSuffix(rawURL, "/") {
+		rawURL += "/"
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url %q: %w", rawURL, err)
+	}
+	return u, nil
+}
+
+func (c *Client) IsEnterprise() bool {
+	return c.WebURL.Host != "github.com"
+}
+
+// ownsHost reports whether u belongs to the configured GitHub instance, so
+// the token is never sent to third-party hosts (image CDNs, other forges).
+func (c *Client) ownsHost(u *url.URL) bool {
+	if u.Host == c.BaseURL.Host || u.Host == c.WebURL.Host || u.Host == c.UploadURL.Host {
+		return true
+	}
+	if !c.IsEnterprise() {
+		return strings.HasSuffix(u.Host, ".githubusercontent.com")
+	}
+	return false
+}
+
+type Response struct {
+	*http.Response
+	Rate Rate
+
+	NextPage  int
+	PrevPage  int
+	FirstPage int
+	LastPage  int
+}
+
+// newRequest resolves urlStr against BaseURL unless it is already absolute,
+// which lets callers pass the *_url fields GitHub returns as-is.
+func (c *Client) newRequest(method, urlStr string, body any) (*http.Request, error) {
+	u, err := c.BaseURL.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		buf = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, u.String(), buf)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if c.Token != "" && c.ownsHost(u) {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+	return req, nil
+}
+
+func (c *Client) do(req *http.Request, v any) (*Response, error) {
+	resp, err := c.doWithBackoff(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response := &Response{Response: resp}
+	response.Rate, _ = parseRate(resp.Header)
+	response.populatePages()
+	if err := checkResponse(resp); err != nil {
+		return response, err
+	}
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil && err != io.EOF {
+			return response, decodeError(err)
+		}
+	}
+	return response, nil
+}
+
+func (c *Client) get(urlStr string, v any) (*Response, error) {
+	req, err := c.newRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req, v)
+}
+
+// send makes a write request. Writes always act as the configured user, so
+// a missing token is reported here instead of as GitHub's 404.
+func (c *Client) send(method, urlStr string, body, v any) (*Response, error) {
+	if c.Token == "" {
+		return nil, &APIError{Kind: ErrUnauthorized, Body: ErrorBody{Message: "this action requires a personal access token (PAT in ~/.remgit.conf)"}}
+	}
+	req, err := c.newRequest(method, urlStr, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req, v)
+}
+
+const ScopeDeleteRepo = "delete_repo"
+
+// TokenScopes returns the OAuth scopes of a classic token, read off the
+// X-OAuth-Scopes header. known is false for fine-grained tokens, which do
+// not report scopes and are checked by GitHub on each request instead.
+func (c *Client) TokenScopes() (scopes []string, known bool, err error) {
+	if c.Token == "" {
+		return nil, false, &APIError{Kind: ErrUnauthorized, Body: ErrorBody{Message: "no personal access token is configured (PAT in ~/.remgit.conf)"}}
+	}
+	resp, err := c.get("user", nil)
+	if err != nil {
+		return nil, false, err
+	}
+	values := resp.Header.Values("X-OAuth-Scopes")
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+	for _, v := range values {
+		for _, scope := range strings.Split(v, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes, true, nil
+}
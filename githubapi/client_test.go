@@ -0,0 +1,105 @@
+package githubapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chirag-diwan/RemGit/config"
+)
+
+// newTestClient points a client with token at a server running handler.
+func newTestClient(t *testing.T, token string, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c, err := NewClient(config.ConfigObj{PAT: token}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClientAuthAndPagination(t *testing.T) {
+	var gotAuth, gotPath, gotQuery string
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Link", `<https://api.github.com/repositories/1/branches?page=3&per_page=2>; rel="next", `+
+			`<https://api.github.com/repositories/1/branches?page=1&per_page=2>; rel="prev", `+
+			`<https://api.github.com/repositories/1/branches?page=1&per_page=2>; rel="first", `+
+			`<https://api.github.com/repositories/1/branches?page=5&per_page=2>; rel="last"`)
+		w.Write([]byte(`[{"name":"main"},{"name":"dev"}]`))
+	})
+
+	repo := Repository{Name: "repo", Owner: Owner{Login: "octo"}}
+	branches, resp, err := c.ListBranches(repo, ListOptions{Page: 2, PerPage: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth != "token secret" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "token secret")
+	}
+	if gotPath != "/repos/octo/repo/branches" || gotQuery != "page=2&per_page=2" {
+		t.Errorf("request = %s?%s", gotPath, gotQuery)
+	}
+	if len(branches) != 2 || branches[0].Name != "main" {
+		t.Errorf("branches = %+v", branches)
+	}
+	if resp.NextPage != 3 || resp.PrevPage != 1 || resp.FirstPage != 1 || resp.LastPage != 5 {
+		t.Errorf("pages = next %d prev %d first %d last %d", resp.NextPage, resp.PrevPage, resp.FirstPage, resp.LastPage)
+	}
+}
+
+func TestClientNoTokenSendsNoAuth(t *testing.T) {
+	c := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization = %q, want none", auth)
+		}
+		w.Write([]byte(`{"name":"repo"}`))
+	})
+	if _, err := c.GetRepo("octo", "repo"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		kind   error
+	}{
+		{http.StatusNotFound, `{"message":"Not Found"}`, ErrNotFound},
+		{http.StatusUnauthorized, `{"message":"Bad credentials"}`, ErrUnauthorized},
+		{http.StatusForbidden, `{"message":"Must have admin rights"}`, ErrUnauthorized},
+		{http.StatusUnprocessableEntity, `{"message":"Validation Failed","errors":[{"field":"name","code":"invalid"}]}`, ErrValidation},
+		{http.StatusInternalServerError, `oops`, nil},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		})
+		_, err := c.GetRepo("octo", "repo")
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("status %d: error %v is not an *APIError", tt.status, err)
+			continue
+		}
+		if apiErr.StatusCode != tt.status {
+			t.Errorf("status %d: StatusCode = %d", tt.status, apiErr.StatusCode)
+		}
+		if apiErr.Kind != tt.kind {
+			t.Errorf("status %d: Kind = %v, want %v", tt.status, apiErr.Kind, tt.kind)
+		}
+		if tt.kind != nil && !errors.Is(err, tt.kind) {
+			t.Errorf("status %d: errors.Is(err, %v) = false", tt.status, tt.kind)
+		}
+		if apiErr.Body.Message == "" {
+			t.Errorf("status %d: the body message was not decoded", tt.status)
+		}
+	}
+}
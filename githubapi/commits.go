@@ -0,0 +1,98 @@
+package githubapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type CommitListOptions struct {
+	// SHA is the branch, tag or commit to list from; empty means the
+	// default branch.
+	SHA    string
+	Path   string
+	Author string
+	Since  time.Time
+	Until  time.Time
+
+	ListOptions
+}
+
+func (o CommitListOptions) values() url.Values {
+	v := url.Values{}
+	if o.SHA != "" {
+		v.Set("sha", o.SHA)
+	}
+	if o.Path != "" {
+		v.Set("path", o.Path)
+	}
+	if o.Author != "" {
+		v.Set("author", o.Author)
+	}
+	if !o.Since.IsZero() {
+		v.Set("since", o.Since.Format(time.RFC3339))
+	}
+	if !o.Until.IsZero() {
+		v.Set("until", o.Until.Format(time.RFC3339))
+	}
+	o.ListOptions.apply(v)
+	return v
+}
+
+// commitsURL expands the repository's commits_url template, falling back to
+// the conventional path for records that came without one.
+func commitsURL(repo Repository, sha string) string {
+	vars := map[string]string{}
+	if sha != "" {
+		vars["sha"] = sha
+	}
+	if repo.CommitsURL != "" {
+		return expandURL(repo.CommitsURL, vars)
+	}
+	return expandURL(fmt.Sprintf("repos/%s/%s/commits{/sha}", repo.Owner.Login, repo.Name), vars)
+}
+
+func (c *Client) GetCommits(repo Repository, opts CommitListOptions) ([]CommitItem, *Response, error) {
+	var commits []CommitItem
+	resp, err := c.get(withQuery(commitsURL(repo, ""), opts.values()), &commits)
+	if err != nil {
+		return nil, resp, err
+	}
+	return commits, resp, nil
+}
+
+// GetCommit fetches a single commit with its stats and changed files.
+func (c *Client) GetCommit(repo Repository, sha string) (CommitItem, error) {
+	var commit CommitItem
+	if _, err := c.get(commitsURL(repo, sha), &commit); err != nil {
+		return CommitItem{}, err
+	}
+	return commit, nil
+}
+
+type Comparison struct {
+	Status       string       `json:"status"`
+	AheadBy      int          `json:"ahead_by"`
+	BehindBy     int          `json:"behind_by"`
+	TotalCommits int          `json:"total_commits"`
+	HTMLURL      string       `json:"html_url"`
+	Commits      []CommitItem `json:"commits"`
+	Files        []CommitFile `json:"files"`
+}
+
+// CompareCommits diffs head against the merge base of base and head, the
+// three-dot comparison GitHub shows for pull requests.
+func (c *Client) CompareCommits(repo Repository, base, head string) (Comparison, error) {
+	escape := func(ref string) string {
+		// Branch names keep their slashes in the path.
+		return strings.ReplaceAll(url.PathEscape(ref), "%2F", "/")
+	}
+	path := fmt.Sprintf("repos/%s/%s/compare/%s...%s", repo.Owner.Login, repo.Name, escape(base), escape(head))
+
+	var comparison Comparison
+	if _, err := c.get(path, &comparison); err != nil {
+		return Comparison{}, err
+	}
+	return comparison, nil
+}
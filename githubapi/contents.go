@@ -0,0 +1,96 @@
+package githubapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type Content struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	SHA         string `json:"sha"`
+	Size        int    `json:"size"`
+	Encoding    string `json:"encoding"`
+	Content     string `json:"content"`
+	HTMLURL     string `json:"html_url"`
+	DownloadURL string `json:"download_url"`
+}
+
+func (c Content) Decode() ([]byte, error) {
+	if c.Encoding != "base64" {
+		return []byte(c.Content), nil
+	}
+	data, err := base64.StdEncoding.DecodeString(c.Content)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	return data, nil
+}
+
+// GetContents fetches a single file at ref, or the default branch when ref
+// is empty.
+func (c *Client) GetContents(repo Repository, filePath, ref string) (Content, error) {
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(filePath, "/"), "/") {
+		segments = append(segments, url.PathEscape(s))
+	}
+	path := fmt.Sprintf("repos/%s/%s/contents/%s", repo.Owner.Login, repo.Name, strings.Join(segments, "/"))
+	if ref != "" {
+		path = withQuery(path, url.Values{"ref": {ref}})
+	}
+
+	var content Content
+	if _, err := c.get(path, &content); err != nil {
+		return Content{}, err
+	}
+	return content, nil
+}
+
+type TreeEntry struct {
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+	// Type is blob, tree or commit (a submodule).
+	Type string `json:"type"`
+	SHA  string `json:"sha"`
+	Size int    `json:"size"`
+	URL  string `json:"url"`
+}
+
+type GitTree struct {
+	SHA       string      `json:"sha"`
+	Entries   []TreeEntry `json:"tree"`
+	Truncated bool        `json:"truncated"`
+}
+
+// GetTree lists a git tree by SHA or by branch or tag name. With recursive
+// set the whole hierarchy comes back at once, unless it exceeds GitHub's
+// limits, in which case Truncated is set and subtrees have to be fetched one
+// at a time.
+func (c *Client) GetTree(repo Repository, sha string, recursive bool) (GitTree, error) {
+	path := fmt.Sprintf("repos/%s/%s/git/trees/%s", repo.Owner.Login, repo.Name, strings.ReplaceAll(url.PathEscape(sha), "%2F", "/"))
+	if recursive {
+		path = withQuery(path, url.Values{"recursive": {"1"}})
+	}
+
+	var tree GitTree
+	if _, err := c.get(path, &tree); err != nil {
+		return GitTree{}, err
+	}
+	return tree, nil
+}
+
+// GetFile returns a file's bytes at ref. The contents API only inlines
+// files up to 1 MB, so larger ones are fetched from their download URL.
+func (c *Client) GetFile(repo Repository, filePath, ref string) ([]byte, error) {
+	content, err := c.GetContents(repo, filePath, ref)
+	if err != nil {
+		return nil, err
+	}
+	if content.Content == "" && content.Size > 0 && content.DownloadURL != "" {
+		return c.Download(content.DownloadURL)
+	}
+	return content.Decode()
+}
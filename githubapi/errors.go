@@ -0,0 +1,126 @@
+package githubapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+	ErrNetwork      = errors.New("network unavailable")
+)
+
+type FieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+type ErrorBody struct {
+	Message          string       `json:"message"`
+	DocumentationURL string       `json:"documentation_url"`
+	Errors           []FieldError `json:"errors"`
+}
+
+// APIError is returned for every failed call. Kind is one of the Err*
+// sentinels (or nil for unclassified failures) so callers can use errors.Is.
+type APIError struct {
+	Kind       error
+	StatusCode int
+	Body       ErrorBody
+	Rate       *Rate
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	var parts []string
+	if e.Kind != nil {
+		parts = append(parts, e.Kind.Error())
+	}
+	if e.StatusCode != 0 {
+		parts = append(parts, fmt.Sprintf("status %d", e.StatusCode))
+	}
+	if e.Body.Message != "" {
+		parts = append(parts, e.Body.Message)
+	}
+	for _, fe := range e.Body.Errors {
+		if fe.Message != "" {
+			parts = append(parts, fe.Message)
+		} else if fe.Field != "" {
+			parts = append(parts, fmt.Sprintf("%s %s", fe.Field, fe.Code))
+		}
+	}
+	if e.Rate != nil && !e.Rate.Reset.IsZero() {
+		parts = append(parts, fmt.Sprintf("%s quota resets at %s", e.Rate.Resource, e.Rate.Reset.Format("15:04:05")))
+	}
+	if e.Err != nil {
+		parts = append(parts, e.Err.Error())
+	}
+	if len(parts) == 0 {
+		return "github api error"
+	}
+	return strings.Join(parts, ": ")
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+func networkError(err error) error {
+	return &APIError{Kind: ErrNetwork, Err: err}
+}
+
+func decodeError(err error) error {
+	return &APIError{Err: fmt.Errorf("decoding response: %w", err)}
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	data, err := io.ReadAll(resp.Body)
+	if err == nil && len(data) > 0 {
+		if json.Unmarshal(data, &apiErr.Body) != nil {
+			apiErr.Body.Message = strings.TrimSpace(string(data))
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		apiErr.Kind = ErrUnauthorized
+	case http.StatusForbidden:
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" ||
+			strings.Contains(strings.ToLower(apiErr.Body.Message), "rate limit") {
+			apiErr.Kind = ErrRateLimited
+		} else {
+			apiErr.Kind = ErrUnauthorized
+		}
+	case http.StatusTooManyRequests:
+		apiErr.Kind = ErrRateLimited
+	case http.StatusNotFound:
+		apiErr.Kind = ErrNotFound
+	case http.StatusUnprocessableEntity:
+		apiErr.Kind = ErrValidation
+	}
+
+	if apiErr.Kind == ErrRateLimited {
+		if rate, ok := parseRate(resp.Header); ok {
+			apiErr.Rate = &rate
+		}
+	}
+	return apiErr
+}
@@ -0,0 +1,132 @@
+package githubapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+type graphQLError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphQLError  `json:"errors"`
+}
+
+// graphql runs a query against the GraphQL endpoint. GitHub answers most
+// query failures with 200 and an errors array, which is folded into an
+// APIError here so callers see the same error model as the REST calls.
+func (c *Client) graphql(query string, variables map[string]any, v any) error {
+	if c.Token == "" {
+		return &APIError{Kind: ErrUnauthorized, Body: ErrorBody{Message: "the GraphQL API requires a personal access token"}}
+	}
+
+	body := map[string]any{"query": query, "variables": variables}
+	req, err := c.newRequest(http.MethodPost, c.GraphQLURL.String(), body)
+	if err != nil {
+		return err
+	}
+
+	var resp graphQLResponse
+	if _, err := c.do(req, &resp); err != nil {
+		return err
+	}
+
+	if len(resp.Errors) > 0 {
+		apiErr := &APIError{}
+		var messages []string
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+			switch e.Type {
+			case "NOT_FOUND":
+				apiErr.Kind = ErrNotFound
+			case "RATE_LIMITED":
+				apiErr.Kind = ErrRateLimited
+			case "FORBIDDEN":
+				apiErr.Kind = ErrUnauthorized
+			}
+		}
+		apiErr.Body.Message = strings.Join(messages, "; ")
+		return apiErr
+	}
+
+	if err := json.Unmarshal(resp.Data, v); err != nil {
+		return decodeError(err)
+	}
+	return nil
+}
+
+const pinnedReposQuery = `query($login: String!) {
+  repositoryOwner(login: $login) {
+    ... on ProfileOwner {
+      pinnedItems(first: 6, types: REPOSITORY) {
+        nodes {
+          ... on Repository {
+            name
+            nameWithOwner
+            description
+            url
+            stargazerCount
+            forkCount
+            owner { login }
+            primaryLanguage { name }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type pinnedRepoNode struct {
+	Name            string  `json:"name"`
+	NameWithOwner   string  `json:"nameWithOwner"`
+	Description     *string `json:"description"`
+	URL             string  `json:"url"`
+	StargazerCount  int     `json:"stargazerCount"`
+	ForkCount       int     `json:"forkCount"`
+	Owner           Owner   `json:"owner"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+}
+
+// GetPinnedRepos returns the repositories pinned on a user or organisation
+// profile. Only the fields shown in listings are filled in; use GetRepo for
+// the full record.
+func (c *Client) GetPinnedRepos(login string) ([]Repository, error) {
+	var data struct {
+		RepositoryOwner *struct {
+			PinnedItems struct {
+				Nodes []pinnedRepoNode `json:"nodes"`
+			} `json:"pinnedItems"`
+		} `json:"repositoryOwner"`
+	}
+	if err := c.graphql(pinnedReposQuery, map[string]any{"login": login}, &data); err != nil {
+		return nil, err
+	}
+	if data.RepositoryOwner == nil {
+		return nil, &APIError{Kind: ErrNotFound, Body: ErrorBody{Message: "no such user or organisation: " + login}}
+	}
+
+	var repos []Repository
+	for _, n := range data.RepositoryOwner.PinnedItems.Nodes {
+		repo := Repository{
+			Name:            n.Name,
+			FullName:        n.NameWithOwner,
+			Description:     n.Description,
+			HTMLURL:         n.URL,
+			StargazersCount: n.StargazerCount,
+			ForksCount:      n.ForkCount,
+			Owner:           n.Owner,
+		}
+		if n.PrimaryLanguage != nil {
+			lang := n.PrimaryLanguage.Name
+			repo.Language = &lang
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
@@ -1,15 +1,17 @@
 package githubapi
 
 import (
-	"bytes"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
 type RepoRequest struct {
@@ -19,6 +21,22 @@ type RepoRequest struct {
 	HasIssues   bool   `json:"has_issues"`
 	HasProjects bool   `json:"has_projects"`
 	HasWiki     bool   `json:"has_wiki"`
+
+	// AutoInit commits a README, which GitIgnoreTemplate and
+	// LicenseTemplate also need to have something to commit to.
+	AutoInit          bool   `json:"auto_init,omitempty"`
+	GitignoreTemplate string `json:"gitignore_template,omitempty"`
+	LicenseTemplate   string `json:"license_template,omitempty"`
+}
+
+// TemplateRequest creates a repository from a template repository. Owner
+// is a user or organisation; empty means the authenticated user.
+type TemplateRequest struct {
+	Owner              string `json:"owner,omitempty"`
+	Name               string `json:"name"`
+	Description        string `json:"description,omitempty"`
+	IncludeAllBranches bool   `json:"include_all_branches,omitempty"`
+	Private            bool   `json:"private"`
 }
 
 type Readme struct {
@@ -82,8 +100,9 @@ type Repository struct {
 	HasPages       bool `json:"has_pages"`
 	HasDiscussions bool `json:"has_discussions"`
 
-	Archived bool `json:"archived"`
-	Disabled bool `json:"disabled"`
+	Archived   bool `json:"archived"`
+	Disabled   bool `json:"disabled"`
+	IsTemplate bool `json:"is_template"`
 
 	License *License `json:"license"`
 
@@ -92,9 +111,50 @@ type Repository struct {
 	Visibility    string `json:"visibility"`
 	DefaultBranch string `json:"default_branch"`
 
+	// The merge settings are only reported to users with admin rights.
+	AllowMergeCommit    bool `json:"allow_merge_commit"`
+	AllowSquashMerge    bool `json:"allow_squash_merge"`
+	AllowRebaseMerge    bool `json:"allow_rebase_merge"`
+	AllowAutoMerge      bool `json:"allow_auto_merge"`
+	DeleteBranchOnMerge bool `json:"delete_branch_on_merge"`
+
+	// Permissions is what the authenticated user may do, and is nil for
+	// anonymous requests.
+	Permissions *RepoPermissions `json:"permissions,omitempty"`
+
 	Score float64 `json:"score,omitempty"`
 }
 
+type RepoPermissions struct {
+	Admin    bool `json:"admin"`
+	Maintain bool `json:"maintain"`
+	Push     bool `json:"push"`
+	Triage   bool `json:"triage"`
+	Pull     bool `json:"pull"`
+}
+
+// RepoUpdate changes a repository's settings. Nil fields are left alone.
+type RepoUpdate struct {
+	Name          *string `json:"name,omitempty"`
+	Description   *string `json:"description,omitempty"`
+	Homepage      *string `json:"homepage,omitempty"`
+	Visibility    *string `json:"visibility,omitempty"`
+	DefaultBranch *string `json:"default_branch,omitempty"`
+
+	HasIssues      *bool `json:"has_issues,omitempty"`
+	HasProjects    *bool `json:"has_projects,omitempty"`
+	HasWiki        *bool `json:"has_wiki,omitempty"`
+	HasDiscussions *bool `json:"has_discussions,omitempty"`
+
+	AllowMergeCommit    *bool `json:"allow_merge_commit,omitempty"`
+	AllowSquashMerge    *bool `json:"allow_squash_merge,omitempty"`
+	AllowRebaseMerge    *bool `json:"allow_rebase_merge,omitempty"`
+	AllowAutoMerge      *bool `json:"allow_auto_merge,omitempty"`
+	DeleteBranchOnMerge *bool `json:"delete_branch_on_merge,omitempty"`
+
+	Archived *bool `json:"archived,omitempty"`
+}
+
 type Verification struct {
 	Verified   bool   `json:"verified"`
 	Reason     string `json:"reason"`
@@ -128,17 +188,69 @@ type Commit struct {
 	Verification Verification `json:"verification"`
 }
 
+type CommitParent struct {
+	SHA     string `json:"sha"`
+	URL     string `json:"url"`
+	HTMLURL string `json:"html_url"`
+}
+
+type CommitStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Total     int `json:"total"`
+}
+
+type CommitFile struct {
+	SHA              string `json:"sha"`
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Changes          int    `json:"changes"`
+	Patch            string `json:"patch"`
+	BlobURL          string `json:"blob_url"`
+	RawURL           string `json:"raw_url"`
+}
+
 type CommitItem struct {
-	SHA    string `json:"sha"`
-	NodeID string `json:"node_id"`
+	SHA     string `json:"sha"`
+	NodeID  string `json:"node_id"`
+	HTMLURL string `json:"html_url"`
 
 	Commit Commit `json:"commit"`
+
+	// Author and Committer are the linked GitHub accounts, nil when the
+	// commit's email does not belong to one.
+	Author    *Owner `json:"author"`
+	Committer *Owner `json:"committer"`
+
+	Parents []CommitParent `json:"parents"`
+
+	// Stats and Files are only filled in by GetCommit.
+	Stats *CommitStats `json:"stats"`
+	Files []CommitFile `json:"files"`
+}
+
+// Summary is the first line of the commit message.
+func (c CommitItem) Summary() string {
+	summary, _, _ := strings.Cut(c.Commit.Message, "\n")
+	return strings.TrimSpace(summary)
+}
+
+func (c CommitItem) ShortSHA() string {
+	if len(c.SHA) > 7 {
+		return c.SHA[:7]
+	}
+	return c.SHA
 }
 
 type UserSummary struct {
-	Login    string `json:"login"`
-	HTMLURL  string `json:"html_url"`
-	ReposURL string `json:"repos_url"`
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatar_url"`
+	HTMLURL   string `json:"html_url"`
+	ReposURL  string `json:"repos_url"`
+	Type      string `json:"type"`
 }
 
 type UserSearchResponse struct {
@@ -152,123 +264,162 @@ type RepoSearchResponse struct {
 	Items             []Repository `json:"items"`
 }
 
-func CloneURL(url string, path string, Progress *io.Writer) {
-	_, err := git.PlainClone(path, false, &git.CloneOptions{
-		URL:      url,
-		Progress: *Progress,
-	})
-	if err != nil {
-		panic(err)
-	}
+func (c *Client) CloneURL(rawURL string, path string, progress io.Writer) error {
+	return c.clone(&git.CloneOptions{URL: rawURL, Progress: progress}, path)
 }
 
-func GetRepoFromUrl(url string) []Repository {
-	resp, err := http.Get(url)
-	if err != nil {
-		panic(err)
+// CloneRef clones rawURL with the branch, or with tag set the tag, named ref
+// checked out.
+func (c *Client) CloneRef(rawURL, path, ref string, tag bool, progress io.Writer) error {
+	name := plumbing.NewBranchReferenceName(ref)
+	if tag {
+		name = plumbing.NewTagReferenceName(ref)
 	}
-	if resp.StatusCode != 200 {
-		panic("Failed to fetch repos")
+	return c.clone(&git.CloneOptions{URL: rawURL, ReferenceName: name, Progress: progress}, path)
+}
+
+func (c *Client) clone(opts *git.CloneOptions, path string) error {
+	if u, err := url.Parse(opts.URL); err == nil && c.Token != "" && c.ownsHost(u) {
+		opts.Auth = &githttp.BasicAuth{Username: "x-access-token", Password: c.Token}
 	}
-	defer resp.Body.Close()
-	var repos []Repository
-	err = json.NewDecoder(resp.Body).Decode(&repos)
+	_, err := git.PlainClone(path, false, opts)
+	return err
+}
+
+// Download fetches an arbitrary asset such as an avatar or raw file. Enterprise
+// instances in private mode require the token for these as well.
+func (c *Client) Download(rawURL string) ([]byte, error) {
+	req, err := c.newRequest(http.MethodGet, rawURL, nil)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return repos
-}
+	req.Header.Del("Accept")
 
-func GetCommits(repo *Repository) []Commit {
-	commitUrl := (*repo).CommitsURL
-	resp, err := http.Get(commitUrl)
+	resp, err := c.doWithBackoff(req)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	defer resp.Body.Close()
-	var commits []Commit
-	err = json.NewDecoder(resp.Body).Decode(&commits)
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		panic(err)
+		return nil, networkError(err)
 	}
-	return commits
+	return data, nil
 }
 
-func GetUsers(userName string) UserSearchResponse {
-	//url := fmt.Sprintf("https://api.github.com/search/users?q=%s&sort=followers&order=desc&per_page=20&page=1", userName)
-	url := fmt.Sprintf("https://api.github.com/search/users?q=%s", userName)
-	resp, err := http.Get(url)
-	if err != nil {
-		panic(err)
+// RawURL returns the URL serving filePath at ref straight from the repository,
+// raw.githubusercontent.com on dotcom and the /raw/ route on Enterprise.
+func (c *Client) RawURL(repo Repository, ref, filePath string) string {
+	filePath = strings.TrimPrefix(filePath, "/")
+	if c.IsEnterprise() {
+		return c.WebURL.String() + fmt.Sprintf("%s/%s/raw/%s/%s", repo.Owner.Login, repo.Name, ref, filePath)
 	}
-	defer resp.Body.Close()
-	var users UserSearchResponse
-	err = json.NewDecoder(resp.Body).Decode(&users)
-	if err != nil {
-		panic(err)
+	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s", repo.Owner.Login, repo.Name, ref, filePath)
+}
+
+// BlobURL returns the web page showing filePath at ref.
+func (c *Client) BlobURL(repo Repository, ref, filePath string) string {
+	filePath = strings.TrimPrefix(filePath, "/")
+	return c.WebURL.String() + fmt.Sprintf("%s/%s/blob/%s/%s", repo.Owner.Login, repo.Name, ref, filePath)
+}
+
+func (c *Client) GetRepoFromUrl(url string) ([]Repository, error) {
+	var repos []Repository
+	if _, err := c.get(url, &repos); err != nil {
+		return nil, err
 	}
-	return users
+	return repos, nil
 }
 
-func GetRepos(query string) RepoSearchResponse {
-	url := fmt.Sprintf("https://api.github.com/search/repositories?q=%s+sort:stars&per_page=10", query)
-	resp, err := http.Get(url)
+func (c *Client) GetUsers(query UserQuery, opts ListOptions) (UserSearchResponse, *Response, error) {
+	v := query.values()
+	opts.apply(v)
+	path := withQuery("search/users", v)
+
+	var users UserSearchResponse
+	resp, err := c.get(path, &users)
 	if err != nil {
-		panic(err)
+		return UserSearchResponse{}, resp, err
 	}
-	defer resp.Body.Close()
+	return users, resp, nil
+}
+
+func (c *Client) GetRepos(query RepoQuery, opts ListOptions) (RepoSearchResponse, *Response, error) {
+	v := query.values()
+	opts.apply(v)
+	path := withQuery("search/repositories", v)
+
 	var repos RepoSearchResponse
-	err = json.NewDecoder(resp.Body).Decode(&repos)
+	resp, err := c.get(path, &repos)
 	if err != nil {
-		panic(err)
+		return RepoSearchResponse{}, resp, err
 	}
-	return repos
+	return repos, resp, nil
 }
 
-func GetReadme(repoData Repository) string {
+// GetReadme returns the repository's README at ref, or on the default
+// branch when ref is empty.
+func (c *Client) GetReadme(repoData Repository, ref string) (string, error) {
 	Owner := repoData.Owner.Login
 	name := repoData.Name
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/readme", Owner, name)
-	resp, err := http.Get(url)
-	if err != nil {
-		panic(err)
+	path := fmt.Sprintf("repos/%s/%s/readme", Owner, name)
+	if ref != "" {
+		path = withQuery(path, url.Values{"ref": {ref}})
 	}
-	defer resp.Body.Close()
 
 	var readme Readme
-	err = json.NewDecoder(resp.Body).Decode(&readme)
-	if err != nil {
-		panic(err)
+	if _, err := c.get(path, &readme); err != nil {
+		return "", err
 	}
 	decoded, err := base64.StdEncoding.DecodeString(readme.Content)
 	if err != nil {
-		panic(err)
+		return "", decodeError(err)
 	}
-	return string(decoded)
+	return string(decoded), nil
 }
 
-func CreateRepo(githubPAT string, body RepoRequest) bool {
-	url := "https://api.github.com/user/repos"
-
-	jsonEncoding, err := json.Marshal(body)
-	if err != nil {
-		return false
+// CreateRepo creates a repository for the authenticated user, or in org
+// when it is not empty.
+func (c *Client) CreateRepo(org string, body RepoRequest) (Repository, error) {
+	path := "user/repos"
+	if org != "" {
+		path = fmt.Sprintf("orgs/%s/repos", url.PathEscape(org))
 	}
-
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonEncoding))
-	if err != nil {
-		return false
+	var repo Repository
+	if _, err := c.send(http.MethodPost, path, body, &repo); err != nil {
+		return Repository{}, err
 	}
+	return repo, nil
+}
 
-	req.Header.Set("Authorization", "token "+githubPAT)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("Content-Type", "application/json")
+// GenerateRepo creates a repository from template, which must be marked as
+// a template repository.
+func (c *Client) GenerateRepo(template Repository, body TemplateRequest) (Repository, error) {
+	var repo Repository
+	path := fmt.Sprintf("repos/%s/%s/generate", template.Owner.Login, template.Name)
+	if _, err := c.send(http.MethodPost, path, body, &repo); err != nil {
+		return Repository{}, err
+	}
+	return repo, nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return false
+// UpdateRepo changes repo's settings and returns it as it now is, which
+// has a new full name after a rename.
+func (c *Client) UpdateRepo(repo Repository, body RepoUpdate) (Repository, error) {
+	var updated Repository
+	if _, err := c.send(http.MethodPatch, fmt.Sprintf("repos/%s/%s", repo.Owner.Login, repo.Name), body, &updated); err != nil {
+		return Repository{}, err
 	}
-	defer resp.Body.Close()
+	return updated, nil
+}
 
-	return resp.StatusCode >= 200 && resp.StatusCode < 300
+// DeleteRepo deletes repo for good. Classic tokens need the delete_repo
+// scope and the user needs admin rights on the repository.
+func (c *Client) DeleteRepo(repo Repository) error {
+	_, err := c.send(http.MethodDelete, fmt.Sprintf("repos/%s/%s", repo.Owner.Login, repo.Name), nil, nil)
+	return err
 }
@@ -0,0 +1,340 @@
+package githubapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	IssueStateOpen   = "open"
+	IssueStateClosed = "closed"
+	IssueStateAll    = "all"
+)
+
+type Label struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+type Milestone struct {
+	Number int        `json:"number"`
+	Title  string     `json:"title"`
+	State  string     `json:"state"`
+	DueOn  *time.Time `json:"due_on"`
+}
+
+// Reactions is the reaction summary GitHub attaches to issues and comments.
+type Reactions struct {
+	TotalCount int `json:"total_count"`
+	PlusOne    int `json:"+1"`
+	MinusOne   int `json:"-1"`
+	Laugh      int `json:"laugh"`
+	Hooray     int `json:"hooray"`
+	Confused   int `json:"confused"`
+	Heart      int `json:"heart"`
+	Rocket     int `json:"rocket"`
+	Eyes       int `json:"eyes"`
+}
+
+// IssuePullRequest is only present on issues that are pull requests.
+type IssuePullRequest struct {
+	URL      string     `json:"url"`
+	HTMLURL  string     `json:"html_url"`
+	MergedAt *time.Time `json:"merged_at"`
+}
+
+type Issue struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	State       string `json:"state"`
+	StateReason string `json:"state_reason"`
+	Locked      bool   `json:"locked"`
+	HTMLURL     string `json:"html_url"`
+
+	User      Owner      `json:"user"`
+	Labels    []Label    `json:"labels"`
+	Assignees []Owner    `json:"assignees"`
+	Milestone *Milestone `json:"milestone"`
+	Comments  int        `json:"comments"`
+	Reactions Reactions  `json:"reactions"`
+
+	AuthorAssociation string `json:"author_association"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+
+	PullRequest *IssuePullRequest `json:"pull_request"`
+
+	// Draft and RepositoryURL are only set on search results.
+	Draft         bool   `json:"draft"`
+	RepositoryURL string `json:"repository_url"`
+}
+
+func (i Issue) IsPullRequest() bool {
+	return i.PullRequest != nil
+}
+
+// RepoFullName is the owner/name of the repository a search result belongs
+// to, taken from RepositoryURL.
+func (i Issue) RepoFullName() string {
+	_, rest, ok := strings.Cut(i.RepositoryURL, "/repos/")
+	if !ok {
+		return ""
+	}
+	return rest
+}
+
+type IssueComment struct {
+	ID                int64     `json:"id"`
+	Body              string    `json:"body"`
+	User              Owner     `json:"user"`
+	HTMLURL           string    `json:"html_url"`
+	AuthorAssociation string    `json:"author_association"`
+	Reactions         Reactions `json:"reactions"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TimelineEvent is one entry of an issue's timeline. Only the fields used
+// to find cross references are decoded.
+type TimelineEvent struct {
+	Event     string    `json:"event"`
+	Actor     *Owner    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+	Source    *struct {
+		Type  string `json:"type"`
+		Issue *struct {
+			Issue
+			Repository *Repository `json:"repository"`
+		} `json:"issue"`
+	} `json:"source"`
+}
+
+type IssueListOptions struct {
+	// State is one of the IssueState constants; empty means open.
+	State string
+	// Labels is a comma separated list, all of which must match.
+	Labels string
+	// Assignee and Milestone also take "none" and "*"; Milestone is a
+	// number otherwise.
+	Assignee  string
+	Milestone string
+	Creator   string
+	Sort      string
+	Direction string
+
+	ListOptions
+}
+
+func (o IssueListOptions) values() url.Values {
+	v := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("state", o.State)
+	set("labels", o.Labels)
+	set("assignee", o.Assignee)
+	set("milestone", o.Milestone)
+	set("creator", o.Creator)
+	set("sort", o.Sort)
+	set("direction", o.Direction)
+	o.ListOptions.apply(v)
+	return v
+}
+
+// ListIssues lists the repository's issues. GitHub returns pull requests
+// through the same endpoint; they are dropped here, so a page may hold fewer
+// than PerPage items while the Response still points at the next one.
+func (c *Client) ListIssues(repo Repository, opts IssueListOptions) ([]Issue, *Response, error) {
+	path := withQuery(fmt.Sprintf("repos/%s/%s/issues", repo.Owner.Login, repo.Name), opts.values())
+
+	var all []Issue
+	resp, err := c.get(path, &all)
+	if err != nil {
+		return nil, resp, err
+	}
+	issues := all[:0]
+	for _, issue := range all {
+		if !issue.IsPullRequest() {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, resp, nil
+}
+
+func (c *Client) GetIssue(repo Repository, number int) (Issue, error) {
+	var issue Issue
+	if _, err := c.get(fmt.Sprintf("repos/%s/%s/issues/%d", repo.Owner.Login, repo.Name, number), &issue); err != nil {
+		return Issue{}, err
+	}
+	return issue, nil
+}
+
+func (c *Client) ListIssueComments(repo Repository, number int, opts ListOptions) ([]IssueComment, *Response, error) {
+	v := url.Values{}
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/issues/%d/comments", repo.Owner.Login, repo.Name, number), v)
+
+	var comments []IssueComment
+	resp, err := c.get(path, &comments)
+	if err != nil {
+		return nil, resp, err
+	}
+	return comments, resp, nil
+}
+
+func (c *Client) ListIssueTimeline(repo Repository, number int, opts ListOptions) ([]TimelineEvent, *Response, error) {
+	v := url.Values{}
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/issues/%d/timeline", repo.Owner.Login, repo.Name, number), v)
+
+	var events []TimelineEvent
+	resp, err := c.get(path, &events)
+	if err != nil {
+		return nil, resp, err
+	}
+	return events, resp, nil
+}
+
+func (c *Client) ListMilestones(repo Repository, state string) ([]Milestone, error) {
+	v := url.Values{"per_page": {"100"}}
+	if state != "" {
+		v.Set("state", state)
+	}
+	var milestones []Milestone
+	if _, err := c.get(withQuery(fmt.Sprintf("repos/%s/%s/milestones", repo.Owner.Login, repo.Name), v), &milestones); err != nil {
+		return nil, err
+	}
+	return milestones, nil
+}
+
+// ResolveMilestone turns a milestone filter given as a title into the
+// number the issues API expects. Numbers, "none" and "*" pass through.
+func (c *Client) ResolveMilestone(repo Repository, milestone string) (string, error) {
+	milestone = strings.TrimSpace(milestone)
+	if milestone == "" || milestone == "none" || milestone == "*" {
+		return milestone, nil
+	}
+	if _, err := strconv.Atoi(milestone); err == nil {
+		return milestone, nil
+	}
+
+	milestones, err := c.ListMilestones(repo, IssueStateAll)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range milestones {
+		if strings.EqualFold(m.Title, milestone) {
+			return strconv.Itoa(m.Number), nil
+		}
+	}
+	return "", &APIError{Kind: ErrNotFound, Body: ErrorBody{Message: "no milestone titled " + strconv.Quote(milestone)}}
+}
+
+const (
+	StateReasonCompleted  = "completed"
+	StateReasonNotPlanned = "not_planned"
+	StateReasonReopened   = "reopened"
+)
+
+type IssueRequest struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	Milestone int      `json:"milestone,omitempty"`
+}
+
+// IssueUpdate holds the fields to change on an issue; nil fields are left
+// as they are.
+type IssueUpdate struct {
+	Title       *string `json:"title,omitempty"`
+	Body        *string `json:"body,omitempty"`
+	State       *string `json:"state,omitempty"`
+	StateReason *string `json:"state_reason,omitempty"`
+}
+
+func (c *Client) CreateIssue(repo Repository, body IssueRequest) (Issue, error) {
+	var issue Issue
+	if _, err := c.send(http.MethodPost, fmt.Sprintf("repos/%s/%s/issues", repo.Owner.Login, repo.Name), body, &issue); err != nil {
+		return Issue{}, err
+	}
+	return issue, nil
+}
+
+func (c *Client) UpdateIssue(repo Repository, number int, body IssueUpdate) (Issue, error) {
+	var issue Issue
+	if _, err := c.send(http.MethodPatch, fmt.Sprintf("repos/%s/%s/issues/%d", repo.Owner.Login, repo.Name, number), body, &issue); err != nil {
+		return Issue{}, err
+	}
+	return issue, nil
+}
+
+// SetIssueState closes an issue with reason, or reopens it when state is
+// open.
+func (c *Client) SetIssueState(repo Repository, number int, state, reason string) (Issue, error) {
+	update := IssueUpdate{State: &state}
+	if reason != "" {
+		update.StateReason = &reason
+	}
+	return c.UpdateIssue(repo, number, update)
+}
+
+// SetIssueLabels replaces the issue's labels with names.
+func (c *Client) SetIssueLabels(repo Repository, number int, names []string) ([]Label, error) {
+	body := map[string][]string{"labels": names}
+	if names == nil {
+		body["labels"] = []string{}
+	}
+	var labels []Label
+	if _, err := c.send(http.MethodPut, fmt.Sprintf("repos/%s/%s/issues/%d/labels", repo.Owner.Login, repo.Name, number), body, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (c *Client) CreateIssueComment(repo Repository, number int, body string) (IssueComment, error) {
+	var comment IssueComment
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/comments", repo.Owner.Login, repo.Name, number)
+	if _, err := c.send(http.MethodPost, path, map[string]string{"body": body}, &comment); err != nil {
+		return IssueComment{}, err
+	}
+	return comment, nil
+}
+
+func (c *Client) ListLabels(repo Repository, opts ListOptions) ([]Label, *Response, error) {
+	v := url.Values{}
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/labels", repo.Owner.Login, repo.Name), v)
+
+	var labels []Label
+	resp, err := c.get(path, &labels)
+	if err != nil {
+		return nil, resp, err
+	}
+	return labels, resp, nil
+}
+
+// ListAssignees lists the users issues in the repository can be assigned to.
+func (c *Client) ListAssignees(repo Repository, opts ListOptions) ([]Owner, *Response, error) {
+	v := url.Values{}
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/assignees", repo.Owner.Login, repo.Name), v)
+
+	var users []Owner
+	resp, err := c.get(path, &users)
+	if err != nil {
+		return nil, resp, err
+	}
+	return users, resp, nil
+}
@@ -0,0 +1,77 @@
+package githubapi
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MaxSearchResults is the hard cap GitHub puts on how deep any search can
+// be paged, regardless of total_count.
+const MaxSearchResults = 1000
+
+const DefaultPerPage = 30
+
+// SearchPerPage divides MaxSearchResults evenly so the last reachable page
+// never asks for results past the cap.
+const SearchPerPage = 25
+
+type ListOptions struct {
+	Page    int
+	PerPage int
+}
+
+func (o ListOptions) apply(v url.Values) {
+	if o.Page > 0 {
+		v.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(o.PerPage))
+	}
+}
+
+func withQuery(path string, v url.Values) string {
+	if len(v) == 0 {
+		return path
+	}
+	return path + "?" + v.Encode()
+}
+
+// populatePages reads the rel="next|prev|first|last" entries of the Link
+// header into the page numbers on r.
+func (r *Response) populatePages() {
+	link := r.Header.Get("Link")
+	if link == "" {
+		return
+	}
+
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(strings.TrimSpace(part), ";")
+		if len(segments) < 2 {
+			continue
+		}
+
+		rawURL := strings.Trim(strings.TrimSpace(segments[0]), "<>")
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			continue
+		}
+		page, err := strconv.Atoi(u.Query().Get("page"))
+		if err != nil {
+			continue
+		}
+
+		for _, seg := range segments[1:] {
+			switch strings.TrimSpace(seg) {
+			case `rel="next"`:
+				r.NextPage = page
+			case `rel="prev"`:
+				r.PrevPage = page
+			case `rel="first"`:
+				r.FirstPage = page
+			case `rel="last"`:
+				r.LastPage = page
+			}
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package githubapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PullStateMerged selects merged pull requests in ListPulls. GitHub itself
+// only knows open and closed.
+const PullStateMerged = "merged"
+
+type PullBranch struct {
+	Label string      `json:"label"`
+	Ref   string      `json:"ref"`
+	SHA   string      `json:"sha"`
+	Repo  *Repository `json:"repo"`
+}
+
+type PullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	Draft   bool   `json:"draft"`
+	Locked  bool   `json:"locked"`
+	HTMLURL string `json:"html_url"`
+
+	User               Owner   `json:"user"`
+	Labels             []Label `json:"labels"`
+	Assignees          []Owner `json:"assignees"`
+	RequestedReviewers []Owner `json:"requested_reviewers"`
+
+	Head PullBranch `json:"head"`
+	Base PullBranch `json:"base"`
+
+	// Merged, Mergeable, MergeableState and the counts below are only
+	// filled in by GetPull. Mergeable is nil while GitHub is still
+	// working it out.
+	Merged         bool   `json:"merged"`
+	MergedBy       *Owner `json:"merged_by"`
+	Mergeable      *bool  `json:"mergeable"`
+	MergeableState string `json:"mergeable_state"`
+
+	Comments       int `json:"comments"`
+	ReviewComments int `json:"review_comments"`
+	Commits        int `json:"commits"`
+	Additions      int `json:"additions"`
+	Deletions      int `json:"deletions"`
+	ChangedFiles   int `json:"changed_files"`
+
+	AuthorAssociation string `json:"author_association"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+}
+
+type PullListOptions struct {
+	// State is open, closed, all or PullStateMerged; empty means open.
+	State string
+	// Head filters by user:branch, Base by branch name.
+	Head      string
+	Base      string
+	Sort      string
+	Direction string
+
+	ListOptions
+}
+
+func (o PullListOptions) values() url.Values {
+	v := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	state := o.State
+	if state == PullStateMerged {
+		state = IssueStateClosed
+	}
+	set("state", state)
+	set("head", o.Head)
+	set("base", o.Base)
+	set("sort", o.Sort)
+	set("direction", o.Direction)
+	o.ListOptions.apply(v)
+	return v
+}
+
+// ListPulls lists the repository's pull requests. For PullStateMerged the
+// closed ones are fetched and the unmerged dropped, so a page may hold fewer
+// than PerPage items.
+func (c *Client) ListPulls(repo Repository, opts PullListOptions) ([]PullRequest, *Response, error) {
+	path := withQuery(fmt.Sprintf("repos/%s/%s/pulls", repo.Owner.Login, repo.Name), opts.values())
+
+	var pulls []PullRequest
+	resp, err := c.get(path, &pulls)
+	if err != nil {
+		return nil, resp, err
+	}
+	if opts.State == PullStateMerged {
+		merged := pulls[:0]
+		for _, pr := range pulls {
+			if pr.MergedAt != nil {
+				merged = append(merged, pr)
+			}
+		}
+		pulls = merged
+	}
+	return pulls, resp, nil
+}
+
+func (c *Client) GetPull(repo Repository, number int) (PullRequest, error) {
+	var pr PullRequest
+	if _, err := c.get(fmt.Sprintf("repos/%s/%s/pulls/%d", repo.Owner.Login, repo.Name, number), &pr); err != nil {
+		return PullRequest{}, err
+	}
+	return pr, nil
+}
+
+func (c *Client) ListPullCommits(repo Repository, number int, opts ListOptions) ([]CommitItem, *Response, error) {
+	v := url.Values{}
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/pulls/%d/commits", repo.Owner.Login, repo.Name, number), v)
+
+	var commits []CommitItem
+	resp, err := c.get(path, &commits)
+	if err != nil {
+		return nil, resp, err
+	}
+	return commits, resp, nil
+}
+
+func (c *Client) ListPullFiles(repo Repository, number int, opts ListOptions) ([]CommitFile, *Response, error) {
+	v := url.Values{}
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/pulls/%d/files", repo.Owner.Login, repo.Name, number), v)
+
+	var files []CommitFile
+	resp, err := c.get(path, &files)
+	if err != nil {
+		return nil, resp, err
+	}
+	return files, resp, nil
+}
+
+const (
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+	ReviewDismissed        = "DISMISSED"
+	ReviewPending          = "PENDING"
+)
+
+type Review struct {
+	ID                int64      `json:"id"`
+	User              Owner      `json:"user"`
+	Body              string     `json:"body"`
+	State             string     `json:"state"`
+	HTMLURL           string     `json:"html_url"`
+	CommitID          string     `json:"commit_id"`
+	AuthorAssociation string     `json:"author_association"`
+	SubmittedAt       *time.Time `json:"submitted_at"`
+}
+
+func (c *Client) ListReviews(repo Repository, number int, opts ListOptions) ([]Review, *Response, error) {
+	v := url.Values{}
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/pulls/%d/reviews", repo.Owner.Login, repo.Name, number), v)
+
+	var reviews []Review
+	resp, err := c.get(path, &reviews)
+	if err != nil {
+		return nil, resp, err
+	}
+	return reviews, resp, nil
+}
+
+// ReviewComment is a comment on a line of a pull request's diff. Line is
+// nil once the comment is outdated by later pushes; Side says whether it
+// counts in the old (LEFT) or new (RIGHT) file.
+type ReviewComment struct {
+	ID        int64  `json:"id"`
+	ReviewID  int64  `json:"pull_request_review_id"`
+	InReplyTo int64  `json:"in_reply_to_id"`
+	Body      string `json:"body"`
+	Path      string `json:"path"`
+	DiffHunk  string `json:"diff_hunk"`
+	Line      *int   `json:"line"`
+	StartLine *int   `json:"start_line"`
+	Side      string `json:"side"`
+	CommitID  string `json:"commit_id"`
+	HTMLURL   string `json:"html_url"`
+
+	User              Owner     `json:"user"`
+	AuthorAssociation string    `json:"author_association"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (c *Client) ListReviewComments(repo Repository, number int, opts ListOptions) ([]ReviewComment, *Response, error) {
+	v := url.Values{}
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/pulls/%d/comments", repo.Owner.Login, repo.Name, number), v)
+
+	var comments []ReviewComment
+	resp, err := c.get(path, &comments)
+	if err != nil {
+		return nil, resp, err
+	}
+	return comments, resp, nil
+}
+
+// Review events accepted by CreateReview.
+const (
+	ReviewEventApprove        = "APPROVE"
+	ReviewEventRequestChanges = "REQUEST_CHANGES"
+	ReviewEventComment        = "COMMENT"
+)
+
+type ReviewRequest struct {
+	// CommitID pins the review to the head commit it was written against.
+	CommitID string `json:"commit_id,omitempty"`
+	Body     string `json:"body,omitempty"`
+	Event    string `json:"event"`
+}
+
+func (c *Client) CreateReview(repo Repository, number int, body ReviewRequest) (Review, error) {
+	var review Review
+	if _, err := c.send(http.MethodPost, fmt.Sprintf("repos/%s/%s/pulls/%d/reviews", repo.Owner.Login, repo.Name, number), body, &review); err != nil {
+		return Review{}, err
+	}
+	return review, nil
+}
+
+// ReviewCommentRequest places a comment on Line of Path, counted in the old
+// (LEFT) or new (RIGHT) file.
+type ReviewCommentRequest struct {
+	Body     string `json:"body"`
+	CommitID string `json:"commit_id"`
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Side     string `json:"side"`
+}
+
+func (c *Client) CreateReviewComment(repo Repository, number int, body ReviewCommentRequest) (ReviewComment, error) {
+	var comment ReviewComment
+	if _, err := c.send(http.MethodPost, fmt.Sprintf("repos/%s/%s/pulls/%d/comments", repo.Owner.Login, repo.Name, number), body, &comment); err != nil {
+		return ReviewComment{}, err
+	}
+	return comment, nil
+}
+
+const (
+	MergeMethodMerge  = "merge"
+	MergeMethodSquash = "squash"
+	MergeMethodRebase = "rebase"
+)
+
+type MergeRequest struct {
+	CommitTitle   string `json:"commit_title,omitempty"`
+	CommitMessage string `json:"commit_message,omitempty"`
+	// SHA makes the merge fail if the head has moved since it was read.
+	SHA         string `json:"sha,omitempty"`
+	MergeMethod string `json:"merge_method"`
+}
+
+type MergeResult struct {
+	SHA     string `json:"sha"`
+	Merged  bool   `json:"merged"`
+	Message string `json:"message"`
+}
+
+func (c *Client) MergePull(repo Repository, number int, body MergeRequest) (MergeResult, error) {
+	var result MergeResult
+	if _, err := c.send(http.MethodPut, fmt.Sprintf("repos/%s/%s/pulls/%d/merge", repo.Owner.Login, repo.Name, number), body, &result); err != nil {
+		return MergeResult{}, err
+	}
+	return result, nil
+}
+
+// DeleteBranch removes a branch, as done after merging a pull request.
+func (c *Client) DeleteBranch(repo Repository, branch string) error {
+	path := fmt.Sprintf("repos/%s/%s/git/refs/heads/%s", repo.Owner.Login, repo.Name, strings.ReplaceAll(url.PathEscape(branch), "%2F", "/"))
+	_, err := c.send(http.MethodDelete, path, nil, nil)
+	return err
+}
+
+type IssueSearchResponse struct {
+	TotalCount int     `json:"total_count"`
+	Items      []Issue `json:"items"`
+}
+
+// ReviewRequestsQuery finds the open pull requests waiting on the
+// authenticated user's review.
+const ReviewRequestsQuery = "is:pr is:open archived:false review-requested:@me"
+
+// SearchIssues runs an issue and pull request search. The items carry
+// RepositoryURL in place of a full repository.
+func (c *Client) SearchIssues(query string, opts ListOptions) (IssueSearchResponse, *Response, error) {
+	v := url.Values{"q": {query}}
+	opts.apply(v)
+	path := withQuery("search/issues", v)
+
+	var result IssueSearchResponse
+	resp, err := c.get(path, &result)
+	if err != nil {
+		return IssueSearchResponse{}, resp, err
+	}
+	return result, resp, nil
+}
+
+// PullKey names a pull request across repositories.
+type PullKey struct {
+	Repo   string
+	Number int
+}
+
+// PullStatus summarises a pull request's review decision (APPROVED,
+// CHANGES_REQUESTED or REVIEW_REQUIRED) and the rolled up state of the
+// checks on its head commit (SUCCESS, FAILURE, PENDING, ERROR or
+// EXPECTED). Either is empty when GitHub has nothing to report.
+type PullStatus struct {
+	ReviewDecision string
+	Checks         string
+}
+
+const pullStatusFields = `fragment statusFields on PullRequest {
+  reviewDecision
+  commits(last: 1) { nodes { commit { statusCheckRollup { state } } } }
+}`
+
+// GetPullStatuses fetches the review and check summary of several pull
+// requests in one GraphQL query, which the REST list endpoints lack.
+func (c *Client) GetPullStatuses(keys []PullKey) (map[PullKey]PullStatus, error) {
+	statuses := make(map[PullKey]PullStatus)
+	if len(keys) == 0 {
+		return statuses, nil
+	}
+
+	var params, fields []string
+	vars := make(map[string]any)
+	for i, k := range keys {
+		owner, name, _ := strings.Cut(k.Repo, "/")
+		params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!, $p%d: Int!", i, i, i))
+		fields = append(fields, fmt.Sprintf("  pr%d: repository(owner: $o%d, name: $n%d) { pullRequest(number: $p%d) { ...statusFields } }", i, i, i, i))
+		vars[fmt.Sprintf("o%d", i)] = owner
+		vars[fmt.Sprintf("n%d", i)] = name
+		vars[fmt.Sprintf("p%d", i)] = k.Number
+	}
+	query := fmt.Sprintf("query(%s) {\n%s\n}\n\n%s", strings.Join(params, ", "), strings.Join(fields, "\n"), pullStatusFields)
+
+	var data map[string]*struct {
+		PullRequest *struct {
+			ReviewDecision *string `json:"reviewDecision"`
+			Commits        struct {
+				Nodes []struct {
+					Commit struct {
+						StatusCheckRollup *struct {
+							State string `json:"state"`
+						} `json:"statusCheckRollup"`
+					} `json:"commit"`
+				} `json:"nodes"`
+			} `json:"commits"`
+		} `json:"pullRequest"`
+	}
+	if err := c.graphql(query, vars, &data); err != nil {
+		return nil, err
+	}
+
+	for i, k := range keys {
+		node := data[fmt.Sprintf("pr%d", i)]
+		if node == nil || node.PullRequest == nil {
+			continue
+		}
+		var status PullStatus
+		if node.PullRequest.ReviewDecision != nil {
+			status.ReviewDecision = *node.PullRequest.ReviewDecision
+		}
+		if commits := node.PullRequest.Commits.Nodes; len(commits) > 0 && commits[0].Commit.StatusCheckRollup != nil {
+			status.Checks = commits[0].Commit.StatusCheckRollup.State
+		}
+		statuses[k] = status
+	}
+	return statuses, nil
+}
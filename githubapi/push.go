@@ -0,0 +1,141 @@
+package githubapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+type PushDirOptions struct {
+	// Branch is the branch a directory that is not yet a git repository
+	// starts on; empty means main.
+	Branch string
+	// Message is the message of the initial commit.
+	Message string
+	// Author signs the initial commit when git has no user configured.
+	Author *object.Signature
+	// Progress receives a line per local step, followed by the remote's
+	// progress output.
+	Progress io.Writer
+}
+
+// PushDirectory publishes the directory at path to remoteURL. It makes path
+// a git repository if it is not one, commits everything in it if there are
+// no commits yet, adds remoteURL as origin and pushes the checked out
+// branch, which it returns. HTTPS remotes on this host use the token; SSH
+// remotes use the SSH agent.
+func (c *Client) PushDirectory(path, remoteURL string, opts PushDirOptions) (string, error) {
+	progress := opts.Progress
+	if progress == nil {
+		progress = io.Discard
+	}
+
+	if info, err := os.Stat(path); err != nil {
+		return "", err
+	} else if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", path)
+	}
+
+	repo, err := git.PlainOpen(path)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		branch := opts.Branch
+		if branch == "" {
+			branch = "main"
+		}
+		repo, err = git.PlainInitWithOptions(path, &git.PlainInitOptions{
+			InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName(branch)},
+		})
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(progress, "Initialised a git repository in %s\n", path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		if err := commitAll(repo, opts, progress); err != nil {
+			return "", err
+		}
+		head, err = repo.Head()
+	}
+	if err != nil {
+		return "", err
+	}
+	if !head.Name().IsBranch() {
+		return "", errors.New("HEAD is detached; check out a branch to push")
+	}
+	branch := head.Name().Short()
+
+	if remote, err := repo.Remote("origin"); err == nil {
+		if urls := remote.Config().URLs; len(urls) == 0 || urls[0] != remoteURL {
+			return "", fmt.Errorf("origin already points at %s", strings.Join(urls, ", "))
+		}
+	} else if errors.Is(err, git.ErrRemoteNotFound) {
+		if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remoteURL}}); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(progress, "Added origin %s\n", remoteURL)
+	} else {
+		return "", err
+	}
+
+	push := &git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", head.Name(), head.Name()))},
+		Progress:   progress,
+	}
+	if u, err := url.Parse(remoteURL); err == nil && c.Token != "" && c.ownsHost(u) {
+		push.Auth = &githttp.BasicAuth{Username: "x-access-token", Password: c.Token}
+	}
+	fmt.Fprintf(progress, "Pushing %s\n", branch)
+	if err := repo.Push(push); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return "", err
+	}
+	return branch, nil
+}
+
+// commitAll makes the initial commit of everything in the worktree that
+// is not ignored.
+func commitAll(repo *git.Repository, opts PushDirOptions, progress io.Writer) error {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := wt.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return err
+	}
+	if status.IsClean() {
+		return fmt.Errorf("%s has no files to commit", filepath.Base(wt.Filesystem.Root()))
+	}
+
+	message := opts.Message
+	if message == "" {
+		message = "Initial commit"
+	}
+	_, err = wt.Commit(message, &git.CommitOptions{})
+	if errors.Is(err, git.ErrMissingAuthor) && opts.Author != nil {
+		_, err = wt.Commit(message, &git.CommitOptions{Author: opts.Author})
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(progress, "Committed %d files\n", len(status))
+	return nil
+}
@@ -0,0 +1,307 @@
+package githubapi
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const searchDateLayout = "2006-01-02"
+
+// IntRange is an inclusive numeric qualifier range; a nil bound is
+// unbounded, so that ranges such as "0" and "<1" can be expressed.
+type IntRange struct {
+	Min *int
+	Max *int
+}
+
+func (r IntRange) IsZero() bool {
+	return r.Min == nil && r.Max == nil
+}
+
+func (r IntRange) String() string {
+	switch {
+	case r.Min != nil && r.Max != nil && *r.Min == *r.Max:
+		return strconv.Itoa(*r.Min)
+	case r.Min != nil && r.Max != nil:
+		return fmt.Sprintf("%d..%d", *r.Min, *r.Max)
+	case r.Min != nil:
+		return fmt.Sprintf(">=%d", *r.Min)
+	case r.Max != nil:
+		return fmt.Sprintf("<=%d", *r.Max)
+	}
+	return ""
+}
+
+// ParseIntRange accepts the forms GitHub's search syntax does: "N", ">N",
+// ">=N", "<N", "<=N", "N..M", "N..*" and "*..M".
+func ParseIntRange(s string) (IntRange, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return IntRange{}, nil
+	}
+
+	// bound parses one side of the range; "*" leaves it unbounded.
+	bound := func(v string) (*int, error) {
+		if v == "*" {
+			return nil, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid number %q", v)
+		}
+		return &n, nil
+	}
+
+	if lo, hi, ok := strings.Cut(s, ".."); ok {
+		from, err := bound(lo)
+		if err != nil {
+			return IntRange{}, err
+		}
+		to, err := bound(hi)
+		if err != nil {
+			return IntRange{}, err
+		}
+		if from != nil && to != nil && *from > *to {
+			return IntRange{}, fmt.Errorf("empty range %q", s)
+		}
+		return IntRange{Min: from, Max: to}, nil
+	}
+
+	for _, prefix := range []string{">=", "<=", ">", "<"} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			n, err := bound(rest)
+			if err != nil || n == nil {
+				return IntRange{}, fmt.Errorf("invalid number %q", rest)
+			}
+			switch prefix {
+			case ">=":
+				return IntRange{Min: n}, nil
+			case "<=":
+				return IntRange{Max: n}, nil
+			case ">":
+				*n++
+				return IntRange{Min: n}, nil
+			default:
+				if *n == 0 {
+					return IntRange{}, fmt.Errorf("empty range %q", s)
+				}
+				*n--
+				return IntRange{Max: n}, nil
+			}
+		}
+	}
+
+	n, err := bound(s)
+	if err != nil || n == nil {
+		return IntRange{}, fmt.Errorf("invalid number %q", s)
+	}
+	return IntRange{Min: n, Max: n}, nil
+}
+
+// DateRange is an inclusive date qualifier range; zero times are unbounded.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func (r DateRange) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+func (r DateRange) String() string {
+	switch {
+	case !r.From.IsZero() && !r.To.IsZero():
+		return r.From.Format(searchDateLayout) + ".." + r.To.Format(searchDateLayout)
+	case !r.From.IsZero():
+		return ">=" + r.From.Format(searchDateLayout)
+	case !r.To.IsZero():
+		return "<=" + r.To.Format(searchDateLayout)
+	}
+	return ""
+}
+
+// ParseDateRange accepts YYYY-MM-DD dates in the same forms as ParseIntRange.
+func ParseDateRange(s string) (DateRange, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DateRange{}, nil
+	}
+
+	parse := func(v string) (time.Time, error) {
+		if v == "*" {
+			return time.Time{}, nil
+		}
+		t, err := time.Parse(searchDateLayout, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q, want YYYY-MM-DD", v)
+		}
+		return t, nil
+	}
+
+	if lo, hi, ok := strings.Cut(s, ".."); ok {
+		from, err := parse(lo)
+		if err != nil {
+			return DateRange{}, err
+		}
+		to, err := parse(hi)
+		if err != nil {
+			return DateRange{}, err
+		}
+		return DateRange{From: from, To: to}, nil
+	}
+
+	for _, prefix := range []string{">=", "<=", ">", "<"} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			t, err := parse(rest)
+			if err != nil {
+				return DateRange{}, err
+			}
+			switch prefix {
+			case ">=":
+				return DateRange{From: t}, nil
+			case "<=":
+				return DateRange{To: t}, nil
+			case ">":
+				return DateRange{From: t.AddDate(0, 0, 1)}, nil
+			default:
+				return DateRange{To: t.AddDate(0, 0, -1)}, nil
+			}
+		}
+	}
+
+	t, err := parse(s)
+	if err != nil {
+		return DateRange{}, err
+	}
+	return DateRange{From: t, To: t}, nil
+}
+
+const (
+	ForkExclude = ""
+	ForkInclude = "true"
+	ForkOnly    = "only"
+
+	VisibilityAny     = ""
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+
+	SortBestMatch = ""
+	OrderDesc     = "desc"
+	OrderAsc      = "asc"
+)
+
+type RepoQuery struct {
+	Text       string
+	Language   string
+	Stars      IntRange
+	Forks      IntRange
+	Topic      string
+	User       string
+	Org        string
+	Pushed     DateRange
+	License    string
+	Archived   *bool
+	Fork       string
+	Visibility string
+
+	// Sort is one of stars, forks, help-wanted-issues or updated.
+	Sort  string
+	Order string
+}
+
+const (
+	UserTypeAny  = ""
+	UserTypeUser = "user"
+	UserTypeOrg  = "org"
+)
+
+type UserQuery struct {
+	Text      string
+	Type      string
+	Followers IntRange
+	Repos     IntRange
+	Location  string
+	Language  string
+
+	// Sort is one of followers, repositories or joined.
+	Sort  string
+	Order string
+}
+
+func (q UserQuery) String() string {
+	return joinTerms(
+		q.Text,
+		qualifier("type", q.Type),
+		qualifier("followers", q.Followers.String()),
+		qualifier("repos", q.Repos.String()),
+		qualifier("location", q.Location),
+		qualifier("language", q.Language),
+	)
+}
+
+func (q UserQuery) values() url.Values {
+	return searchValues(q.String(), q.Sort, q.Order)
+}
+
+// searchValues builds the parameters of a search request. GitHub ignores
+// the order without a sort, so it is only sent along with one.
+func searchValues(query, sort, order string) url.Values {
+	v := url.Values{}
+	v.Set("q", query)
+	if sort != "" {
+		v.Set("sort", sort)
+		if order != "" {
+			v.Set("order", order)
+		}
+	}
+	return v
+}
+
+func qualifier(key, value string) string {
+	if value == "" {
+		return ""
+	}
+	if strings.ContainsAny(value, " \t") {
+		value = strconv.Quote(value)
+	}
+	return key + ":" + value
+}
+
+func joinTerms(terms ...string) string {
+	var parts []string
+	for _, t := range terms {
+		if t = strings.TrimSpace(t); t != "" {
+			parts = append(parts, t)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// String composes the q parameter, e.g. `cli language:go stars:>=100`.
+func (q RepoQuery) String() string {
+	archived := ""
+	if q.Archived != nil {
+		archived = strconv.FormatBool(*q.Archived)
+	}
+	return joinTerms(
+		q.Text,
+		qualifier("language", q.Language),
+		qualifier("stars", q.Stars.String()),
+		qualifier("forks", q.Forks.String()),
+		qualifier("topic", q.Topic),
+		qualifier("user", q.User),
+		qualifier("org", q.Org),
+		qualifier("pushed", q.Pushed.String()),
+		qualifier("license", q.License),
+		qualifier("archived", archived),
+		qualifier("fork", q.Fork),
+		qualifier("is", q.Visibility),
+	)
+}
+
+func (q RepoQuery) values() url.Values {
+	return searchValues(q.String(), q.Sort, q.Order)
+}
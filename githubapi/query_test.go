@@ -0,0 +1,101 @@
+package githubapi
+
+import "testing"
+
+func TestParseIntRange(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "0", want: "0"},
+		{in: "42", want: "42"},
+		{in: " 7 ", want: "7"},
+		{in: ">=0", want: ">=0"},
+		{in: ">0", want: ">=1"},
+		{in: ">10", want: ">=11"},
+		{in: "<=0", want: "<=0"},
+		{in: "<1", want: "<=0"},
+		{in: "<100", want: "<=99"},
+		{in: "10..500", want: "10..500"},
+		{in: "0..0", want: "0"},
+		{in: "0..5", want: "0..5"},
+		{in: "10..*", want: ">=10"},
+		{in: "*..20", want: "<=20"},
+		{in: "*..*", want: ""},
+		{in: "<0", wantErr: true},
+		{in: "5..1", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: ">=", wantErr: true},
+		{in: ">*", wantErr: true},
+		{in: "*", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1..x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseIntRange(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseIntRange(%q) = %q, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseIntRange(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseIntRange(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got.IsZero() != (tt.want == "") {
+			t.Errorf("ParseIntRange(%q).IsZero() = %v", tt.in, got.IsZero())
+		}
+	}
+}
+
+func TestRepoQueryZeroRanges(t *testing.T) {
+	stars, _ := ParseIntRange("0")
+	forks, _ := ParseIntRange("<1")
+	q := RepoQuery{Text: "cli", Stars: stars, Forks: forks}
+	if got, want := q.String(), "cli stars:0 forks:<=0"; got != want {
+		t.Errorf("RepoQuery.String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "2024-01-31", want: "2024-01-31..2024-01-31"},
+		{in: ">=2024-01-01", want: ">=2024-01-01"},
+		{in: ">2024-01-31", want: ">=2024-02-01"},
+		{in: "<=2024-03-01", want: "<=2024-03-01"},
+		{in: "<2024-03-01", want: "<=2024-02-29"},
+		{in: "2024-01-01..2024-06-30", want: "2024-01-01..2024-06-30"},
+		{in: "2024-01-01..*", want: ">=2024-01-01"},
+		{in: "*..2024-06-30", want: "<=2024-06-30"},
+		{in: "2024-13-01", wantErr: true},
+		{in: "yesterday", wantErr: true},
+		{in: "2024-01-01..soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDateRange(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDateRange(%q) = %q, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDateRange(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseDateRange(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package githubapi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxRetries = 3
+	maxBackoff = 60 * time.Second
+)
+
+// sleep and now are replaced in tests so backoff runs without waiting.
+var (
+	sleep = time.Sleep
+	now   = time.Now
+)
+
+type Rate struct {
+	Resource  string
+	Limit     int
+	Remaining int
+	Used      int
+	Reset     time.Time
+}
+
+// Low reports whether less than a tenth of the quota is left.
+func (r Rate) Low() bool {
+	return r.Limit > 0 && r.Remaining*10 < r.Limit
+}
+
+func parseRate(h http.Header) (Rate, bool) {
+	limit := h.Get("X-RateLimit-Limit")
+	if limit == "" {
+		return Rate{}, false
+	}
+
+	var rate Rate
+	rate.Resource = h.Get("X-RateLimit-Resource")
+	if rate.Resource == "" {
+		rate.Resource = "core"
+	}
+	rate.Limit, _ = strconv.Atoi(limit)
+	rate.Remaining, _ = strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	rate.Used, _ = strconv.Atoi(h.Get("X-RateLimit-Used"))
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rate.Reset = time.Unix(reset, 0)
+	}
+	return rate, true
+}
+
+func (c *Client) recordRate(rate Rate) {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	if c.rates == nil {
+		c.rates = make(map[string]Rate)
+	}
+	c.rates[rate.Resource] = rate
+}
+
+// Rates returns the last quota seen for every resource (core, search, ...)
+// ordered by name.
+func (c *Client) Rates() []Rate {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	rates := make([]Rate, 0, len(c.rates))
+	for _, r := range c.rates {
+		rates = append(rates, r)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Resource < rates[j].Resource })
+	return rates
+}
+
+func (c *Client) Rate(resource string) (Rate, bool) {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	r, ok := c.rates[resource]
+	return r, ok
+}
+
+// retryDelay decides whether a 403/429 response is worth retrying and how
+// long to wait first. Secondary limits carry Retry-After or say so in the
+// body; an exhausted primary quota is only waited on if it resets soon.
+func retryDelay(resp *http.Response, body []byte, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if after := resp.Header.Get("Retry-After"); after != "" {
+		if secs, err := strconv.Atoi(after); err == nil {
+			d := time.Duration(secs) * time.Second
+			return d, d <= maxBackoff
+		}
+	}
+
+	if strings.Contains(strings.ToLower(string(body)), "secondary rate limit") {
+		d := time.Duration(1<<attempt) * time.Minute / 4
+		return d, d <= maxBackoff
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		rate, _ := parseRate(resp.Header)
+		d := rate.Reset.Sub(now()) + time.Second
+		return d, d > 0 && d <= 5*time.Second
+	}
+	return 0, false
+}
+
+func (c *Client) doWithBackoff(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, networkError(err)
+		}
+		if rate, ok := parseRate(resp.Header); ok {
+			c.recordRate(rate)
+		}
+
+		if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		delay, retry := retryDelay(resp, body, attempt)
+		if !retry || attempt >= maxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+
+		sleep(delay)
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return resp, nil
+			}
+		}
+	}
+}
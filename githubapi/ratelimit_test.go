@@ -0,0 +1,181 @@
+package githubapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Unix(1_700_000_000, 0)
+
+// fakeClock stops the backoff from sleeping, recording the delays instead.
+func fakeClock(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var slept []time.Duration
+	oldSleep, oldNow := sleep, now
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	now = func() time.Time { return testNow }
+	t.Cleanup(func() { sleep, now = oldSleep, oldNow })
+	return &slept
+}
+
+func TestParseRate(t *testing.T) {
+	h := http.Header{}
+	if _, ok := parseRate(h); ok {
+		t.Error("parseRate accepted a response without rate headers")
+	}
+
+	h.Set("X-RateLimit-Limit", "5000")
+	h.Set("X-RateLimit-Remaining", "42")
+	h.Set("X-RateLimit-Used", "4958")
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(testNow.Unix(), 10))
+	rate, ok := parseRate(h)
+	want := Rate{Resource: "core", Limit: 5000, Remaining: 42, Used: 4958, Reset: testNow}
+	if !ok || rate != want {
+		t.Errorf("parseRate() = %+v, %v, want %+v", rate, ok, want)
+	}
+	if !rate.Low() {
+		t.Error("42 of 5000 is not reported as low")
+	}
+
+	h.Set("X-RateLimit-Resource", "search")
+	if rate, _ := parseRate(h); rate.Resource != "search" {
+		t.Errorf("Resource = %q, want search", rate.Resource)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	fakeClock(t)
+	reset := func(in time.Duration) string {
+		return strconv.FormatInt(testNow.Add(in).Unix(), 10)
+	}
+	tests := []struct {
+		name    string
+		status  int
+		header  map[string]string
+		body    string
+		attempt int
+		delay   time.Duration
+		retry   bool
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "plain forbidden", status: http.StatusForbidden, body: `{"message":"Must have admin rights"}`},
+		{name: "retry after", status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "7"}, delay: 7 * time.Second, retry: true},
+		{name: "retry after too long", status: http.StatusForbidden, header: map[string]string{"Retry-After": "120"}, delay: 120 * time.Second},
+		{name: "secondary first attempt", status: http.StatusForbidden, body: "You have exceeded a secondary rate limit", delay: 15 * time.Second, retry: true},
+		{name: "secondary doubles", status: http.StatusForbidden, body: "secondary rate limit", attempt: 2, delay: 60 * time.Second, retry: true},
+		{name: "secondary past the cap", status: http.StatusForbidden, body: "secondary rate limit", attempt: 3, delay: 120 * time.Second},
+		{
+			name:   "quota resets soon",
+			status: http.StatusForbidden,
+			header: map[string]string{"X-RateLimit-Limit": "60", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset(3 * time.Second)},
+			delay:  4 * time.Second,
+			retry:  true,
+		},
+		{
+			name:   "quota resets later",
+			status: http.StatusForbidden,
+			header: map[string]string{"X-RateLimit-Limit": "60", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset(time.Hour)},
+			delay:  time.Hour + time.Second,
+		},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+		for k, v := range tt.header {
+			resp.Header.Set(k, v)
+		}
+		delay, retry := retryDelay(resp, []byte(tt.body), tt.attempt)
+		if retry != tt.retry || (tt.delay != 0 && delay != tt.delay) {
+			t.Errorf("%s: retryDelay() = %v, %v, want %v, %v", tt.name, delay, retry, tt.delay, tt.retry)
+		}
+	}
+}
+
+func TestBackoffGivesUpAfterMaxRetries(t *testing.T) {
+	slept := fakeClock(t)
+	requests := 0
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "2")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message":"slow down"}`))
+	})
+
+	_, err := c.GetRepo("octo", "repo")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Body.Message != "slow down" || apiErr.Rate == nil {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if requests != maxRetries+1 {
+		t.Errorf("made %d requests, want %d", requests, maxRetries+1)
+	}
+	want := []time.Duration{2 * time.Second, 2 * time.Second, 2 * time.Second}
+	if !reflect.DeepEqual(*slept, want) {
+		t.Errorf("slept %v, want %v", *slept, want)
+	}
+	if rate, ok := c.Rate("core"); !ok || rate.Remaining != 4000 {
+		t.Errorf("recorded rate = %+v, %v", rate, ok)
+	}
+}
+
+func TestBackoffRetriesSecondaryLimit(t *testing.T) {
+	slept := fakeClock(t)
+	var bodies []string
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"You have exceeded a secondary rate limit"}`))
+			return
+		}
+		w.Write([]byte(`{"name":"renamed"}`))
+	})
+
+	name := "renamed"
+	repo, err := c.UpdateRepo(Repository{Name: "repo", Owner: Owner{Login: "octo"}}, RepoUpdate{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Name != "renamed" {
+		t.Errorf("repo = %+v", repo)
+	}
+	if len(bodies) != 3 || bodies[2] != bodies[0] || !strings.Contains(bodies[0], "renamed") {
+		t.Errorf("request bodies = %q, want the same body three times", bodies)
+	}
+	want := []time.Duration{15 * time.Second, 30 * time.Second}
+	if !reflect.DeepEqual(*slept, want) {
+		t.Errorf("slept %v, want %v", *slept, want)
+	}
+}
+
+func TestBackoffSkipsLongWaits(t *testing.T) {
+	slept := fakeClock(t)
+	requests := 0
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(testNow.Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	})
+
+	_, err := c.GetRepo("octo", "repo")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+	if requests != 1 || len(*slept) != 0 {
+		t.Errorf("made %d requests and slept %v, want one request and no wait", requests, *slept)
+	}
+}
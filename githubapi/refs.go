@@ -0,0 +1,152 @@
+package githubapi
+
+import (
+	"fmt"
+	"time"
+)
+
+type RefCommit struct {
+	SHA string `json:"sha"`
+	URL string `json:"url"`
+}
+
+type Branch struct {
+	Name      string    `json:"name"`
+	Commit    RefCommit `json:"commit"`
+	Protected bool      `json:"protected"`
+}
+
+type Tag struct {
+	Name       string    `json:"name"`
+	Commit     RefCommit `json:"commit"`
+	ZipballURL string    `json:"zipball_url"`
+	TarballURL string    `json:"tarball_url"`
+}
+
+func (c *Client) ListBranches(repo Repository, opts ListOptions) ([]Branch, *Response, error) {
+	v := make(map[string][]string)
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/branches", repo.Owner.Login, repo.Name), v)
+
+	var branches []Branch
+	resp, err := c.get(path, &branches)
+	if err != nil {
+		return nil, resp, err
+	}
+	return branches, resp, nil
+}
+
+func (c *Client) ListTags(repo Repository, opts ListOptions) ([]Tag, *Response, error) {
+	v := make(map[string][]string)
+	opts.apply(v)
+	path := withQuery(fmt.Sprintf("repos/%s/%s/tags", repo.Owner.Login, repo.Name), v)
+
+	var tags []Tag
+	resp, err := c.get(path, &tags)
+	if err != nil {
+		return nil, resp, err
+	}
+	return tags, resp, nil
+}
+
+const refCommitsQuery = `query($owner: String!, $name: String!, $prefix: String!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    refs(refPrefix: $prefix, first: 100, after: $after) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        target {
+          ... on Commit { ...commitFields }
+          ... on Tag { target { ... on Commit { ...commitFields } } }
+        }
+      }
+    }
+  }
+}
+
+fragment commitFields on Commit {
+  oid
+  messageHeadline
+  author { name date user { login } }
+}`
+
+// MaxRefs bounds how many branches or tags are listed for one repository.
+const MaxRefs = 1000
+
+type refCommitNode struct {
+	OID             string `json:"oid"`
+	MessageHeadline string `json:"messageHeadline"`
+	Author          struct {
+		Name string    `json:"name"`
+		Date time.Time `json:"date"`
+		User *struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"author"`
+	// Target is set instead of the fields above for annotated tags.
+	Target *refCommitNode `json:"target"`
+}
+
+// GetRefCommits returns the commit each branch (or each tag, with tags set)
+// points at, keyed by ref name, in a single GraphQL round trip per hundred
+// refs. Only the SHA, headline and author of each commit are filled in.
+func (c *Client) GetRefCommits(repo Repository, tags bool) (map[string]CommitItem, error) {
+	prefix := "refs/heads/"
+	if tags {
+		prefix = "refs/tags/"
+	}
+
+	commits := make(map[string]CommitItem)
+	var after *string
+	for len(commits) < MaxRefs {
+		var data struct {
+			Repository *struct {
+				Refs struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []struct {
+						Name   string        `json:"name"`
+						Target refCommitNode `json:"target"`
+					} `json:"nodes"`
+				} `json:"refs"`
+			} `json:"repository"`
+		}
+		vars := map[string]any{"owner": repo.Owner.Login, "name": repo.Name, "prefix": prefix, "after": after}
+		if err := c.graphql(refCommitsQuery, vars, &data); err != nil {
+			return nil, err
+		}
+		if data.Repository == nil {
+			return nil, &APIError{Kind: ErrNotFound, Body: ErrorBody{Message: "no such repository: " + repo.FullName}}
+		}
+
+		for _, n := range data.Repository.Refs.Nodes {
+			node := n.Target
+			if node.Target != nil {
+				node = *node.Target
+			}
+			if node.OID == "" {
+				continue
+			}
+			item := CommitItem{
+				SHA: node.OID,
+				Commit: Commit{
+					Message: node.MessageHeadline,
+					Author:  CommitAuthor{Name: node.Author.Name, Date: node.Author.Date},
+				},
+			}
+			if node.Author.User != nil {
+				item.Author = &Owner{Login: node.Author.User.Login}
+			}
+			commits[n.Name] = item
+		}
+
+		page := data.Repository.Refs.PageInfo
+		if !page.HasNextPage {
+			break
+		}
+		after = &page.EndCursor
+	}
+	return commits, nil
+}
@@ -0,0 +1,21 @@
+package githubapi
+
+// ListGitignoreTemplates returns the names of the .gitignore templates
+// GitHub can add to a new repository, such as "Go" or "Node".
+func (c *Client) ListGitignoreTemplates() ([]string, error) {
+	var names []string
+	if _, err := c.get("gitignore/templates", &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// ListLicenses returns the commonly used licenses. Their keys are what
+// RepoRequest.LicenseTemplate takes.
+func (c *Client) ListLicenses() ([]License, error) {
+	var licenses []License
+	if _, err := c.get("licenses", &licenses); err != nil {
+		return nil, err
+	}
+	return licenses, nil
+}
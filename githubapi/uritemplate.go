@@ -0,0 +1,72 @@
+package githubapi
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var templateExpr = regexp.MustCompile(`\{([+#/?&]?)([^}]+)\}`)
+
+// expandURL expands the RFC 6570 templates GitHub puts in hypermedia fields,
+// such as the "{/sha}" at the end of commits_url. Variables missing from
+// vars expand to nothing, as the RFC specifies.
+func expandURL(tmpl string, vars map[string]string) string {
+	return templateExpr.ReplaceAllStringFunc(tmpl, func(expr string) string {
+		m := templateExpr.FindStringSubmatch(expr)
+		op, names := m[1], strings.Split(m[2], ",")
+
+		var parts []string
+		for _, name := range names {
+			value, ok := vars[name]
+			if !ok {
+				continue
+			}
+			switch op {
+			case "?", "&":
+				parts = append(parts, name+"="+url.QueryEscape(value))
+			case "+", "#":
+				parts = append(parts, reservedEscape(value))
+			default:
+				parts = append(parts, url.PathEscape(value))
+			}
+		}
+		if len(parts) == 0 {
+			return ""
+		}
+
+		switch op {
+		case "/":
+			return "/" + strings.Join(parts, "/")
+		case "?", "&":
+			return op + strings.Join(parts, "&")
+		case "#":
+			return "#" + strings.Join(parts, ",")
+		}
+		return strings.Join(parts, ",")
+	})
+}
+
+// reservedEscape encodes value for the + and # operators, which keep the
+// reserved characters such as "/" and existing percent escapes as they are.
+func reservedEscape(value string) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			strings.IndexByte("-._~:/?#[]@!$&'()*+,;=", c) >= 0:
+			sb.WriteByte(c)
+		case c == '%' && i+2 < len(value) && isHex(value[i+1]) && isHex(value[i+2]):
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
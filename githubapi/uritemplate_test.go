@@ -0,0 +1,66 @@
+package githubapi
+
+import "testing"
+
+func TestExpandURL(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		vars map[string]string
+		want string
+	}{
+		{
+			tmpl: "https://api.github.com/repos/o/r/commits{/sha}",
+			vars: map[string]string{"sha": "abc123"},
+			want: "https://api.github.com/repos/o/r/commits/abc123",
+		},
+		{
+			tmpl: "https://api.github.com/repos/o/r/commits{/sha}",
+			want: "https://api.github.com/repos/o/r/commits",
+		},
+		{
+			tmpl: "https://api.github.com/repos/o/r/contents/{+path}",
+			vars: map[string]string{"path": "a/b c"},
+			want: "https://api.github.com/repos/o/r/contents/a/b%20c",
+		},
+		{
+			tmpl: "/repos/o/r/contents/{+path}",
+			vars: map[string]string{"path": "docs/%C3%A9t%C3%A9.md"},
+			want: "/repos/o/r/contents/docs/%C3%A9t%C3%A9.md",
+		},
+		{
+			tmpl: "/repos/o/r/contents/{+path}",
+			vars: map[string]string{"path": "notes/100%"},
+			want: "/repos/o/r/contents/notes/100%25",
+		},
+		{
+			tmpl: "/page{#section}",
+			vars: map[string]string{"section": "a b/c"},
+			want: "/page#a%20b/c",
+		},
+		{
+			tmpl: "https://uploads.github.com/releases/1/assets{?name,label}",
+			vars: map[string]string{"name": "x y.zip", "label": "l"},
+			want: "https://uploads.github.com/releases/1/assets?name=x+y.zip&label=l",
+		},
+		{
+			tmpl: "https://uploads.github.com/releases/1/assets{?name,label}",
+			vars: map[string]string{"label": "l"},
+			want: "https://uploads.github.com/releases/1/assets?label=l",
+		},
+		{
+			tmpl: "/search{?q}{&page}",
+			vars: map[string]string{"q": "go", "page": "2"},
+			want: "/search?q=go&page=2",
+		},
+		{
+			tmpl: "/users/{user}/repos",
+			vars: map[string]string{"user": "octo/cat"},
+			want: "/users/octo%2Fcat/repos",
+		},
+	}
+	for _, tt := range tests {
+		if got := expandURL(tt.tmpl, tt.vars); got != tt.want {
+			t.Errorf("expandURL(%q, %v) = %q, want %q", tt.tmpl, tt.vars, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package githubapi
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type User struct {
+	Login     string `json:"login"`
+	ID        int64  `json:"id"`
+	AvatarURL string `json:"avatar_url"`
+	HTMLURL   string `json:"html_url"`
+	ReposURL  string `json:"repos_url"`
+	Type      string `json:"type"`
+
+	Name     string `json:"name"`
+	Company  string `json:"company"`
+	Blog     string `json:"blog"`
+	Location string `json:"location"`
+	Email    string `json:"email"`
+	Bio      string `json:"bio"`
+
+	PublicRepos int `json:"public_repos"`
+	Followers   int `json:"followers"`
+	Following   int `json:"following"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *Client) GetUser(login string) (User, error) {
+	var user User
+	if _, err := c.get(fmt.Sprintf("users/%s", url.PathEscape(login)), &user); err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
+
+// GetAuthenticatedUser returns the user the token belongs to.
+func (c *Client) GetAuthenticatedUser() (User, error) {
+	if c.Token == "" {
+		return User{}, &APIError{Kind: ErrUnauthorized, Body: ErrorBody{Message: "no personal access token is configured (PAT in ~/.remgit.conf)"}}
+	}
+	var user User
+	if _, err := c.get("user", &user); err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
+
+func (u User) IsOrganization() bool {
+	return u.Type == "Organization"
+}
+
+func (c *Client) GetRepo(owner, name string) (Repository, error) {
+	var repo Repository
+	path := fmt.Sprintf("repos/%s/%s", url.PathEscape(owner), url.PathEscape(name))
+	if _, err := c.get(path, &repo); err != nil {
+		return Repository{}, err
+	}
+	return repo, nil
+}
+
+// ListOrgRepos lists every repository of an organisation the token can see,
+// including private and internal ones for members.
+func (c *Client) ListOrgRepos(org string, opts ListOptions) ([]Repository, *Response, error) {
+	v := url.Values{}
+	v.Set("type", "all")
+	v.Set("sort", "pushed")
+	opts.apply(v)
+
+	var repos []Repository
+	resp, err := c.get(withQuery(fmt.Sprintf("orgs/%s/repos", url.PathEscape(org)), v), &repos)
+	if err != nil {
+		return nil, resp, err
+	}
+	return repos, resp, nil
+}
+
+// ListMyOrgs lists the organisations the authenticated user belongs to.
+func (c *Client) ListMyOrgs(opts ListOptions) ([]Owner, *Response, error) {
+	if c.Token == "" {
+		return nil, nil, &APIError{Kind: ErrUnauthorized, Body: ErrorBody{Message: "listing your organisations requires a personal access token (PAT in ~/.remgit.conf)"}}
+	}
+	v := url.Values{}
+	opts.apply(v)
+
+	var orgs []Owner
+	resp, err := c.get(withQuery("user/orgs", v), &orgs)
+	if err != nil {
+		return nil, resp, err
+	}
+	return orgs, resp, nil
+}
+
+// CountOrgMembers counts public members by asking for one per page and
+// reading the last page number off the Link header.
+func (c *Client) CountOrgMembers(org string) (int, error) {
+	var members []Owner
+	path := fmt.Sprintf("orgs/%s/public_members?per_page=1", url.PathEscape(org))
+	resp, err := c.get(path, &members)
+	if err != nil {
+		return 0, err
+	}
+	if resp.LastPage > 0 {
+		return resp.LastPage, nil
+	}
+	return len(members), nil
+}
+
+const (
+	AffiliationOwner        = "owner"
+	AffiliationCollaborator = "collaborator"
+	AffiliationOrgMember    = "organization_member"
+)
+
+type RepoListOptions struct {
+	// Affiliation is a comma separated list of AffiliationOwner,
+	// AffiliationCollaborator and AffiliationOrgMember; empty means all.
+	Affiliation string
+	// Visibility is all, public or private.
+	Visibility string
+	// Sort is created, updated, pushed or full_name.
+	Sort      string
+	Direction string
+
+	ListOptions
+}
+
+func (o RepoListOptions) values() url.Values {
+	v := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("affiliation", o.Affiliation)
+	set("visibility", o.Visibility)
+	set("sort", o.Sort)
+	set("direction", o.Direction)
+	o.ListOptions.apply(v)
+	return v
+}
+
+// ListMyRepos lists the repositories the authenticated user can access:
+// their own, those they collaborate on and those of their organisations,
+// private ones included.
+func (c *Client) ListMyRepos(opts RepoListOptions) ([]Repository, *Response, error) {
+	if c.Token == "" {
+		return nil, nil, &APIError{Kind: ErrUnauthorized, Body: ErrorBody{Message: "listing your repositories requires a personal access token (PAT in ~/.remgit.conf)"}}
+	}
+
+	var repos []Repository
+	resp, err := c.get(withQuery("user/repos", opts.values()), &repos)
+	if err != nil {
+		return nil, resp, err
+	}
+	return repos, resp, nil
+}
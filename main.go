@@ -2,9 +2,12 @@ package main
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/blacktop/go-termimg"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/chirag-diwan/RemGit/config"
+	"github.com/chirag-diwan/RemGit/githubapi"
 	"github.com/chirag-diwan/RemGit/tui"
 )
 
@@ -12,7 +15,16 @@ func main() {
 	tokens := config.Lexer("/home/chirag/.remgit.conf")
 	praser := config.NewPraser(tokens)
 	obj := praser.Prase()
-	Manager := tui.NewManager(obj)
+	client, err := githubapi.NewClient(obj)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	// termimg probes the terminal on first use; do it before bubbletea
+	// takes over stdin so the replies are not read as key presses.
+	termimg.QueryTerminalFeatures()
+
+	Manager := tui.NewManager(obj, client)
 	p := tea.NewProgram(Manager, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		panic(err)
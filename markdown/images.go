@@ -0,0 +1,183 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Image is a README image swapped out for Token by ExtractImages.
+type Image struct {
+	Token string
+	Dest  string
+	Alt   string
+}
+
+var (
+	htmlImgRe  = regexp.MustCompile(`(?is)<img\s[^>]*>`)
+	htmlSrcRe  = regexp.MustCompile(`(?is)\ssrc\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	htmlAltRe  = regexp.MustCompile(`(?is)\salt\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	imageToken = regexp.MustCompile(`REMGITIMG\d{4}X`)
+)
+
+func newImage(n int, dest, alt string) Image {
+	return Image{Token: fmt.Sprintf("REMGITIMG%04dX", n), Dest: dest, Alt: alt}
+}
+
+func htmlAttr(re *regexp.Regexp, tag string) string {
+	m := re.FindStringSubmatch(tag)
+	if m == nil {
+		return ""
+	}
+	return m[1] + m[2]
+}
+
+// span is a byte range of the markdown source.
+type span struct {
+	start, end int
+}
+
+func (s span) contains(i int) bool {
+	return i >= s.start && i < s.end
+}
+
+// codeSpans returns the byte ranges of md's code blocks and code spans,
+// where image syntax is shown as text rather than rendered.
+func codeSpans(md string) []span {
+	_, root := parse(md)
+	var spans []span
+	ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			if lines := n.Lines(); lines.Len() > 0 {
+				spans = append(spans, span{lines.At(0).Start, lines.At(lines.Len() - 1).Stop})
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeSpan:
+			sp := span{-1, -1}
+			for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+				if t, ok := c.(*ast.Text); ok {
+					if sp.start < 0 {
+						sp.start = t.Segment.Start
+					}
+					sp.end = t.Segment.Stop
+				}
+			}
+			if sp.start >= 0 {
+				spans = append(spans, sp)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return spans
+}
+
+// ExtractImages replaces every markdown and HTML image in md with a
+// placeholder token on a line of its own. Glamour leaves the tokens intact,
+// so the rendered images can be spliced back in with SpliceImages. Images
+// inside code blocks and code spans are left as they are.
+func ExtractImages(md string) (string, []Image) {
+	var images []Image
+	code := codeSpans(md)
+	inCode := func(i int) bool {
+		return slices.ContainsFunc(code, func(s span) bool { return s.contains(i) })
+	}
+
+	type replacement struct {
+		span
+		with string
+	}
+	var replacements []replacement
+	tokens := make(map[string]string)
+
+	seen := make(map[string]bool)
+	for _, dest := range GetPaths(md) {
+		if seen[dest] {
+			continue
+		}
+		seen[dest] = true
+
+		re := regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?` + regexp.QuoteMeta(dest) + `>?(?:\s+(?:"[^"]*"|'[^']*'))?\s*\)`)
+		for _, loc := range re.FindAllStringSubmatchIndex(md, -1) {
+			if inCode(loc[0]) {
+				continue
+			}
+			match := md[loc[0]:loc[1]]
+			token, ok := tokens[match]
+			if !ok {
+				img := newImage(len(images), dest, md[loc[2]:loc[3]])
+				images = append(images, img)
+				token = img.Token
+				tokens[match] = token
+			}
+			replacements = append(replacements, replacement{span{loc[0], loc[1]}, "\n" + token + "\n"})
+		}
+	}
+
+	// Glamour drops raw HTML blocks, so the token needs blank lines around
+	// it to escape the surrounding block.
+	for _, loc := range htmlImgRe.FindAllStringIndex(md, -1) {
+		tag := md[loc[0]:loc[1]]
+		src := htmlAttr(htmlSrcRe, tag)
+		if src == "" || inCode(loc[0]) {
+			continue
+		}
+		img := newImage(len(images), src, htmlAttr(htmlAltRe, tag))
+		images = append(images, img)
+		replacements = append(replacements, replacement{span{loc[0], loc[1]}, "\n\n" + img.Token + "\n\n"})
+	}
+
+	slices.SortFunc(replacements, func(a, b replacement) int { return a.start - b.start })
+	var sb strings.Builder
+	last := 0
+	for _, r := range replacements {
+		if r.start < last {
+			continue
+		}
+		sb.WriteString(md[last:r.start])
+		sb.WriteString(r.with)
+		last = r.end
+	}
+	sb.WriteString(md[last:])
+
+	return sb.String(), images
+}
+
+// SpliceImages replaces each token in rendered with its block from blocks.
+// Text sharing a line with a token, such as a badge's link, is kept on its
+// own line around the block.
+func SpliceImages(rendered string, blocks map[string]string) string {
+	lines := strings.Split(rendered, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		locs := imageToken.FindAllStringIndex(line, -1)
+		if locs == nil {
+			out = append(out, line)
+			continue
+		}
+
+		keep := func(s string) {
+			if strings.TrimSpace(ansi.Strip(s)) != "" {
+				out = append(out, s+"\x1b[0m")
+			}
+		}
+		start := 0
+		for _, loc := range locs {
+			keep(line[start:loc[0]])
+			if block, ok := blocks[line[loc[0]:loc[1]]]; ok {
+				out = append(out, block)
+			}
+			start = loc[1]
+		}
+		keep(line[start:])
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,68 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractImages(t *testing.T) {
+	md := strings.Join([]string{
+		"# Title",
+		"",
+		"![logo](img/logo.png)",
+		"",
+		"Inline `![logo](img/logo.png)` syntax.",
+		"",
+		"```markdown",
+		"![logo](img/logo.png)",
+		`<img src="img/shot.png">`,
+		"```",
+		"",
+		`<p><img alt="shot" src="img/shot.png"></p>`,
+		"",
+		"    ![indented](img/logo.png)",
+		"",
+		"![spinner](img/spin.gif)",
+	}, "\n")
+
+	out, images := ExtractImages(md)
+
+	if len(images) != 3 {
+		t.Fatalf("got %d images, want 3: %+v", len(images), images)
+	}
+	if images[0].Dest != "img/logo.png" || images[0].Alt != "logo" {
+		t.Errorf("images[0] = %+v", images[0])
+	}
+	// GIFs are extracted like any other image and drawn as their first frame.
+	if images[1].Dest != "img/spin.gif" || images[1].Alt != "spinner" {
+		t.Errorf("images[1] = %+v", images[1])
+	}
+	if images[2].Dest != "img/shot.png" || images[2].Alt != "shot" {
+		t.Errorf("images[2] = %+v", images[2])
+	}
+
+	for _, img := range images {
+		if strings.Count(out, img.Token) != 1 {
+			t.Errorf("token %s appears %d times, want 1", img.Token, strings.Count(out, img.Token))
+		}
+	}
+	for _, kept := range []string{
+		"Inline `![logo](img/logo.png)` syntax.",
+		"```markdown\n![logo](img/logo.png)\n<img src=\"img/shot.png\">\n```",
+		"    ![indented](img/logo.png)",
+	} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("output lost %q:\n%s", kept, out)
+		}
+	}
+}
+
+func TestExtractImagesRepeated(t *testing.T) {
+	out, images := ExtractImages("![a](x.png) and ![a](x.png)")
+	if len(images) != 1 {
+		t.Fatalf("got %d images, want 1", len(images))
+	}
+	if strings.Count(out, images[0].Token) != 2 {
+		t.Errorf("want the token twice, got %q", out)
+	}
+}
@@ -0,0 +1,168 @@
+package markdown
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Link is a link in the document together with the line of the rendered
+// output it appears on, or -1 when it could not be located.
+type Link struct {
+	Text string
+	Dest string
+	Line int
+}
+
+// Heading is an outline entry. ID is the anchor GitHub generates for it and
+// Line its line in the rendered output.
+type Heading struct {
+	Level int
+	Text  string
+	ID    string
+	Line  int
+}
+
+func nodeText(n ast.Node, source []byte) string {
+	var sb strings.Builder
+	ast.Walk(n, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := node.(type) {
+		case *ast.Text:
+			sb.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(sb.String())
+}
+
+// IsRelative reports whether dest points into the repository rather than at
+// another site or an anchor in the same document.
+func IsRelative(dest string) bool {
+	if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "//") {
+		return false
+	}
+	u, err := url.Parse(dest)
+	return err == nil && u.Scheme == ""
+}
+
+// RewriteLinks replaces every relative link destination in md, inline or in
+// a reference definition, with the result of resolve.
+func RewriteLinks(md string, resolve func(dest string) string) string {
+	source, root := parse(md)
+
+	dests := make(map[string]bool)
+	ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := node.(*ast.Link); ok && entering {
+			if dest := string(link.Destination); IsRelative(dest) {
+				dests[dest] = true
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	md = string(source)
+	for dest := range dests {
+		resolved := resolve(dest)
+		escaped := strings.ReplaceAll(resolved, "$", "$$")
+
+		inline := regexp.MustCompile(`\]\(\s*<?` + regexp.QuoteMeta(dest) + `>?([\s)])`)
+		md = inline.ReplaceAllString(md, "]("+escaped+"${1}")
+
+		definition := regexp.MustCompile(`(?m)^( {0,3}\[[^\]]+\]:\s*)<?` + regexp.QuoteMeta(dest) + `>?(\s|$)`)
+		md = definition.ReplaceAllString(md, "${1}"+escaped+"${2}")
+	}
+	return md
+}
+
+// locator finds needles in rendered output in document order, so repeated
+// text resolves to successive occurrences rather than the first one.
+type locator struct {
+	lines []string
+	next  int
+}
+
+func newLocator(rendered string) *locator {
+	lines := strings.Split(ansi.Strip(rendered), "\n")
+	return &locator{lines: lines}
+}
+
+func (l *locator) find(needles ...string) int {
+	for _, needle := range needles {
+		if needle == "" {
+			continue
+		}
+		for i := l.next; i < len(l.lines); i++ {
+			if strings.Contains(l.lines[i], needle) {
+				l.next = i
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Links lists the links in md, locating each in rendered by its URL, which
+// glamour prints after the link text, or by the text for anchors it hides.
+func Links(md, rendered string) []Link {
+	source, root := parse(md)
+	loc := newLocator(rendered)
+
+	var links []Link
+	ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		var link Link
+		switch n := node.(type) {
+		case *ast.Link:
+			link = Link{Text: nodeText(n, source), Dest: string(n.Destination)}
+		case *ast.AutoLink:
+			link = Link{Text: string(n.Label(source)), Dest: string(n.URL(source))}
+		default:
+			return ast.WalkContinue, nil
+		}
+		link.Line = loc.find(link.Dest, link.Text)
+		links = append(links, link)
+		return ast.WalkSkipChildren, nil
+	})
+	return links
+}
+
+// Outline lists the headings in md with their anchors and rendered lines.
+// A heading that cannot be located inherits the line of the one before it.
+func Outline(md, rendered string) []Heading {
+	source, root := parse(md)
+	loc := newLocator(rendered)
+
+	var headings []Heading
+	ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := node.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		heading := Heading{Level: h.Level, Text: nodeText(h, source)}
+		if id, ok := h.AttributeString("id"); ok {
+			if b, ok := id.([]byte); ok {
+				heading.ID = string(b)
+			}
+		}
+		heading.Line = loc.find(heading.Text)
+		if heading.Line < 0 {
+			heading.Line = loc.next
+		}
+		headings = append(headings, heading)
+		return ast.WalkSkipChildren, nil
+	})
+	return headings
+}
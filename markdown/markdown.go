@@ -12,7 +12,7 @@ import (
 	"github.com/yuin/goldmark/text"
 )
 
-func GetPaths(md string) []string {
+func parse(md string) ([]byte, ast.Node) {
 	gd := goldmark.New(
 		goldmark.WithExtensions(
 			extension.GFM,
@@ -21,8 +21,13 @@ func GetPaths(md string) []string {
 			parser.WithAutoHeadingID(),
 		),
 	)
+	source := []byte(md)
+	return source, gd.Parser().Parse(text.NewReader(source))
+}
+
+func GetPaths(md string) []string {
 	var paths []string
-	root := gd.Parser().Parse(text.NewReader([]byte(md)))
+	_, root := parse(md)
 	ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
 		if !entering {
 			return ast.WalkContinue, nil
@@ -31,11 +36,7 @@ func GetPaths(md string) []string {
 		if !ok {
 			return ast.WalkContinue, nil
 		}
-		dest := string(img.Destination)
-		if strings.Contains(dest, ".gif") {
-			return ast.WalkContinue, nil
-		}
-		paths = append(paths, dest)
+		paths = append(paths, string(img.Destination))
 
 		return ast.WalkContinue, nil
 
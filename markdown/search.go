@@ -0,0 +1,156 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Match is a search hit in rendered output. Start and End are byte offsets
+// into the line with its escape sequences removed.
+type Match struct {
+	Line  int
+	Start int
+	End   int
+}
+
+const (
+	matchStyle        = "\x1b[7m"
+	currentMatchStyle = "\x1b[1;30;48;5;214m"
+	resetStyle        = "\x1b[0m"
+)
+
+// escapeLen returns the length of the escape sequence at the start of s,
+// covering CSI sequences as well as the string sequences (OSC, DCS, APC)
+// that carry hyperlinks and sixel or kitty images.
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', '_', '^', 'X':
+		for i := 2; i < len(s); i++ {
+			if s[i] == 0x07 {
+				return i + 1
+			}
+			if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+func plainText(line string) string {
+	var sb strings.Builder
+	for i := 0; i < len(line); {
+		if line[i] == 0x1b {
+			i += escapeLen(line[i:])
+			continue
+		}
+		sb.WriteByte(line[i])
+		i++
+	}
+	return sb.String()
+}
+
+// Search finds every case-insensitive occurrence of query in rendered.
+func Search(rendered, query string) []Match {
+	if query == "" {
+		return nil
+	}
+	re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+
+	var matches []Match
+	for i, line := range strings.Split(rendered, "\n") {
+		for _, loc := range re.FindAllStringIndex(plainText(line), -1) {
+			matches = append(matches, Match{Line: i, Start: loc[0], End: loc[1]})
+		}
+	}
+	return matches
+}
+
+// Highlight marks matches in rendered, with the one at index current
+// standing out. Existing escape sequences are left intact: the highlight is
+// re-applied after any sequence inside a match and the line's own styling
+// is restored after it.
+func Highlight(rendered string, matches []Match, current int) string {
+	if len(matches) == 0 {
+		return rendered
+	}
+	lines := strings.Split(rendered, "\n")
+
+	byLine := make(map[int][]int)
+	for i, m := range matches {
+		byLine[m.Line] = append(byLine[m.Line], i)
+	}
+	for n, idx := range byLine {
+		if n < len(lines) {
+			lines[n] = highlightLine(lines[n], matches, idx, current)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func highlightLine(line string, matches []Match, idx []int, current int) string {
+	var (
+		out    strings.Builder
+		active strings.Builder
+		plain  int
+		next   int
+		in     bool
+		style  string
+	)
+	for i := 0; i < len(line); {
+		if line[i] == 0x1b {
+			n := escapeLen(line[i:])
+			seq := line[i : i+n]
+			out.WriteString(seq)
+			if strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+				if seq == resetStyle || seq == "\x1b[m" {
+					active.Reset()
+				} else {
+					active.WriteString(seq)
+				}
+			}
+			if in {
+				out.WriteString(style)
+			}
+			i += n
+			continue
+		}
+
+		if !in && next < len(idx) && plain >= matches[idx[next]].Start {
+			style = matchStyle
+			if idx[next] == current {
+				style = currentMatchStyle
+			}
+			out.WriteString(style)
+			in = true
+		}
+
+		_, size := utf8.DecodeRuneInString(line[i:])
+		out.WriteString(line[i : i+size])
+		i += size
+		plain += size
+
+		if in && plain >= matches[idx[next]].End {
+			out.WriteString(resetStyle)
+			out.WriteString(active.String())
+			in = false
+			next++
+		}
+	}
+	if in {
+		out.WriteString(resetStyle)
+	}
+	return out.String()
+}
@@ -0,0 +1,563 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+type commitsMsg struct {
+	Repo     string
+	Request  int
+	Page     int
+	NextPage int
+	Commits  []githubapi.CommitItem
+	Err      error
+}
+
+type commitDetailMsg struct {
+	Repo   string
+	SHA    string
+	Commit githubapi.CommitItem
+	Err    error
+}
+
+const (
+	commitsList int = iota
+	commitsFilter
+	commitsDetail
+)
+
+// commitsChrome is the height taken by the header and footer around the
+// commit list.
+const commitsChrome = 6
+
+type CommitsPageModel struct {
+	Width  int
+	Height int
+
+	repo     githubapi.Repository
+	CameFrom int
+	filters  filterPanel
+	opts     githubapi.CommitListOptions
+
+	commits     []githubapi.CommitItem
+	cursor      int
+	windowStart int
+	listPager
+
+	// marked is the SHA chosen as the base of a comparison.
+	marked string
+
+	mode          int
+	detail        *githubapi.CommitItem
+	detailErr     error
+	loadingDetail bool
+	fileCursor    int
+	detailView    viewport.Model
+
+	spinner spinner.Model
+	client  *githubapi.Client
+}
+
+func NewCommitsPageModel(client *githubapi.Client, repo githubapi.Repository, ref string, camefrom int) CommitsPageModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	filters := newCommitFilterPanel(ref)
+	opts, _ := filters.commitOptions()
+
+	m := CommitsPageModel{
+		repo:       repo,
+		CameFrom:   camefrom,
+		filters:    filters,
+		opts:       opts,
+		mode:       commitsList,
+		detailView: viewport.New(0, 0),
+		spinner:    s,
+		client:     client,
+	}
+	m.restart()
+	return m
+}
+
+func fetchCommitsCmd(client *githubapi.Client, repo githubapi.Repository, opts githubapi.CommitListOptions, request, page int) tea.Cmd {
+	opts.Page = page
+	opts.PerPage = githubapi.DefaultPerPage
+	return func() tea.Msg {
+		commits, resp, err := client.GetCommits(repo, opts)
+		msg := commitsMsg{Repo: repo.FullName, Request: request, Page: page, Commits: commits, Err: err}
+		if resp != nil {
+			msg.NextPage = resp.NextPage
+		}
+		return msg
+	}
+}
+
+func fetchCommitDetailCmd(client *githubapi.Client, repo githubapi.Repository, sha string) tea.Cmd {
+	return func() tea.Msg {
+		commit, err := client.GetCommit(repo, sha)
+		return commitDetailMsg{Repo: repo.FullName, SHA: sha, Commit: commit, Err: err}
+	}
+}
+
+func (m CommitsPageModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, fetchCommitsCmd(m.client, m.repo, m.opts, m.request, 1))
+}
+
+func (m CommitsPageModel) listHeight() int {
+	return max(m.Height-commitsChrome, 3)
+}
+
+func (m *CommitsPageModel) reload() tea.Cmd {
+	m.commits = nil
+	m.cursor = 0
+	m.windowStart = 0
+	request := m.restart()
+	return tea.Batch(m.spinner.Tick, fetchCommitsCmd(m.client, m.repo, m.opts, request, 1))
+}
+
+func (m *CommitsPageModel) maybeLoadMore() tea.Cmd {
+	if !m.wantMore(m.cursor, len(m.commits)) {
+		return nil
+	}
+	return tea.Batch(m.spinner.Tick, fetchCommitsCmd(m.client, m.repo, m.opts, m.request, m.nextPage))
+}
+
+func (m *CommitsPageModel) moveCursor(step int) {
+	if len(m.commits) == 0 {
+		return
+	}
+	m.cursor = max(0, min(m.cursor+step, len(m.commits)-1))
+	if m.cursor < m.windowStart {
+		m.windowStart = m.cursor
+	}
+	if m.cursor >= m.windowStart+m.listHeight() {
+		m.windowStart = m.cursor - m.listHeight() + 1
+	}
+}
+
+func (m CommitsPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.detailView.Width = m.Width
+		m.detailView.Height = max(m.Height-2, 1)
+		if m.detail != nil {
+			m.detailView.SetContent(m.renderDetail())
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading && !m.loadingMore && !m.loadingDetail {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case commitsMsg:
+		if msg.Repo != m.repo.FullName || !m.landed(msg.Request, msg.Page, msg.NextPage, msg.Err) || msg.Err != nil {
+			return m, nil
+		}
+		if msg.Page == 1 {
+			m.commits = msg.Commits
+		} else {
+			m.commits = append(m.commits, msg.Commits...)
+		}
+		return m, nil
+
+	case commitDetailMsg:
+		if msg.Repo != m.repo.FullName || m.mode != commitsDetail || m.detail == nil || m.detail.SHA != msg.SHA {
+			return m, nil
+		}
+		m.loadingDetail = false
+		m.detailErr = msg.Err
+		if msg.Err == nil {
+			commit := msg.Commit
+			m.detail = &commit
+		}
+		m.detailView.SetContent(m.renderDetail())
+		return m, nil
+
+	case browserMsg:
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case commitsFilter:
+			return m.updateFilter(msg)
+		case commitsDetail:
+			return m.updateDetail(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m CommitsPageModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var (
+		cmd    tea.Cmd
+		closed bool
+	)
+	m.filters, cmd, closed = m.filters.Update(msg)
+	if !closed {
+		return m, cmd
+	}
+
+	opts, err := m.filters.commitOptions()
+	if err != nil {
+		m.filters.err = err.Error()
+		return m, nil
+	}
+	m.filters.err = ""
+	m.mode = commitsList
+	m.opts = opts
+	return m, m.reload()
+}
+
+func (m CommitsPageModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		m.moveCursor(1)
+		return m, m.maybeLoadMore()
+	case "k", "up":
+		m.moveCursor(-1)
+	case "d", "ctrl+d":
+		m.moveCursor(m.listHeight() / 2)
+		return m, m.maybeLoadMore()
+	case "u", "ctrl+u":
+		m.moveCursor(-m.listHeight() / 2)
+	case "f":
+		m.mode = commitsFilter
+	case "r":
+		if m.err != nil {
+			return m, m.reload()
+		}
+		if m.moreErr != nil {
+			m.moreErr = nil
+			return m, m.maybeLoadMore()
+		}
+	case "enter":
+		if len(m.commits) == 0 {
+			return m, nil
+		}
+		commit := m.commits[m.cursor]
+		m.mode = commitsDetail
+		m.detail = &commit
+		m.detailErr = nil
+		m.loadingDetail = true
+		m.fileCursor = 0
+		m.detailView.SetContent(m.renderDetail())
+		m.detailView.GotoTop()
+		return m, tea.Batch(m.spinner.Tick, fetchCommitDetailCmd(m.client, m.repo, commit.SHA))
+	case "m":
+		if len(m.commits) > 0 {
+			if sha := m.commits[m.cursor].SHA; m.marked == sha {
+				m.marked = ""
+			} else {
+				m.marked = sha
+			}
+		}
+	case "c":
+		if m.marked == "" || len(m.commits) == 0 || m.commits[m.cursor].SHA == m.marked {
+			return m, nil
+		}
+		base, head := m.marked, m.commits[m.cursor].SHA
+		return m, func() tea.Msg {
+			return NavMsg{to: DiffPage, from: CommitsPage, repodata: m.repo, base: base, head: head}
+		}
+	case "backspace":
+		return m, func() tea.Msg {
+			return NavMsg{to: m.CameFrom, from: CommitsPage, repodata: m.repo, back: true}
+		}
+	}
+	return m, nil
+}
+
+func (m CommitsPageModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	files := 0
+	if m.detail != nil {
+		files = len(m.detail.Files)
+	}
+
+	switch msg.String() {
+	case "backspace", "esc":
+		m.mode = commitsList
+		m.detail = nil
+		return m, nil
+	case "j", "down":
+		if m.fileCursor < files-1 {
+			m.fileCursor++
+		}
+	case "k", "up":
+		if m.fileCursor > 0 {
+			m.fileCursor--
+		}
+	case "d", "ctrl+d":
+		m.detailView.HalfPageDown()
+		return m, nil
+	case "u", "ctrl+u":
+		m.detailView.HalfPageUp()
+		return m, nil
+	case "enter":
+		if m.loadingDetail || files == 0 {
+			return m, nil
+		}
+		sha, file := m.detail.SHA, m.detail.Files[m.fileCursor].Filename
+		return m, func() tea.Msg {
+			return NavMsg{to: DiffPage, from: CommitsPage, repodata: m.repo, head: sha, path: file}
+		}
+	case "o":
+		if m.detail != nil && m.detail.HTMLURL != "" {
+			return m, openBrowserCmd(m.detail.HTMLURL)
+		}
+		return m, nil
+	case "r":
+		if m.detailErr != nil {
+			m.detailErr = nil
+			m.loadingDetail = true
+			m.detailView.SetContent(m.renderDetail())
+			return m, tea.Batch(m.spinner.Tick, fetchCommitDetailCmd(m.client, m.repo, m.detail.SHA))
+		}
+		return m, nil
+	}
+
+	m.detailView.SetContent(m.renderDetail())
+	m.followFileCursor()
+	return m, nil
+}
+
+// followFileCursor scrolls the detail view so the selected file stays on
+// screen.
+func (m *CommitsPageModel) followFileCursor() {
+	if m.detail == nil || len(m.detail.Files) == 0 {
+		return
+	}
+	line := lipgloss.Height(m.renderDetailHeader()) + 1 + m.fileCursor
+	if line < m.detailView.YOffset {
+		m.detailView.SetYOffset(line)
+	} else if line >= m.detailView.YOffset+m.detailView.Height {
+		m.detailView.SetYOffset(line - m.detailView.Height + 1)
+	}
+}
+
+func verificationBadge(v githubapi.Verification) string {
+	switch {
+	case v.Verified:
+		return lipgloss.NewStyle().Foreground(special).Render("✓ verified")
+	case v.Reason != "" && v.Reason != "unsigned":
+		return lipgloss.NewStyle().Foreground(warning).Render("✗ unverified")
+	}
+	return ""
+}
+
+func commitAuthor(c githubapi.CommitItem) string {
+	if c.Author != nil && c.Author.Login != "" {
+		return c.Author.Login
+	}
+	return c.Commit.Author.Name
+}
+
+func (m CommitsPageModel) renderHeader() string {
+	title := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(m.repo.FullName + " · commits")
+
+	var scope []string
+	ref := m.opts.SHA
+	if ref == "" {
+		ref = readmeRef(m.repo)
+	}
+	scope = append(scope, "on "+ref)
+	if m.opts.Path != "" {
+		scope = append(scope, "touching "+m.opts.Path)
+	}
+	if m.opts.Author != "" {
+		scope = append(scope, "by "+m.opts.Author)
+	}
+	if dates := m.filters.value(filterDate); dates != "" {
+		scope = append(scope, "dated "+dates)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, title, lipgloss.NewStyle().Foreground(subtle).Render(strings.Join(scope, " · ")))
+}
+
+func (m CommitsPageModel) renderRow(i int) string {
+	c := m.commits[i]
+	shaStyle := lipgloss.NewStyle().Foreground(special)
+	metaStyle := lipgloss.NewStyle().Foreground(subtle)
+	msgStyle := lipgloss.NewStyle().Foreground(text)
+	pointer := "  "
+	if i == m.cursor {
+		pointer = "> "
+		msgStyle = msgStyle.Foreground(highlight).Bold(true)
+	}
+	if c.SHA == m.marked {
+		pointer = lipgloss.NewStyle().Foreground(special).Render("◆ ")
+	}
+
+	meta := metaStyle.Render(fmt.Sprintf("%s · %s", commitAuthor(c), formatDate(c.Commit.Author.Date)))
+	badge := verificationBadge(c.Commit.Verification)
+	if badge != "" {
+		badge = " " + badge
+	}
+
+	room := m.Width - lipgloss.Width(meta) - lipgloss.Width(badge) - 14
+	summary := ansi.Truncate(c.Summary(), max(room, 10), "…")
+	return fmt.Sprintf("%s%s %s%s  %s", pointer, shaStyle.Render(c.ShortSHA()), msgStyle.Render(summary), badge, meta)
+}
+
+func (m CommitsPageModel) renderList() string {
+	switch {
+	case m.loading:
+		return fmt.Sprintf("%s Loading commits...", m.spinner.View())
+	case m.err != nil:
+		return renderErrorState(m.err, m.Width-4)
+	case len(m.commits) == 0:
+		return lipgloss.NewStyle().Foreground(subtle).Render("No commits match these filters.")
+	}
+
+	var rows []string
+	end := min(m.windowStart+m.listHeight(), len(m.commits))
+	for i := m.windowStart; i < end; i++ {
+		rows = append(rows, m.renderRow(i))
+	}
+
+	status := fmt.Sprintf("%d commits loaded", len(m.commits))
+	switch {
+	case m.loadingMore:
+		status += fmt.Sprintf(" • %s loading more", m.spinner.View())
+	case m.moreErr != nil:
+		status += " • failed to load more (" + errorTitle(m.moreErr) + "), r to retry"
+	case m.nextPage == 0:
+		status += " • end of history"
+	}
+	rows = append(rows, "", lipgloss.NewStyle().Foreground(subtle).Render(status))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m CommitsPageModel) renderDetailHeader() string {
+	c := m.detail
+	label := lipgloss.NewStyle().Foreground(subtle).Bold(true)
+	value := lipgloss.NewStyle().Foreground(text)
+
+	lines := []string{
+		lipgloss.NewStyle().Foreground(highlight).Bold(true).Render("commit "+c.SHA) + "  " + verificationBadge(c.Commit.Verification),
+		label.Render("Author:    ") + value.Render(fmt.Sprintf("%s <%s> · %s", c.Commit.Author.Name, c.Commit.Author.Email, c.Commit.Author.Date.Format("02 Jan 2006 15:04"))),
+	}
+	if c.Commit.Committer.Email != c.Commit.Author.Email {
+		lines = append(lines, label.Render("Committer: ")+value.Render(fmt.Sprintf("%s <%s> · %s", c.Commit.Committer.Name, c.Commit.Committer.Email, c.Commit.Committer.Date.Format("02 Jan 2006 15:04"))))
+	}
+	if len(c.Parents) > 0 {
+		var parents []string
+		for _, p := range c.Parents {
+			parents = append(parents, githubapi.CommitItem{SHA: p.SHA}.ShortSHA())
+		}
+		lines = append(lines, label.Render("Parents:   ")+value.Render(strings.Join(parents, " ")))
+	}
+	if c.Stats != nil {
+		lines = append(lines, label.Render("Changes:   ")+fmt.Sprintf("%s %s in %d files",
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#43BF6D")).Render(fmt.Sprintf("+%d", c.Stats.Additions)),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#E05252")).Render(fmt.Sprintf("-%d", c.Stats.Deletions)),
+			len(c.Files)))
+	}
+
+	message := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(subtle).
+		Padding(0, 1).
+		Width(max(min(m.Width-4, 100), 20)).
+		Render(strings.TrimRight(c.Commit.Message, "\n"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, append(lines, "", message, "")...)
+}
+
+func fileStatus(status string) string {
+	switch status {
+	case "added":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#43BF6D")).Render("A")
+	case "removed":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#E05252")).Render("D")
+	case "renamed":
+		return lipgloss.NewStyle().Foreground(special).Render("R")
+	}
+	return lipgloss.NewStyle().Foreground(warning).Render("M")
+}
+
+func (m CommitsPageModel) renderDetail() string {
+	if m.detail == nil {
+		return ""
+	}
+	sections := []string{m.renderDetailHeader()}
+
+	switch {
+	case m.loadingDetail:
+		sections = append(sections, fmt.Sprintf("%s Loading changed files...", m.spinner.View()))
+	case m.detailErr != nil:
+		sections = append(sections, renderErrorState(m.detailErr, m.Width-4))
+	case len(m.detail.Files) == 0:
+		sections = append(sections, lipgloss.NewStyle().Foreground(subtle).Render("No files changed."))
+	default:
+		sections = append(sections, lipgloss.NewStyle().Foreground(subtle).Bold(true).Render("Files"))
+		for i, f := range m.detail.Files {
+			name := f.Filename
+			if f.PreviousFilename != "" {
+				name = f.PreviousFilename + " → " + f.Filename
+			}
+			style := lipgloss.NewStyle().Foreground(text)
+			pointer := "  "
+			if i == m.fileCursor {
+				style = style.Foreground(highlight).Bold(true)
+				pointer = "> "
+			}
+			sections = append(sections, fmt.Sprintf("%s%s %s %s %s",
+				pointer,
+				fileStatus(f.Status),
+				lipgloss.NewStyle().Foreground(lipgloss.Color("#43BF6D")).Width(6).Render(fmt.Sprintf("+%d", f.Additions)),
+				lipgloss.NewStyle().Foreground(lipgloss.Color("#E05252")).Width(6).Render(fmt.Sprintf("-%d", f.Deletions)),
+				style.Render(name)))
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m CommitsPageModel) View() string {
+	hint := lipgloss.NewStyle().Foreground(subtle)
+
+	switch m.mode {
+	case commitsDetail:
+		return lipgloss.JoinVertical(lipgloss.Left,
+			m.detailView.View(),
+			"",
+			hint.Render("j/k select file • enter diff • d/u scroll • o open on web • backspace back to list"),
+		)
+	case commitsFilter:
+		return lipgloss.JoinVertical(lipgloss.Left,
+			m.renderHeader(),
+			"",
+			m.filters.View(min(m.Width-4, 60)),
+		)
+	}
+
+	keys := "j/k move • enter details • f filter • m mark as compare base • backspace back"
+	if m.marked != "" {
+		keys = fmt.Sprintf("j/k move • enter details • f filter • c compare %s...selected • m unmark • backspace back", shortRef(m.marked))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.renderHeader(),
+		"",
+		m.renderList(),
+		"",
+		hint.Render(keys),
+	)
+}
+
+func (m CommitsPageModel) editing() bool {
+	return m.mode == commitsFilter && m.filters.editing()
+}
@@ -1,12 +1,19 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/chirag-diwan/RemGit/githubapi"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 const (
@@ -15,28 +22,207 @@ const (
 )
 
 const (
-	fieldRepoName = iota
+	fieldOwner = iota
+	fieldRepoName
 	fieldDescription
+	fieldTemplate
 	fieldPrivate
 	fieldIssues
 	fieldProjects
 	fieldWiki
+	fieldAutoInit
+	fieldGitignore
+	fieldLicense
+	fieldLocalPath
+	fieldPushSSH
 	fieldSubmit
 )
 
+// nameCheckDelay is how long typing has to pause before the name is
+// looked up on GitHub.
+const nameCheckDelay = 400 * time.Millisecond
+
+var invalidRepoChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
 type createResultMsg struct {
-	Success bool
+	Repo githubapi.Repository
+	Err  error
+}
+
+type createItemsMsg struct {
+	Field int
+	Items []pickerItem
+	// Templates backs the template picker, whose values are full names.
+	Templates []githubapi.Repository
+	Err       error
+}
+
+type viewerMsg struct {
+	Login string
+	ID    int64
+	Err   error
+}
+
+type pushLineMsg string
+
+type pushResultMsg struct {
+	Repo   string
+	Branch string
+	Err    error
+}
+
+// pushWriter passes the last line of each progress write on to the page.
+// Lines are dropped while the page is busy rather than holding up git.
+type pushWriter struct {
+	ch chan string
+}
+
+func (pw *pushWriter) Write(p []byte) (int, error) {
+	lines := strings.FieldsFunc(string(p), func(r rune) bool { return r == '\n' || r == '\r' })
+	if len(lines) > 0 {
+		select {
+		case pw.ch <- strings.TrimSpace(lines[len(lines)-1]):
+		default:
+		}
+	}
+	return len(p), nil
 }
 
-func createRepoCmd(githubPAT string, req githubapi.RepoRequest) tea.Cmd {
+func waitForPushLine(ch chan string) tea.Cmd {
 	return func() tea.Msg {
-		result := githubapi.CreateRepo(githubPAT, req)
-		return createResultMsg{
-			Success: result,
+		line, ok := <-ch
+		if !ok {
+			return nil
 		}
+		return pushLineMsg(line)
+	}
+}
+
+func pushDirCmd(client *githubapi.Client, repo githubapi.Repository, path, remoteURL string, author *object.Signature, ch chan string) tea.Cmd {
+	return func() tea.Msg {
+		branch, err := client.PushDirectory(path, remoteURL, githubapi.PushDirOptions{
+			Branch:   repo.DefaultBranch,
+			Message:  "Initial commit",
+			Author:   author,
+			Progress: &pushWriter{ch: ch},
+		})
+		close(ch)
+		return pushResultMsg{Repo: repo.FullName, Branch: branch, Err: err}
+	}
+}
+
+type nameCheckTickMsg struct {
+	Seq int
+}
+
+type nameCheckMsg struct {
+	Seq    int
+	Exists bool
+	Err    error
+}
+
+func createRepoCmd(client *githubapi.Client, org string, req githubapi.RepoRequest) tea.Cmd {
+	return func() tea.Msg {
+		repo, err := client.CreateRepo(org, req)
+		return createResultMsg{Repo: repo, Err: err}
+	}
+}
+
+func generateRepoCmd(client *githubapi.Client, template githubapi.Repository, req githubapi.TemplateRequest) tea.Cmd {
+	return func() tea.Msg {
+		repo, err := client.GenerateRepo(template, req)
+		return createResultMsg{Repo: repo, Err: err}
+	}
+}
+
+func fetchViewerCmd(client *githubapi.Client) tea.Cmd {
+	return func() tea.Msg {
+		user, err := client.GetAuthenticatedUser()
+		return viewerMsg{Login: user.Login, ID: user.ID, Err: err}
 	}
 }
 
+// fetchCreateItemsCmd lists the choices for one of the picker fields.
+func fetchCreateItemsCmd(client *githubapi.Client, field int) tea.Cmd {
+	return func() tea.Msg {
+		items := []pickerItem{{Label: "None", Value: ""}}
+
+		switch field {
+		case fieldOwner:
+			orgs, err := listAll(client.ListMyOrgs)
+			if err != nil {
+				return createItemsMsg{Field: field, Err: err}
+			}
+			items = []pickerItem{{Label: "you", Detail: "personal account", Value: ""}}
+			for _, o := range orgs {
+				items = append(items, pickerItem{Label: o.Login, Detail: "organisation", Value: o.Login})
+			}
+
+		case fieldTemplate:
+			repos, err := listAll(func(opts githubapi.ListOptions) ([]githubapi.Repository, *githubapi.Response, error) {
+				return client.ListMyRepos(githubapi.RepoListOptions{Sort: "pushed", ListOptions: opts})
+			})
+			if err != nil {
+				return createItemsMsg{Field: field, Err: err}
+			}
+			var templates []githubapi.Repository
+			for _, r := range repos {
+				if r.IsTemplate {
+					templates = append(templates, r)
+					items = append(items, pickerItem{Label: r.FullName, Detail: safeStr(r.Description, ""), Value: r.FullName})
+				}
+			}
+			return createItemsMsg{Field: field, Items: items, Templates: templates}
+
+		case fieldGitignore:
+			names, err := client.ListGitignoreTemplates()
+			if err != nil {
+				return createItemsMsg{Field: field, Err: err}
+			}
+			for _, name := range names {
+				items = append(items, pickerItem{Label: name, Value: name})
+			}
+
+		case fieldLicense:
+			licenses, err := client.ListLicenses()
+			if err != nil {
+				return createItemsMsg{Field: field, Err: err}
+			}
+			for _, l := range licenses {
+				items = append(items, pickerItem{Label: l.Name, Detail: l.SpdxID, Value: l.Key})
+			}
+		}
+		return createItemsMsg{Field: field, Items: items}
+	}
+}
+
+func checkNameCmd(client *githubapi.Client, seq int, owner, name string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.GetRepo(owner, name)
+		if errors.Is(err, githubapi.ErrNotFound) {
+			return nameCheckMsg{Seq: seq}
+		}
+		return nameCheckMsg{Seq: seq, Exists: err == nil, Err: err}
+	}
+}
+
+// checkRepoName applies GitHub's naming rules. GitHub replaces runs of
+// characters other than letters, digits, ".", "-" and "_" with a "-", so
+// sanitized is the name the repository would actually get.
+func checkRepoName(name string) (sanitized, problem string) {
+	switch {
+	case name == "":
+		return "", "A repository name is required."
+	case len(name) > 100:
+		return "", "Repository names are at most 100 characters."
+	}
+	sanitized = invalidRepoChars.ReplaceAllString(name, "-")
+	if sanitized == "." || sanitized == ".." {
+		return "", fmt.Sprintf("%q is reserved.", sanitized)
+	}
+	return sanitized, ""
+}
+
 type createRepoPage struct {
 	mode        int
 	focusIndex  int
@@ -44,43 +230,250 @@ type createRepoPage struct {
 	toggles     []bool
 	statusMsg   string
 	statusColor lipgloss.Color
-
-	width     int
-	height    int
-	githubPAT string
+	err         error
+	lastCmd     tea.Cmd
+
+	viewer    string
+	viewerID  int64
+	owner     string
+	template  *githubapi.Repository
+	templates []githubapi.Repository
+	gitignore string
+	license   pickerItem
+
+	picker      *picker
+	pickerField int
+
+	pushSSH  bool
+	created  *githubapi.Repository
+	pushing  bool
+	pushChan chan string
+	pushLine string
+
+	// nameSeq numbers the name edits, so only the lookup for the latest
+	// one is shown.
+	nameSeq    int
+	nameCheck  *nameCheckMsg
+	nameExists bool
+
+	width  int
+	height int
+	client *githubapi.Client
 }
 
-func NewCreateRepoPage(githubPAT string, width, height int) tea.Model {
+func NewCreateRepoPage(client *githubapi.Client, width, height int) tea.Model {
 	m := createRepoPage{
 		mode:       ModeNav,
-		focusIndex: 0,
-		toggles:    []bool{false, true, true, true},
+		focusIndex: fieldRepoName,
+		toggles:    []bool{false, true, true, true, false},
 		width:      width,
 		height:     height,
-		githubPAT:  githubPAT,
+		client:     client,
 	}
 
-	m.inputs = make([]textinput.Model, 2)
+	m.inputs = make([]textinput.Model, 3)
 
 	tName := textinput.New()
 	tName.Placeholder = "Project Name"
 	tName.CharLimit = 100
 	tName.Width = 50
 	tName.Prompt = ""
-	m.inputs[fieldRepoName] = tName
+	m.inputs[inputIndex(fieldRepoName)] = tName
 
 	tDesc := textinput.New()
 	tDesc.Placeholder = "Short description of your repository"
 	tDesc.CharLimit = 200
 	tDesc.Width = 50
 	tDesc.Prompt = ""
-	m.inputs[fieldDescription] = tDesc
+	m.inputs[inputIndex(fieldDescription)] = tDesc
+
+	tPath := textinput.New()
+	tPath.Placeholder = "Local directory to push (optional)"
+	tPath.CharLimit = 500
+	tPath.Width = 50
+	tPath.Prompt = ""
+	m.inputs[inputIndex(fieldLocalPath)] = tPath
 
 	return m
 }
 
+// inputIndex returns the text input behind field, or -1 for fields that
+// are not typed into.
+func inputIndex(field int) int {
+	switch field {
+	case fieldRepoName:
+		return 0
+	case fieldDescription:
+		return 1
+	case fieldLocalPath:
+		return 2
+	}
+	return -1
+}
+
+func (m createRepoPage) toggle(field int) bool {
+	return m.toggles[field-fieldPrivate]
+}
+
+// hidden reports whether field is skipped, as a template brings its own
+// features and files.
+func (m createRepoPage) hidden(field int) bool {
+	return m.template != nil && field >= fieldIssues && field <= fieldLicense
+}
+
 func (m createRepoPage) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, fetchViewerCmd(m.client))
+}
+
+func (m createRepoPage) name() string {
+	return strings.TrimSpace(m.inputs[inputIndex(fieldRepoName)].Value())
+}
+
+// localPath returns the directory to push with "~" expanded, or "".
+func (m createRepoPage) localPath() string {
+	path := strings.TrimSpace(m.inputs[inputIndex(fieldLocalPath)].Value())
+	if rest, ok := strings.CutPrefix(path, "~"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+func (m createRepoPage) ownerLogin() string {
+	if m.owner != "" {
+		return m.owner
+	}
+	return m.viewer
+}
+
+// recheckName schedules a lookup of the name once typing pauses.
+func (m *createRepoPage) recheckName() tea.Cmd {
+	m.nameSeq++
+	m.nameCheck = nil
+	m.nameExists = false
+	seq := m.nameSeq
+	return tea.Tick(nameCheckDelay, func(time.Time) tea.Msg {
+		return nameCheckTickMsg{Seq: seq}
+	})
+}
+
+func (m createRepoPage) submit() (tea.Model, tea.Cmd) {
+	name, problem := checkRepoName(m.name())
+	if problem != "" {
+		m.statusMsg = problem
+		m.statusColor = warning
+		return m, nil
+	}
+	if m.nameExists {
+		m.statusMsg = fmt.Sprintf("%s/%s already exists.", m.ownerLogin(), name)
+		m.statusColor = warning
+		return m, nil
+	}
+
+	if m.localPath() != "" {
+		if info, err := os.Stat(m.localPath()); err != nil || !info.IsDir() {
+			m.statusMsg = m.localPath() + " is not a directory."
+			m.statusColor = warning
+			return m, nil
+		}
+		if m.template != nil || m.toggle(fieldAutoInit) || m.gitignore != "" || m.license.Value != "" {
+			m.statusMsg = "A local directory can only be pushed to an empty repository; drop the template, README, .gitignore and license."
+			m.statusColor = warning
+			return m, nil
+		}
+	}
+
+	m.created = nil
+	m.statusMsg = "Creating repository..."
+	m.statusColor = text
+	description := m.inputs[inputIndex(fieldDescription)].Value()
+
+	if m.template != nil {
+		m.lastCmd = generateRepoCmd(m.client, *m.template, githubapi.TemplateRequest{
+			Owner:       m.owner,
+			Name:        name,
+			Description: description,
+			Private:     m.toggle(fieldPrivate),
+		})
+		return m, m.lastCmd
+	}
+
+	req := githubapi.RepoRequest{
+		Name:              name,
+		Description:       description,
+		Private:           m.toggle(fieldPrivate),
+		HasIssues:         m.toggle(fieldIssues),
+		HasProjects:       m.toggle(fieldProjects),
+		HasWiki:           m.toggle(fieldWiki),
+		AutoInit:          m.toggle(fieldAutoInit),
+		GitignoreTemplate: m.gitignore,
+		LicenseTemplate:   m.license.Value,
+	}
+	m.lastCmd = createRepoCmd(m.client, m.owner, req)
+	return m, m.lastCmd
+}
+
+// startPush pushes the local directory to the repository just created.
+func (m createRepoPage) startPush() (tea.Model, tea.Cmd) {
+	remote := m.created.CloneURL
+	if m.pushSSH {
+		remote = m.created.SSHURL
+	}
+
+	// Used only when git has no user.name and user.email configured.
+	var author *object.Signature
+	if m.viewer != "" {
+		author = &object.Signature{
+			Name:  m.viewer,
+			Email: fmt.Sprintf("%d+%s@users.noreply.github.com", m.viewerID, m.viewer),
+			When:  time.Now(),
+		}
+	}
+
+	ch := make(chan string, 1)
+	m.pushChan = ch
+	m.pushing = true
+	m.pushLine = ""
+	m.err = nil
+	m.statusMsg = fmt.Sprintf("Pushing %s to %s...", m.localPath(), m.created.FullName)
+	m.statusColor = text
+	return m, tea.Batch(
+		pushDirCmd(m.client, *m.created, m.localPath(), remote, author, ch),
+		waitForPushLine(ch),
+	)
+}
+
+func (m createRepoPage) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p, item, done, cmd := m.picker.update(msg)
+	m.picker = &p
+	if !done {
+		return m, cmd
+	}
+	m.picker = nil
+	if item == nil {
+		return m, nil
+	}
+
+	switch m.pickerField {
+	case fieldOwner:
+		if item.Value != m.owner {
+			m.owner = item.Value
+			return m, m.recheckName()
+		}
+	case fieldTemplate:
+		m.template = nil
+		for i, r := range m.templates {
+			if r.FullName == item.Value {
+				m.template = &m.templates[i]
+			}
+		}
+	case fieldGitignore:
+		m.gitignore = item.Value
+	case fieldLicense:
+		m.license = *item
+	}
+	return m, nil
 }
 
 func (m createRepoPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -90,37 +483,126 @@ func (m createRepoPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 
 	case createResultMsg:
-		if msg.Success {
-			m.statusMsg = "Repository Created Successfully!"
-			m.statusColor = special
-		} else {
-			m.statusMsg = "Failed to Create Repository."
+		m.err = msg.Err
+		m.mode = ModeNav
+		if msg.Err != nil {
+			m.statusMsg = ""
+			return m, nil
+		}
+		m.created = &msg.Repo
+		if m.localPath() != "" {
+			return m.startPush()
+		}
+		m.statusMsg = fmt.Sprintf("Created %s successfully!", msg.Repo.FullName)
+		m.statusColor = special
+
+	case pushLineMsg:
+		if !m.pushing {
+			return m, nil
+		}
+		m.pushLine = string(msg)
+		return m, waitForPushLine(m.pushChan)
+
+	case pushResultMsg:
+		if m.created == nil || msg.Repo != m.created.FullName {
+			return m, nil
+		}
+		m.pushing = false
+		m.pushLine = ""
+		m.err = msg.Err
+		if msg.Err != nil {
+			m.statusMsg = fmt.Sprintf("Created %s, but the push failed.", m.created.FullName)
 			m.statusColor = warning
+			return m, nil
 		}
-		m.mode = ModeNav
+		m.statusMsg = fmt.Sprintf("Created %s and pushed %s!", m.created.FullName, msg.Branch)
+		m.statusColor = special
+		return m, nil
+
+	case viewerMsg:
+		// Without a token there is no one to create repositories for;
+		// submitting reports that.
+		if msg.Err == nil {
+			m.viewer = msg.Login
+			if m.name() != "" {
+				return m, m.recheckName()
+			}
+		}
+		return m, nil
+
+	case createItemsMsg:
+		if m.picker != nil && msg.Field == m.pickerField {
+			if msg.Field == fieldTemplate {
+				m.templates = msg.Templates
+			}
+			m.picker.setItems(msg.Items, msg.Err)
+		}
+		return m, nil
+
+	case nameCheckTickMsg:
+		name, problem := checkRepoName(m.name())
+		if msg.Seq != m.nameSeq || problem != "" || m.ownerLogin() == "" {
+			return m, nil
+		}
+		return m, checkNameCmd(m.client, msg.Seq, m.ownerLogin(), name)
+
+	case nameCheckMsg:
+		if msg.Seq == m.nameSeq {
+			m.nameCheck = &msg
+			m.nameExists = msg.Exists
+		}
+		return m, nil
 
 	case tea.KeyMsg:
+		if m.picker != nil {
+			return m.updatePicker(msg)
+		}
 
 		if m.mode == ModeEdit {
 			switch msg.String() {
 			case "esc":
 				m.mode = ModeNav
-				m.inputs[m.focusIndex].Blur()
+				m.inputs[inputIndex(m.focusIndex)].Blur()
 				return m, nil
 			case "enter":
 				m.mode = ModeNav
-				m.inputs[m.focusIndex].Blur()
+				m.inputs[inputIndex(m.focusIndex)].Blur()
 				return m, nil
 			}
 
-			return m, m.updateInputs(msg)
+			before := m.name()
+			cmd := m.updateInputs(msg)
+			if m.name() != before {
+				cmd = tea.Batch(cmd, m.recheckName())
+			}
+			return m, cmd
+		}
+
+		if m.err != nil {
+			switch msg.String() {
+			case "r":
+				m.err = nil
+				if m.created != nil && m.localPath() != "" {
+					return m.startPush()
+				}
+				m.statusMsg = "Creating repository..."
+				m.statusColor = text
+				return m, m.lastCmd
+			case "backspace", "esc":
+				m.err = nil
+			}
+			return m, nil
+		}
+
+		if m.pushing {
+			return m, nil
 		}
 
 		switch msg.String() {
 		case "q":
 
 			return m, func() tea.Msg {
-				return NavMsg{to: SearchPage, from: CreateRepoPage}
+				return NavMsg{to: SearchPage, from: CreateRepoPage, back: true}
 			}
 
 		case "backspace":
@@ -128,58 +610,69 @@ func (m createRepoPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "esc":
 			return m, func() tea.Msg {
-				return NavMsg{to: SearchPage, from: CreateRepoPage}
+				return NavMsg{to: SearchPage, from: CreateRepoPage, back: true}
 			}
 
 		case "tab", "shift+tab", "up", "down", "k", "j":
 			s := msg.String()
 
+			step := 1
 			if s == "up" || s == "shift+tab" || s == "k" {
-				m.focusIndex--
-			} else {
-				m.focusIndex++
+				step = -1
 			}
-
-			if m.focusIndex > fieldSubmit {
-				m.focusIndex = 0
-			} else if m.focusIndex < 0 {
-				m.focusIndex = fieldSubmit
+			for {
+				m.focusIndex += step
+				if m.focusIndex > fieldSubmit {
+					m.focusIndex = 0
+				} else if m.focusIndex < 0 {
+					m.focusIndex = fieldSubmit
+				}
+				if !m.hidden(m.focusIndex) {
+					break
+				}
 			}
 
 		case "enter", " ":
 
-			if m.focusIndex == fieldRepoName || m.focusIndex == fieldDescription {
+			if i := inputIndex(m.focusIndex); i >= 0 {
 				m.mode = ModeEdit
-				m.inputs[m.focusIndex].Focus()
+				m.inputs[i].Focus()
 
-				cmd := m.inputs[m.focusIndex].Cursor.BlinkCmd()
+				cmd := m.inputs[i].Cursor.BlinkCmd()
 				return m, cmd
 			}
 
-			if m.focusIndex >= fieldPrivate && m.focusIndex <= fieldWiki {
-				idx := m.focusIndex - 2
+			switch m.focusIndex {
+			case fieldOwner, fieldTemplate, fieldGitignore, fieldLicense:
+				titles := map[int]string{
+					fieldOwner:     "Owner",
+					fieldTemplate:  "Template repository",
+					fieldGitignore: ".gitignore template",
+					fieldLicense:   "License",
+				}
+				p := newPicker(titles[m.focusIndex])
+				m.picker = &p
+				m.pickerField = m.focusIndex
+				return m, fetchCreateItemsCmd(m.client, m.focusIndex)
+			}
+
+			if m.focusIndex == fieldPushSSH {
+				m.pushSSH = !m.pushSSH
+			}
+
+			if m.focusIndex >= fieldPrivate && m.focusIndex <= fieldAutoInit {
+				idx := m.focusIndex - fieldPrivate
 				m.toggles[idx] = !m.toggles[idx]
 			}
 
 			if m.focusIndex == fieldSubmit {
-				m.statusMsg = "Creating repository..."
-				m.statusColor = text
-
-				req := githubapi.RepoRequest{
-					Name:        m.inputs[fieldRepoName].Value(),
-					Description: m.inputs[fieldDescription].Value(),
-					Private:     m.toggles[fieldPrivate-2],
-					HasIssues:   m.toggles[fieldIssues-2],
-					HasProjects: m.toggles[fieldProjects-2],
-					HasWiki:     m.toggles[fieldWiki-2],
-				}
-				return m, createRepoCmd(m.githubPAT, req)
+				return m.submit()
 			}
 		}
 	}
 
 	for i := range m.inputs {
-		if i == m.focusIndex {
+		if i == inputIndex(m.focusIndex) {
 
 			if m.mode == ModeEdit {
 				m.inputs[i].TextStyle = lipgloss.NewStyle().Foreground(text)
@@ -206,7 +699,45 @@ func (m *createRepoPage) updateInputs(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// renderNameNote shows what is wrong with the name, or whether it is free.
+func (m createRepoPage) renderNameNote() string {
+	if m.name() == "" {
+		return ""
+	}
+	warn := lipgloss.NewStyle().Foreground(warning)
+	note := lipgloss.NewStyle().Foreground(subtle)
+
+	name, problem := checkRepoName(m.name())
+	if problem != "" {
+		return warn.Render("✗ " + problem)
+	}
+	full := name
+	if m.ownerLogin() != "" {
+		full = m.ownerLogin() + "/" + name
+	}
+
+	var status string
+	switch {
+	case m.ownerLogin() == "":
+	case m.nameCheck == nil:
+		status = note.Render("checking " + full + "...")
+	case m.nameCheck.Err != nil:
+		status = note.Render("could not check: " + errorTitle(m.nameCheck.Err))
+	case m.nameCheck.Exists:
+		return warn.Render("✗ " + full + " already exists")
+	default:
+		status = lipgloss.NewStyle().Foreground(special).Render("✓ " + full + " is available")
+	}
+	if name != m.name() {
+		status = strings.TrimSpace(warn.Render("will be created as "+name) + " " + status)
+	}
+	return status
+}
+
 func (m createRepoPage) View() string {
+	if m.picker != nil {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.picker.View(m.width-4, m.height-4))
+	}
 
 	activeStyle := lipgloss.NewStyle().Foreground(highlight)
 	inactiveStyle := lipgloss.NewStyle().Foreground(subtle)
@@ -256,13 +787,48 @@ func (m createRepoPage) View() string {
 		)
 	}
 
-	nameField := renderInput("Repository Name", m.inputs[fieldRepoName], m.focusIndex == fieldRepoName)
-	descField := renderInput("Description", m.inputs[fieldDescription], m.focusIndex == fieldDescription)
+	renderChoice := func(label, value string, isFocused bool) string {
+		style := inactiveStyle
+		if isFocused {
+			style = activeStyle
+		}
+		return style.Width(14).Render(label) + lipgloss.NewStyle().Foreground(text).Render(value+" ▾")
+	}
 
-	settingPrivate := renderCheckbox("Private Repository", m.toggles[0], m.focusIndex == fieldPrivate)
-	settingIssues := renderCheckbox("Enable Issues", m.toggles[1], m.focusIndex == fieldIssues)
-	settingProjects := renderCheckbox("Enable Projects", m.toggles[2], m.focusIndex == fieldProjects)
-	settingWiki := renderCheckbox("Enable Wiki", m.toggles[3], m.focusIndex == fieldWiki)
+	owner := "you"
+	if m.viewer != "" {
+		owner = "you (" + m.viewer + ")"
+	}
+	if m.owner != "" {
+		owner = m.owner
+	}
+	template := "None"
+	if m.template != nil {
+		template = m.template.FullName
+	}
+	gitignore := safeStr(&m.gitignore, "None")
+	license := safeStr(&m.license.Label, "None")
+
+	ownerField := renderChoice("Owner", owner, m.focusIndex == fieldOwner)
+	nameField := renderInput("Repository Name", m.inputs[inputIndex(fieldRepoName)], m.focusIndex == fieldRepoName)
+	descField := renderInput("Description", m.inputs[inputIndex(fieldDescription)], m.focusIndex == fieldDescription)
+	templateField := renderChoice("Template", template, m.focusIndex == fieldTemplate)
+
+	settingPrivate := renderCheckbox("Private Repository", m.toggle(fieldPrivate), m.focusIndex == fieldPrivate)
+	settingIssues := renderCheckbox("Enable Issues", m.toggle(fieldIssues), m.focusIndex == fieldIssues)
+	settingProjects := renderCheckbox("Enable Projects", m.toggle(fieldProjects), m.focusIndex == fieldProjects)
+	settingWiki := renderCheckbox("Enable Wiki", m.toggle(fieldWiki), m.focusIndex == fieldWiki)
+	settingReadme := renderCheckbox("Add a README", m.toggle(fieldAutoInit), m.focusIndex == fieldAutoInit)
+	gitignoreField := renderChoice(".gitignore", gitignore, m.focusIndex == fieldGitignore)
+	licenseField := renderChoice("License", license, m.focusIndex == fieldLicense)
+
+	pathField := renderInput("Push Local Directory", m.inputs[inputIndex(fieldLocalPath)], m.focusIndex == fieldLocalPath)
+	settingSSH := renderCheckbox("Push over SSH instead of HTTPS", m.pushSSH, m.focusIndex == fieldPushSSH)
+
+	initSection := lipgloss.JoinVertical(lipgloss.Left, settingIssues, settingProjects, settingWiki, "", settingReadme, gitignoreField, licenseField)
+	if m.template != nil {
+		initSection = inactiveStyle.Render("Features and initial files come from the template.")
+	}
 
 	submitBtn := "[ Create Repository ]"
 	if m.focusIndex == fieldSubmit {
@@ -272,26 +838,39 @@ func (m createRepoPage) View() string {
 	}
 
 	statusDisplay := ""
-	if m.statusMsg != "" {
+	if m.err != nil {
+		statusDisplay = renderErrorState(m.err, 60)
+		if m.created != nil {
+			statusDisplay = lipgloss.JoinVertical(lipgloss.Left, lipgloss.NewStyle().Foreground(m.statusColor).Bold(true).Render(m.statusMsg), statusDisplay)
+		}
+	} else if m.statusMsg != "" {
 		statusDisplay = lipgloss.NewStyle().
 			Foreground(m.statusColor).
 			Bold(true).
 			Render(m.statusMsg)
 	}
+	if m.pushing && m.pushLine != "" {
+		statusDisplay = lipgloss.JoinVertical(lipgloss.Left, statusDisplay, inactiveStyle.MaxWidth(60).Render(m.pushLine))
+	}
 
 	formContent := lipgloss.JoinVertical(lipgloss.Left,
 		heading.Render("Create New Repository"),
 		"",
 		modeIndicator,
 		"",
+		ownerField,
 		nameField,
+		m.renderNameNote(),
 		"",
 		descField,
 		"",
+		templateField,
+		"",
 		settingPrivate,
-		settingIssues,
-		settingProjects,
-		settingWiki,
+		initSection,
+		"",
+		pathField,
+		settingSSH,
 		"",
 		submitBtn,
 		"",
@@ -304,3 +883,7 @@ func (m createRepoPage) View() string {
 		window.Render(formContent),
 	)
 }
+
+func (m createRepoPage) editing() bool {
+	return m.mode == ModeEdit || m.picker != nil
+}
@@ -0,0 +1,270 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+const (
+	deleteChecking int = iota
+	deleteBlocked
+	deleteConfirming
+	deleteRunning
+	deleteDone
+)
+
+type tokenScopesMsg struct {
+	Scopes []string
+	Known  bool
+	Err    error
+}
+
+type repoDeletedMsg struct {
+	Repo string
+	Err  error
+}
+
+// repoDeleter walks the user through deleting one or more repositories:
+// it checks that the token may delete them, has the full name of each one
+// typed out, then deletes them in turn and reports how each went.
+type repoDeleter struct {
+	repos []githubapi.Repository
+	stage int
+	index int
+	input textinput.Model
+	// unknownScopes is set for tokens that do not report their scopes.
+	unknownScopes bool
+	blocked       string
+	mismatch      bool
+	errs          []error
+	spinner       spinner.Model
+	client        *githubapi.Client
+}
+
+func newRepoDeleter(client *githubapi.Client, repos []githubapi.Repository) (repoDeleter, tea.Cmd) {
+	ti := textinput.New()
+	ti.Prompt = "› "
+	ti.CharLimit = 200
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	d := repoDeleter{
+		repos:   repos,
+		stage:   deleteChecking,
+		input:   ti,
+		errs:    make([]error, len(repos)),
+		spinner: s,
+		client:  client,
+	}
+	return d, tea.Batch(s.Tick, func() tea.Msg {
+		scopes, known, err := client.TokenScopes()
+		return tokenScopesMsg{Scopes: scopes, Known: known, Err: err}
+	})
+}
+
+func deleteRepoCmd(client *githubapi.Client, repo githubapi.Repository) tea.Cmd {
+	return func() tea.Msg {
+		return repoDeletedMsg{Repo: repo.FullName, Err: client.DeleteRepo(repo)}
+	}
+}
+
+func (d repoDeleter) busy() bool {
+	return d.stage == deleteChecking || d.stage == deleteRunning
+}
+
+// deleted returns the full names of the repositories that are gone.
+func (d repoDeleter) deleted() []string {
+	if d.stage < deleteRunning {
+		return nil
+	}
+	var names []string
+	for i, r := range d.repos {
+		if i < d.index && d.errs[i] == nil {
+			names = append(names, r.FullName)
+		}
+	}
+	return names
+}
+
+// update handles the deleter's messages and keys. closed is true once the
+// user dismisses it. Ticks of other spinners are ignored and return no
+// command, so hosts can offer every tick here first.
+func (d repoDeleter) update(msg tea.Msg) (repoDeleter, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case spinner.TickMsg:
+		if !d.busy() {
+			return d, nil, false
+		}
+		var cmd tea.Cmd
+		d.spinner, cmd = d.spinner.Update(msg)
+		return d, cmd, false
+
+	case tokenScopesMsg:
+		if d.stage != deleteChecking {
+			return d, nil, false
+		}
+		switch {
+		case msg.Err != nil:
+			d.stage = deleteBlocked
+			d.blocked = "Could not check the token: " + errorTitle(msg.Err) + ": " + msg.Err.Error()
+			return d, nil, false
+		case msg.Known && !slices.Contains(msg.Scopes, githubapi.ScopeDeleteRepo):
+			d.stage = deleteBlocked
+			d.blocked = "Your token does not have the delete_repo scope. Add it to the PAT in your GitHub token settings, then try again."
+			return d, nil, false
+		}
+		d.unknownScopes = !msg.Known
+		d.stage = deleteConfirming
+		return d, d.input.Focus(), false
+
+	case repoDeletedMsg:
+		if d.stage != deleteRunning || msg.Repo != d.repos[d.index].FullName {
+			return d, nil, false
+		}
+		d.errs[d.index] = msg.Err
+		d.index++
+		if d.index < len(d.repos) {
+			return d, deleteRepoCmd(d.client, d.repos[d.index]), false
+		}
+		d.stage = deleteDone
+		return d, nil, false
+
+	case tea.KeyMsg:
+		switch d.stage {
+		case deleteRunning:
+			return d, nil, false
+		case deleteConfirming:
+			switch msg.String() {
+			case "esc":
+				return d, nil, true
+			case "enter":
+				if strings.TrimSpace(d.input.Value()) != d.repos[d.index].FullName {
+					d.mismatch = true
+					return d, nil, false
+				}
+				d.mismatch = false
+				d.input.SetValue("")
+				d.index++
+				if d.index < len(d.repos) {
+					return d, nil, false
+				}
+				d.index = 0
+				d.stage = deleteRunning
+				d.input.Blur()
+				return d, tea.Batch(d.spinner.Tick, deleteRepoCmd(d.client, d.repos[0])), false
+			}
+			var cmd tea.Cmd
+			d.input, cmd = d.input.Update(msg)
+			d.mismatch = false
+			return d, cmd, false
+		default:
+			switch msg.String() {
+			case "esc", "enter":
+				return d, nil, true
+			}
+		}
+	}
+	return d, nil, false
+}
+
+// deleteWarnings lists what is lost along with repo.
+func deleteWarnings(repo githubapi.Repository) []string {
+	var warnings []string
+	if repo.StargazersCount > 0 {
+		warnings = append(warnings, fmt.Sprintf("★ %d stars will be lost", repo.StargazersCount))
+	}
+	if repo.ForksCount > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d forks will be detached from it", repo.ForksCount))
+	}
+	if repo.Size > 0 {
+		warnings = append(warnings, fmt.Sprintf("it is not empty (%s)", humanSize(repo.Size*1024)))
+	}
+	if repo.OpenIssuesCount > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d open issues and pull requests go with it", repo.OpenIssuesCount))
+	}
+	return warnings
+}
+
+// deleteError explains a failed deletion, pointing at the usual causes
+// when GitHub refuses.
+func deleteError(err error) string {
+	msg := errorTitle(err) + ": " + err.Error()
+	if errors.Is(err, githubapi.ErrUnauthorized) || errors.Is(err, githubapi.ErrNotFound) {
+		msg += " (deleting needs admin rights on the repository and a token with the delete_repo scope)"
+	}
+	return msg
+}
+
+func (d repoDeleter) View(width, height int) string {
+	inner := min(width-4, readmeWrap)
+	title := lipgloss.NewStyle().Foreground(warning).Bold(true)
+	note := lipgloss.NewStyle().Foreground(subtle).Width(inner)
+	warn := lipgloss.NewStyle().Foreground(warning).Width(inner)
+
+	var rows []string
+	switch d.stage {
+	case deleteChecking:
+		rows = append(rows, title.Render("Delete repositories"), "", d.spinner.View()+" Checking the token's scopes...")
+
+	case deleteBlocked:
+		rows = append(rows, title.Render("Cannot delete"), "", warn.Render(d.blocked), "", note.Render("esc close"))
+
+	case deleteConfirming:
+		repo := d.repos[d.index]
+		heading := "Delete " + repo.FullName + "?"
+		if len(d.repos) > 1 {
+			heading = fmt.Sprintf("Delete %s? (%d of %d)", repo.FullName, d.index+1, len(d.repos))
+		}
+		rows = append(rows, title.Render(heading), "")
+		if warnings := deleteWarnings(repo); len(warnings) > 0 {
+			for _, w := range warnings {
+				rows = append(rows, warn.Render("! "+w))
+			}
+		} else {
+			rows = append(rows, note.Render("It has no stars or forks and appears to be empty."))
+		}
+		rows = append(rows, "",
+			lipgloss.NewStyle().Foreground(text).Width(inner).Render("This cannot be undone. Type "+lipgloss.NewStyle().Bold(true).Render(repo.FullName)+" to confirm."),
+			d.input.View(),
+		)
+		if d.mismatch {
+			rows = append(rows, warn.Render("That does not match the repository's name."))
+		}
+		if d.unknownScopes {
+			rows = append(rows, "", note.Render("Your token does not report its scopes; fine-grained tokens need the Administration (write) permission."))
+		}
+		rows = append(rows, "", note.Render("enter confirm • esc cancel"))
+
+	case deleteRunning:
+		rows = append(rows, title.Render("Deleting"), "", fmt.Sprintf("%s Deleting %s...", d.spinner.View(), d.repos[d.index].FullName))
+
+	case deleteDone:
+		rows = append(rows, title.Render("Done"), "")
+		for i, r := range d.repos {
+			if d.errs[i] != nil {
+				rows = append(rows, warn.Render("✗ "+r.FullName+": "+deleteError(d.errs[i])))
+			} else {
+				rows = append(rows, lipgloss.NewStyle().Foreground(special).Render("✓ deleted "+r.FullName))
+			}
+		}
+		rows = append(rows, "", note.Render("enter/esc close"))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(warning).
+		Padding(0, 1).
+		Width(inner + 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
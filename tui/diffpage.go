@@ -0,0 +1,739 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/chirag-diwan/RemGit/diff"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+// diffMsg carries a diff with what it was fetched for: a commit (Head
+// alone), a comparison, or a pull request.
+type diffMsg struct {
+	Repo     string
+	Base     string
+	Head     string
+	Pull     int
+	Title    string
+	Files    []githubapi.CommitFile
+	Parsed   []parsedFile
+	Comments []githubapi.ReviewComment
+	// HeadSHA is the pull request's head, which line comments are left on.
+	HeadSHA string
+	Err     error
+}
+
+type reviewCommentMsg struct {
+	Repo    string
+	Number  int
+	Comment githubapi.ReviewComment
+	Err     error
+}
+
+// parsedFile is a file's patch, parsed and highlighted once when the diff
+// loads so that redrawing only lays the lines out. code holds the
+// highlighted text of each line in hunks.
+type parsedFile struct {
+	hunks []diff.Hunk
+	code  map[*diff.Line]string
+	err   error
+}
+
+func parseFiles(files []githubapi.CommitFile) []parsedFile {
+	parsed := make([]parsedFile, len(files))
+	for i, f := range files {
+		if f.Patch == "" {
+			continue
+		}
+		hunks, err := diff.Parse(f.Patch)
+		if err != nil {
+			parsed[i].err = err
+			continue
+		}
+		hl := diff.NewHighlighter(path.Base(f.Filename))
+		code := make(map[*diff.Line]string)
+		for h := range hunks {
+			for l := range hunks[h].Lines {
+				line := &hunks[h].Lines[l]
+				if line.Kind != diff.NoNewline {
+					code[line] = hl.Line(line.Text)
+				}
+			}
+		}
+		parsed[i] = parsedFile{hunks: hunks, code: code}
+	}
+	return parsed
+}
+
+// diffTarget is a diff line a review comment can be left on: the content
+// line it is drawn on and its number in the old (LEFT) or new (RIGHT) file.
+type diffTarget struct {
+	Line int
+	Side string
+	No   int
+}
+
+const (
+	diffFilesWidth    = 34
+	sideBySideMinimum = 160
+
+	addedBg   = "\x1b[48;5;22m"
+	deletedBg = "\x1b[48;5;52m"
+)
+
+type DiffPageModel struct {
+	Width  int
+	Height int
+
+	repo       githubapi.Repository
+	base       string
+	head       string
+	selectPath string
+	pull       int
+	CameFrom   int
+
+	title      string
+	files      []githubapi.CommitFile
+	parsed     []parsedFile
+	comments   []githubapi.ReviewComment
+	fileCursor int
+	hunkLines  []int
+	sideBySide bool
+
+	// Line comments on pull requests: picking moves a cursor over targets,
+	// composing writes the comment for the one under it.
+	headSHA   string
+	targets   []diffTarget
+	picking   bool
+	target    int
+	composing bool
+	input     textarea.Model
+	posting   bool
+	status    string
+
+	view    viewport.Model
+	loading bool
+	err     error
+	spinner spinner.Model
+	client  *githubapi.Client
+}
+
+// NewDiffPageModel shows the changes of commit head, or of base...head when
+// base is set, or of pull request pull with its review comments inline when
+// that is set. selectPath picks the file shown first.
+func NewDiffPageModel(client *githubapi.Client, repo githubapi.Repository, base, head, selectPath string, pull, camefrom int) DiffPageModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	input := textarea.New()
+	input.Placeholder = "Leave a comment on this line (markdown)"
+	input.ShowLineNumbers = false
+	input.CharLimit = 0
+	input.SetWidth(readmeWrap)
+	input.SetHeight(8)
+	input.FocusedStyle.CursorLine = lipgloss.NewStyle()
+
+	return DiffPageModel{
+		input:      input,
+		repo:       repo,
+		base:       base,
+		head:       head,
+		selectPath: selectPath,
+		pull:       pull,
+		CameFrom:   camefrom,
+		sideBySide: true,
+		view:       viewport.New(0, 0),
+		loading:    true,
+		spinner:    s,
+		client:     client,
+	}
+}
+
+func fetchDiffCmd(client *githubapi.Client, repo githubapi.Repository, base, head string, pull int) tea.Cmd {
+	return func() tea.Msg {
+		msg := fetchDiff(client, repo, base, head, pull)
+		msg.Repo, msg.Base, msg.Head, msg.Pull = repo.FullName, base, head, pull
+		if msg.Err == nil {
+			msg.Parsed = parseFiles(msg.Files)
+		}
+		return msg
+	}
+}
+
+func fetchDiff(client *githubapi.Client, repo githubapi.Repository, base, head string, pull int) diffMsg {
+	if pull > 0 {
+		pr, err := client.GetPull(repo, pull)
+		if err != nil {
+			return diffMsg{Err: err}
+		}
+		files, err := allPullFiles(client, repo, pull)
+		if err != nil {
+			return diffMsg{Err: err}
+		}
+		comments, err := allReviewComments(client, repo, pull)
+		if err != nil {
+			return diffMsg{Err: err}
+		}
+		title := fmt.Sprintf("#%d %s · %d files · %d review comments", pull, pr.Title, len(files), len(comments))
+		return diffMsg{Title: title, Files: files, Comments: comments, HeadSHA: pr.Head.SHA}
+	}
+	if base == "" {
+		commit, err := client.GetCommit(repo, head)
+		if err != nil {
+			return diffMsg{Err: err}
+		}
+		return diffMsg{Title: fmt.Sprintf("commit %s · %s", commit.ShortSHA(), commit.Summary()), Files: commit.Files}
+	}
+
+	cmp, err := client.CompareCommits(repo, base, head)
+	if err != nil {
+		return diffMsg{Err: err}
+	}
+	title := fmt.Sprintf("%s...%s · %d commits · %s", shortRef(base), shortRef(head), cmp.TotalCommits, cmp.Status)
+	return diffMsg{Title: title, Files: cmp.Files}
+}
+
+func createReviewCommentCmd(client *githubapi.Client, repo githubapi.Repository, number int, req githubapi.ReviewCommentRequest) tea.Cmd {
+	return func() tea.Msg {
+		comment, err := client.CreateReviewComment(repo, number, req)
+		return reviewCommentMsg{Repo: repo.FullName, Number: number, Comment: comment, Err: err}
+	}
+}
+
+// shortRef abbreviates full commit SHAs and leaves branch names alone.
+func shortRef(ref string) string {
+	if len(ref) == 40 && strings.Trim(ref, "0123456789abcdef") == "" {
+		return ref[:7]
+	}
+	return ref
+}
+
+func (m DiffPageModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, fetchDiffCmd(m.client, m.repo, m.base, m.head, m.pull))
+}
+
+func (m DiffPageModel) wide() bool {
+	return m.sideBySide && m.Width >= sideBySideMinimum
+}
+
+func (m *DiffPageModel) layout() {
+	m.view.Width = max(m.Width-diffFilesWidth-2, 20)
+	m.view.Height = max(m.Height-3, 1)
+	m.refresh()
+}
+
+func (m *DiffPageModel) refresh() {
+	var content string
+	content, m.hunkLines, m.targets = m.renderFile()
+	m.view.SetContent(content)
+	if m.target >= len(m.targets) {
+		m.target = max(len(m.targets)-1, 0)
+	}
+}
+
+// showTarget scrolls the line under the comment cursor into view.
+func (m *DiffPageModel) showTarget() {
+	if m.target >= len(m.targets) {
+		return
+	}
+	line := m.targets[m.target].Line
+	if line < m.view.YOffset {
+		m.view.SetYOffset(line)
+	} else if line >= m.view.YOffset+m.view.Height {
+		m.view.SetYOffset(line - m.view.Height + 1)
+	}
+}
+
+// firstVisibleTarget puts the comment cursor on the first line on screen.
+func (m *DiffPageModel) firstVisibleTarget() {
+	m.target = 0
+	for i, t := range m.targets {
+		if t.Line >= m.view.YOffset {
+			m.target = i
+			return
+		}
+	}
+}
+
+func (m DiffPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.layout()
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading && !m.posting {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		if m.loading {
+			m.refresh()
+		}
+		return m, cmd
+
+	case diffMsg:
+		if msg.Repo != m.repo.FullName || msg.Base != m.base || msg.Head != m.head || msg.Pull != m.pull || !m.loading {
+			return m, nil
+		}
+		m.loading = false
+		m.err = msg.Err
+		if msg.Err == nil {
+			m.title = msg.Title
+			m.files = msg.Files
+			m.parsed = msg.Parsed
+			m.comments = msg.Comments
+			m.headSHA = msg.HeadSHA
+			for i, f := range m.files {
+				if f.Filename == m.selectPath {
+					m.fileCursor = i
+				}
+			}
+		}
+		m.refresh()
+		return m, nil
+
+	case reviewCommentMsg:
+		if msg.Repo != m.repo.FullName || msg.Number != m.pull || !m.posting {
+			return m, nil
+		}
+		m.posting = false
+		if msg.Err != nil {
+			m.status = actionError(msg.Err)
+			return m, nil
+		}
+		m.composing = false
+		m.input.Reset()
+		m.input.Blur()
+		m.comments = append(m.comments, msg.Comment)
+		m.status = lipgloss.NewStyle().Foreground(special).Render("Comment posted.")
+		m.refresh()
+		m.showTarget()
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.composing {
+			return m.updateComment(msg)
+		}
+		if m.picking {
+			return m.updatePicking(msg)
+		}
+
+		switch msg.String() {
+		case "c":
+			if m.pull == 0 || m.loading || m.err != nil || m.headSHA == "" || len(m.targets) == 0 {
+				return m, nil
+			}
+			m.picking = true
+			m.status = ""
+			m.firstVisibleTarget()
+			m.refresh()
+			m.showTarget()
+			return m, nil
+		case "backspace":
+			return m, func() tea.Msg {
+				return NavMsg{to: m.CameFrom, from: DiffPage, repodata: m.repo, back: true}
+			}
+		case "r":
+			if m.err != nil {
+				m.err = nil
+				m.loading = true
+				return m, tea.Batch(m.spinner.Tick, fetchDiffCmd(m.client, m.repo, m.base, m.head, m.pull))
+			}
+		case "tab", "J":
+			if len(m.files) > 0 {
+				m.fileCursor = (m.fileCursor + 1) % len(m.files)
+				m.refresh()
+				m.view.GotoTop()
+			}
+		case "shift+tab", "K":
+			if len(m.files) > 0 {
+				m.fileCursor = (m.fileCursor - 1 + len(m.files)) % len(m.files)
+				m.refresh()
+				m.view.GotoTop()
+			}
+		case "n":
+			for _, line := range m.hunkLines {
+				if line > m.view.YOffset {
+					m.view.SetYOffset(line)
+					break
+				}
+			}
+		case "N":
+			for i := len(m.hunkLines) - 1; i >= 0; i-- {
+				if m.hunkLines[i] < m.view.YOffset {
+					m.view.SetYOffset(m.hunkLines[i])
+					break
+				}
+			}
+		case "s":
+			m.sideBySide = !m.sideBySide
+			m.refresh()
+		case "j", "down":
+			m.view.ScrollDown(1)
+		case "k", "up":
+			m.view.ScrollUp(1)
+		case "d", "ctrl+d":
+			m.view.HalfPageDown()
+		case "u", "ctrl+u":
+			m.view.HalfPageUp()
+		}
+	}
+	return m, nil
+}
+
+func (m DiffPageModel) updatePicking(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "c":
+		m.picking = false
+	case "j", "down":
+		m.target = min(m.target+1, max(len(m.targets)-1, 0))
+	case "k", "up":
+		m.target = max(m.target-1, 0)
+	case "d", "ctrl+d":
+		m.target = min(m.target+m.view.Height/2, max(len(m.targets)-1, 0))
+	case "u", "ctrl+u":
+		m.target = max(m.target-m.view.Height/2, 0)
+	case "enter":
+		if len(m.targets) == 0 {
+			return m, nil
+		}
+		m.composing = true
+		m.status = ""
+		return m, m.input.Focus()
+	}
+	m.refresh()
+	m.showTarget()
+	return m, nil
+}
+
+func (m DiffPageModel) updateComment(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.posting {
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.composing = false
+		m.input.Blur()
+		return m, nil
+	case "ctrl+s":
+		body := strings.TrimSpace(m.input.Value())
+		if body == "" || m.target >= len(m.targets) {
+			return m, nil
+		}
+		t := m.targets[m.target]
+		m.posting = true
+		m.status = ""
+		return m, tea.Batch(m.spinner.Tick, createReviewCommentCmd(m.client, m.repo, m.pull, githubapi.ReviewCommentRequest{
+			Body:     body,
+			CommitID: m.headSHA,
+			Path:     m.files[m.fileCursor].Filename,
+			Line:     t.No,
+			Side:     t.Side,
+		}))
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func lineNo(n int) string {
+	if n == 0 {
+		return "     "
+	}
+	return fmt.Sprintf("%5d", n)
+}
+
+// diffCell renders one side of a diff line: line number, sign and its
+// highlighted code, padded and tinted to width.
+func diffCell(line *diff.Line, code string, no int, width int) string {
+	gutter := lipgloss.NewStyle().Foreground(subtle)
+	if line == nil {
+		return strings.Repeat(" ", width)
+	}
+	if line.Kind == diff.NoNewline {
+		return ansi.Truncate(gutter.Render("      \\ "+line.Text), width, "")
+	}
+
+	sign, bg := " ", ""
+	switch line.Kind {
+	case diff.Added:
+		sign, bg = "+", addedBg
+	case diff.Deleted:
+		sign, bg = "-", deletedBg
+	}
+
+	cell := gutter.Render(lineNo(no)) + " " + sign + " " + code
+	cell = ansi.Truncate(cell, width, "…")
+	if pad := width - ansi.StringWidth(cell); pad > 0 {
+		cell += strings.Repeat(" ", pad)
+	}
+	if bg != "" {
+		cell = diff.Tint(cell, bg)
+	}
+	return cell
+}
+
+// commentKey places a review comment on a line of one side of the diff.
+func commentKey(side string, line int) string {
+	return fmt.Sprintf("%s:%d", side, line)
+}
+
+// renderReviewComments draws the comments left on one diff line as a
+// single box, oldest first.
+func renderReviewComments(comments []githubapi.ReviewComment, width int) []string {
+	var posts []string
+	for _, c := range comments {
+		head := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(c.User.Login) +
+			lipgloss.NewStyle().Foreground(subtle).Render(" · "+formatDate(c.CreatedAt))
+		posts = append(posts, head+"\n"+lipgloss.NewStyle().Foreground(text).Render(strings.TrimSpace(c.Body)))
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(special).
+		Padding(0, 1).
+		MarginLeft(8).
+		Width(max(min(width-10, readmeWrap), 20)).
+		Render(strings.Join(posts, "\n\n"))
+	return strings.Split(box, "\n")
+}
+
+// renderFile renders the selected file and returns the content line each
+// hunk starts on, for n/N navigation, and the lines open to comments.
+func (m DiffPageModel) renderFile() (string, []int, []diffTarget) {
+	switch {
+	case m.loading:
+		return fmt.Sprintf("%s Loading diff...", m.spinner.View()), nil, nil
+	case m.err != nil:
+		return renderErrorState(m.err, m.view.Width-4), nil, nil
+	case len(m.files) == 0:
+		return lipgloss.NewStyle().Foreground(subtle).Render("No changes."), nil, nil
+	}
+
+	f := m.files[m.fileCursor]
+	name := f.Filename
+	if f.PreviousFilename != "" {
+		name = f.PreviousFilename + " → " + f.Filename
+	}
+	lines := []string{
+		fmt.Sprintf("%s %s  %s %s", fileStatus(f.Status),
+			lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(name),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#43BF6D")).Render(fmt.Sprintf("+%d", f.Additions)),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#E05252")).Render(fmt.Sprintf("-%d", f.Deletions))),
+		"",
+	}
+
+	if f.Patch == "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(subtle).Render("Binary file, or the diff is too large to show here."))
+		return strings.Join(lines, "\n"), nil, nil
+	}
+	parsed := m.parsed[m.fileCursor]
+	if parsed.err != nil {
+		lines = append(lines, renderErrorState(parsed.err, m.view.Width-4))
+		return strings.Join(lines, "\n"), nil, nil
+	}
+
+	hunkStyle := lipgloss.NewStyle().Foreground(special)
+	width := m.view.Width
+
+	// Review comments still attached to a line go under it; outdated ones
+	// are listed after the diff.
+	onLine := make(map[string][]githubapi.ReviewComment)
+	var outdated []githubapi.ReviewComment
+	for _, c := range m.comments {
+		if c.Path != f.Filename {
+			continue
+		}
+		if c.Line == nil {
+			outdated = append(outdated, c)
+			continue
+		}
+		side := c.Side
+		if side == "" {
+			side = "RIGHT"
+		}
+		key := commentKey(side, *c.Line)
+		onLine[key] = append(onLine[key], c)
+	}
+	takeComments := func(keys ...string) {
+		var found []githubapi.ReviewComment
+		for _, k := range keys {
+			found = append(found, onLine[k]...)
+			delete(onLine, k)
+		}
+		if len(found) > 0 {
+			lines = append(lines, renderReviewComments(found, width)...)
+		}
+	}
+
+	// addLine appends a diff line and, on pull requests, records it as a
+	// comment target, marking it when the comment cursor is on it.
+	var targets []diffTarget
+	addLine := func(line, side string, no int) {
+		if m.pull > 0 && no > 0 {
+			if m.picking && len(targets) == m.target {
+				line = lipgloss.NewStyle().Foreground(highlight).Bold(true).Render("▶") + ansi.Cut(line, 1, ansi.StringWidth(line))
+			}
+			targets = append(targets, diffTarget{Line: len(lines), Side: side, No: no})
+		}
+		lines = append(lines, line)
+	}
+
+	var hunkLines []int
+	for _, h := range parsed.hunks {
+		hunkLines = append(hunkLines, len(lines))
+		header := fmt.Sprintf("@@ -%d,%d +%d,%d @@ %s", h.OldStart, h.OldLines, h.NewStart, h.NewLines, h.Section)
+		lines = append(lines, hunkStyle.Render(ansi.Truncate(header, width, "…")))
+
+		if m.wide() {
+			half := (width - 1) / 2
+			for _, row := range diff.SideBySide(h) {
+				var oldNo, newNo int
+				if row.Left != nil {
+					oldNo = row.Left.OldNo
+				}
+				if row.Right != nil {
+					newNo = row.Right.NewNo
+				}
+				row := diffCell(row.Left, parsed.code[row.Left], oldNo, half) + " " + diffCell(row.Right, parsed.code[row.Right], newNo, half)
+				if newNo > 0 {
+					addLine(row, "RIGHT", newNo)
+				} else {
+					addLine(row, "LEFT", oldNo)
+				}
+				takeComments(commentKey("LEFT", oldNo), commentKey("RIGHT", newNo))
+			}
+			continue
+		}
+
+		for i := range h.Lines {
+			line := &h.Lines[i]
+			no, side := line.NewNo, "RIGHT"
+			if line.Kind == diff.Deleted {
+				no, side = line.OldNo, "LEFT"
+			}
+			addLine(diffCell(line, parsed.code[line], no, width), side, no)
+			switch line.Kind {
+			case diff.Deleted:
+				takeComments(commentKey("LEFT", line.OldNo))
+			case diff.Added:
+				takeComments(commentKey("RIGHT", line.NewNo))
+			default:
+				takeComments(commentKey("LEFT", line.OldNo), commentKey("RIGHT", line.NewNo))
+			}
+		}
+	}
+
+	// Anything left over no longer maps onto this diff.
+	for _, comments := range onLine {
+		outdated = append(outdated, comments...)
+	}
+	if len(outdated) > 0 {
+		sort.Slice(outdated, func(i, j int) bool { return outdated[i].CreatedAt.Before(outdated[j].CreatedAt) })
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(subtle).Bold(true).Render("Outdated comments"))
+		lines = append(lines, renderReviewComments(outdated, width)...)
+	}
+	return strings.Join(lines, "\n"), hunkLines, targets
+}
+
+func (m DiffPageModel) renderFiles() string {
+	height := max(m.Height-3, 1)
+	rows := []string{lipgloss.NewStyle().Foreground(subtle).Bold(true).Render(fmt.Sprintf("Files (%d)", len(m.files)))}
+
+	visible := max(height-1, 1)
+	start := max(0, min(m.fileCursor-visible/2, len(m.files)-visible))
+	for i := start; i < min(len(m.files), start+visible); i++ {
+		f := m.files[i]
+		style := lipgloss.NewStyle().Foreground(text)
+		pointer := "  "
+		if i == m.fileCursor {
+			style = style.Foreground(highlight).Bold(true)
+			pointer = "> "
+		}
+		name := ansi.TruncateLeft(f.Filename, max(ansi.StringWidth(f.Filename)-(diffFilesWidth-6), 0), "…")
+		rows = append(rows, pointer+fileStatus(f.Status)+" "+style.Render(name))
+	}
+
+	return lipgloss.NewStyle().
+		Width(diffFilesWidth).
+		Height(height).
+		Border(lipgloss.NormalBorder(), false, true, false, false).
+		BorderForeground(subtle).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func (m DiffPageModel) View() string {
+	title := m.title
+	if title == "" {
+		title = m.repo.FullName + " · diff"
+	}
+	header := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(ansi.Truncate(title, m.Width, "…"))
+
+	mode := "unified"
+	if m.wide() {
+		mode = "side by side"
+	} else if m.sideBySide {
+		mode = fmt.Sprintf("unified (side by side needs %d columns)", sideBySideMinimum)
+	}
+	if m.composing {
+		return m.renderComment()
+	}
+
+	keys := "tab/shift+tab file • j/k d/u scroll • n/N hunk • s toggle side by side [" + mode + "] • backspace back"
+	switch {
+	case m.picking:
+		keys = "j/k d/u pick a line • enter comment on it • esc cancel"
+	case m.pull > 0:
+		keys = "c comment on a line • " + keys
+	}
+	hint := lipgloss.NewStyle().Foreground(subtle).Render(keys)
+	if m.status != "" {
+		hint = m.status + "  " + hint
+	}
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top, m.renderFiles(), " ", m.view.View())
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, ansi.Truncate(hint, m.Width, "…"))
+}
+
+func (m DiffPageModel) renderComment() string {
+	t := m.targets[m.target]
+	status := lipgloss.NewStyle().Foreground(subtle).Render("ctrl+s post • esc cancel")
+	switch {
+	case m.posting:
+		status = fmt.Sprintf("%s Posting comment...", m.spinner.View())
+	case m.status != "":
+		status = m.status
+	}
+	where := fmt.Sprintf("Comment on %s line %d", m.files[m.fileCursor].Filename, t.No)
+	if t.Side == "LEFT" {
+		where += " (removed)"
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(ansi.TruncateLeft(where, max(ansi.StringWidth(where)-readmeWrap, 0), "…")),
+			"",
+			m.input.View(),
+			"",
+			status,
+		))
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m DiffPageModel) editing() bool {
+	return m.composing
+}
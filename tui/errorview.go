@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+func errorTitle(err error) string {
+	switch {
+	case errors.Is(err, githubapi.ErrNotFound):
+		return "Not Found"
+	case errors.Is(err, githubapi.ErrUnauthorized):
+		return "Unauthorized"
+	case errors.Is(err, githubapi.ErrRateLimited):
+		return "Rate Limited"
+	case errors.Is(err, githubapi.ErrValidation):
+		return "Validation Failed"
+	case errors.Is(err, githubapi.ErrNetwork):
+		return "Network Unavailable"
+	}
+	return "Something Went Wrong"
+}
+
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, githubapi.ErrUnauthorized):
+		return "Check the PAT in ~/.remgit.conf and its scopes."
+	case errors.Is(err, githubapi.ErrRateLimited):
+		return "Wait for the rate limit to reset, or set a PAT for higher limits."
+	case errors.Is(err, githubapi.ErrNetwork):
+		return "Check your internet connection."
+	}
+	return ""
+}
+
+func renderErrorState(err error, width int) string {
+	if width <= 0 || width > 70 {
+		width = 70
+	}
+
+	title := lipgloss.NewStyle().Foreground(warning).Bold(true).Render("✗ " + errorTitle(err))
+	body := lipgloss.NewStyle().Foreground(text).Width(width - 4).Render(err.Error())
+	lines := []string{title, "", body}
+
+	if hint := errorHint(err); hint != "" {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(subtle).Italic(true).Width(width-4).Render(hint))
+	}
+	lines = append(lines, "", lipgloss.NewStyle().Foreground(subtle).Render("r retry • backspace back"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(warning).
+		Padding(1, 2).
+		Width(width).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
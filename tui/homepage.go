@@ -44,6 +44,14 @@ func (model HomePageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return model, func() tea.Msg {
 				return NavMsg{to: SearchPage, from: HomePage}
 			}
+		case "p":
+			return model, func() tea.Msg {
+				return NavMsg{to: PullsPage, from: HomePage}
+			}
+		case "y":
+			return model, func() tea.Msg {
+				return NavMsg{to: MyReposPage, from: HomePage}
+			}
 		case "c":
 		}
 	}
@@ -55,6 +63,10 @@ func (model HomePageModel) View() string {
 
 Press s to search user or repository :)
 
+Press p for pull requests waiting on your review
+
+Press y for your repositories
+
 Press h for help
 
 You can disable this screen in config files (~/.remgit.conf)
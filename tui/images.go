@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"bytes"
+
+	"github.com/blacktop/go-termimg"
+)
+
+func imageProtocol() termimg.Protocol {
+	switch imgstyle {
+	case "sixel":
+		return termimg.Sixel
+	case "kitty":
+		return termimg.Kitty
+	case "iterm2", "iterm":
+		return termimg.ITerm2
+	}
+	return termimg.Halfblocks
+}
+
+// renderImage decodes data and renders it with protocol, fitting it inside
+// maxWidth x maxHeight cells and the Imgw/Imgh caps. Animated images show
+// their first frame.
+//
+// Images that scroll with text in a viewport must use termimg.Halfblocks:
+// the other protocols are escape sequences drawn outside the cell grid, so
+// the viewport can neither count, wrap nor clip their rows.
+func renderImage(data []byte, protocol termimg.Protocol, maxWidth, maxHeight int) (string, error) {
+	img, err := termimg.From(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	width, height := maxWidth, maxHeight
+	if imgwidth > 0 {
+		width = min(width, imgwidth)
+	}
+	if imgheight > 0 {
+		height = min(height, imgheight)
+	}
+
+	return img.
+		Width(width).
+		Height(height).
+		Scale(termimg.ScaleFit).
+		Protocol(protocol).
+		Render()
+}
@@ -0,0 +1,479 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+// Kinds of repository metadata offered in pickers.
+const (
+	repoLabels int = iota
+	repoAssignees
+	repoMilestones
+	repoBranches
+)
+
+// repoItemPages bounds how many pages of labels, assignees or branches are
+// listed.
+const repoItemPages = 10
+
+type repoItemsMsg struct {
+	Repo  string
+	Kind  int
+	Items []pickerItem
+	Err   error
+}
+
+type issueCreatedMsg struct {
+	Repo  string
+	Issue githubapi.Issue
+	Err   error
+}
+
+func fetchRepoItemsCmd(client *githubapi.Client, repo githubapi.Repository, kind int) tea.Cmd {
+	return func() tea.Msg {
+		var items []pickerItem
+		opts := githubapi.ListOptions{Page: 1, PerPage: 100}
+
+		switch kind {
+		case repoMilestones:
+			milestones, err := client.ListMilestones(repo, githubapi.IssueStateOpen)
+			if err != nil {
+				return repoItemsMsg{Repo: repo.FullName, Kind: kind, Err: err}
+			}
+			items = append(items, pickerItem{Label: "No milestone"})
+			for _, m := range milestones {
+				detail := ""
+				if m.DueOn != nil {
+					detail = "due " + formatDate(*m.DueOn)
+				}
+				items = append(items, pickerItem{Label: m.Title, Detail: detail, Value: strconv.Itoa(m.Number)})
+			}
+			return repoItemsMsg{Repo: repo.FullName, Kind: kind, Items: items}
+
+		case repoLabels:
+			for range repoItemPages {
+				labels, resp, err := client.ListLabels(repo, opts)
+				if err != nil {
+					return repoItemsMsg{Repo: repo.FullName, Kind: kind, Err: err}
+				}
+				for _, l := range labels {
+					items = append(items, pickerItem{Label: l.Name, Detail: l.Description, Value: l.Name})
+				}
+				if resp == nil || resp.NextPage == 0 {
+					break
+				}
+				opts.Page = resp.NextPage
+			}
+
+		case repoAssignees:
+			for range repoItemPages {
+				users, resp, err := client.ListAssignees(repo, opts)
+				if err != nil {
+					return repoItemsMsg{Repo: repo.FullName, Kind: kind, Err: err}
+				}
+				for _, u := range users {
+					items = append(items, pickerItem{Label: u.Login, Value: u.Login})
+				}
+				if resp == nil || resp.NextPage == 0 {
+					break
+				}
+				opts.Page = resp.NextPage
+			}
+
+		case repoBranches:
+			for range repoItemPages {
+				branches, resp, err := client.ListBranches(repo, opts)
+				if err != nil {
+					return repoItemsMsg{Repo: repo.FullName, Kind: kind, Err: err}
+				}
+				for _, b := range branches {
+					detail := ""
+					if b.Protected {
+						detail = "protected"
+					}
+					items = append(items, pickerItem{Label: b.Name, Detail: detail, Value: b.Name})
+				}
+				if resp == nil || resp.NextPage == 0 {
+					break
+				}
+				opts.Page = resp.NextPage
+			}
+		}
+		return repoItemsMsg{Repo: repo.FullName, Kind: kind, Items: items}
+	}
+}
+
+func createIssueCmd(client *githubapi.Client, repo githubapi.Repository, req githubapi.IssueRequest) tea.Cmd {
+	return func() tea.Msg {
+		issue, err := client.CreateIssue(repo, req)
+		return issueCreatedMsg{Repo: repo.FullName, Issue: issue, Err: err}
+	}
+}
+
+const (
+	composerTitle = iota
+	composerBody
+	composerLabels
+	composerAssignees
+	composerMilestone
+	composerSubmit
+)
+
+// issueComposerPage is the form for opening a new issue. The label,
+// assignee and milestone pickers are filled from the repository.
+type issueComposerPage struct {
+	Width  int
+	Height int
+
+	repo       githubapi.Repository
+	mode       int
+	focusIndex int
+	title      textinput.Model
+	body       textarea.Model
+
+	// items, itemErrs and chosen are indexed by the repo item kinds.
+	items    [3][]pickerItem
+	itemErrs [3]error
+	loaded   [3]bool
+	chosen   [3][]string
+
+	picker     *picker
+	pickerKind int
+
+	submitting bool
+	statusMsg  string
+	err        error
+
+	spinner spinner.Model
+	client  *githubapi.Client
+}
+
+func NewIssueComposerPage(client *githubapi.Client, repo githubapi.Repository) issueComposerPage {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	title := textinput.New()
+	title.Placeholder = "Title"
+	title.CharLimit = 256
+	title.Width = 60
+	title.Prompt = ""
+
+	body := textarea.New()
+	body.Placeholder = "Leave a comment (markdown)"
+	body.ShowLineNumbers = false
+	body.CharLimit = 0
+	body.SetWidth(62)
+	body.SetHeight(8)
+	body.FocusedStyle.CursorLine = lipgloss.NewStyle()
+
+	return issueComposerPage{
+		repo:    repo,
+		mode:    ModeNav,
+		title:   title,
+		body:    body,
+		spinner: s,
+		client:  client,
+	}
+}
+
+func (m issueComposerPage) Init() tea.Cmd {
+	return tea.Batch(
+		fetchRepoItemsCmd(m.client, m.repo, repoLabels),
+		fetchRepoItemsCmd(m.client, m.repo, repoAssignees),
+		fetchRepoItemsCmd(m.client, m.repo, repoMilestones),
+	)
+}
+
+func (m issueComposerPage) request() githubapi.IssueRequest {
+	req := githubapi.IssueRequest{
+		Title:     strings.TrimSpace(m.title.Value()),
+		Body:      m.body.Value(),
+		Labels:    m.chosen[repoLabels],
+		Assignees: m.chosen[repoAssignees],
+	}
+	if len(m.chosen[repoMilestones]) > 0 {
+		req.Milestone, _ = strconv.Atoi(m.chosen[repoMilestones][0])
+	}
+	return req
+}
+
+// openPicker shows the picker for kind, marked with the current choice.
+// The picker is rebuilt each time so that cancelling drops its changes.
+func (m *issueComposerPage) openPicker(kind int) {
+	var p picker
+	switch kind {
+	case repoLabels:
+		p = newMultiPicker("Labels", m.chosen[kind])
+	case repoAssignees:
+		p = newMultiPicker("Assignees", m.chosen[kind])
+	default:
+		p = newPicker("Milestone")
+	}
+	if m.loaded[kind] {
+		p.setItems(m.items[kind], m.itemErrs[kind])
+	}
+	m.picker = &p
+	m.pickerKind = kind
+}
+
+func (m issueComposerPage) submit() (issueComposerPage, tea.Cmd) {
+	req := m.request()
+	if req.Title == "" {
+		m.statusMsg = "An issue needs a title."
+		return m, nil
+	}
+	m.err = nil
+	m.statusMsg = ""
+	m.submitting = true
+	m.mode = ModeNav
+	m.title.Blur()
+	m.body.Blur()
+	return m, tea.Batch(m.spinner.Tick, createIssueCmd(m.client, m.repo, req))
+}
+
+func (m issueComposerPage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.submitting {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case repoItemsMsg:
+		if msg.Repo != m.repo.FullName {
+			return m, nil
+		}
+		m.items[msg.Kind] = msg.Items
+		m.itemErrs[msg.Kind] = msg.Err
+		m.loaded[msg.Kind] = true
+		if m.picker != nil && m.pickerKind == msg.Kind {
+			m.picker.setItems(msg.Items, msg.Err)
+		}
+		return m, nil
+
+	case issueCreatedMsg:
+		if msg.Repo != m.repo.FullName || !m.submitting {
+			return m, nil
+		}
+		m.submitting = false
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		// The issues page picks up issueCreatedMsg too and opens the
+		// new issue.
+		return m, func() tea.Msg {
+			return NavMsg{to: IssuesPage, from: IssueComposerPage, repodata: m.repo, back: true}
+		}
+
+	case tea.KeyMsg:
+		return m.updateKeys(msg)
+	}
+	return m, nil
+}
+
+func (m issueComposerPage) updateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.submitting {
+		return m, nil
+	}
+
+	if m.picker != nil {
+		p, item, done, cmd := m.picker.update(msg)
+		m.picker = &p
+		if done {
+			if item != nil {
+				switch {
+				case p.multi:
+					m.chosen[m.pickerKind] = p.selection()
+				case item.Value == "":
+					m.chosen[m.pickerKind] = nil
+				default:
+					m.chosen[m.pickerKind] = []string{item.Value}
+				}
+			}
+			m.picker = nil
+		}
+		return m, cmd
+	}
+
+	if msg.String() == "ctrl+s" {
+		return m.submit()
+	}
+
+	if m.mode == ModeEdit {
+		if msg.String() == "esc" || (msg.String() == "enter" && m.focusIndex == composerTitle) {
+			m.mode = ModeNav
+			m.title.Blur()
+			m.body.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		if m.focusIndex == composerTitle {
+			m.title, cmd = m.title.Update(msg)
+		} else {
+			m.body, cmd = m.body.Update(msg)
+		}
+		return m, cmd
+	}
+
+	if m.err != nil && msg.String() == "r" {
+		return m.submit()
+	}
+
+	switch msg.String() {
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavMsg{to: IssuesPage, from: IssueComposerPage, repodata: m.repo, back: true}
+		}
+
+	case "tab", "shift+tab", "up", "down", "k", "j":
+		s := msg.String()
+		if s == "up" || s == "shift+tab" || s == "k" {
+			m.focusIndex--
+		} else {
+			m.focusIndex++
+		}
+		if m.focusIndex > composerSubmit {
+			m.focusIndex = 0
+		} else if m.focusIndex < 0 {
+			m.focusIndex = composerSubmit
+		}
+
+	case "enter", " ":
+		switch m.focusIndex {
+		case composerTitle:
+			m.mode = ModeEdit
+			return m, m.title.Focus()
+		case composerBody:
+			m.mode = ModeEdit
+			return m, m.body.Focus()
+		case composerLabels:
+			m.openPicker(repoLabels)
+		case composerAssignees:
+			m.openPicker(repoAssignees)
+		case composerMilestone:
+			m.openPicker(repoMilestones)
+		case composerSubmit:
+			return m.submit()
+		}
+	}
+	return m, nil
+}
+
+// chosenLabel shows the current choice for kind by its picker label.
+func (m issueComposerPage) chosenLabel(kind int) string {
+	if len(m.chosen[kind]) == 0 {
+		return "None"
+	}
+	if kind != repoMilestones {
+		return strings.Join(m.chosen[kind], ", ")
+	}
+	for _, item := range m.items[kind] {
+		if item.Value == m.chosen[kind][0] {
+			return item.Label
+		}
+	}
+	return m.chosen[kind][0]
+}
+
+func (m issueComposerPage) View() string {
+	if m.picker != nil {
+		return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, m.picker.View(m.Width-4, m.Height-4))
+	}
+
+	activeStyle := lipgloss.NewStyle().Foreground(highlight)
+	inactiveStyle := lipgloss.NewStyle().Foreground(subtle)
+	valueStyle := lipgloss.NewStyle().Foreground(text)
+
+	modeStr := "NAV"
+	modeColor := subtle
+	if m.mode == ModeEdit {
+		modeStr = "EDIT"
+		modeColor = special
+	}
+	modeIndicator := lipgloss.NewStyle().Foreground(modeColor).Bold(true).Render("-- " + modeStr + " --")
+
+	labelFor := func(label string, field int) string {
+		style := inactiveStyle
+		if m.focusIndex == field {
+			style = activeStyle
+		}
+		if m.focusIndex == field && m.mode == ModeEdit {
+			label += " ✐"
+		}
+		return style.Render(label)
+	}
+
+	renderChoice := func(label string, field, kind int) string {
+		value := m.chosenLabel(kind)
+		if m.itemErrs[kind] != nil {
+			value += lipgloss.NewStyle().Foreground(warning).Render("  (" + errorTitle(m.itemErrs[kind]) + ")")
+		}
+		return fmt.Sprintf("%s %s", lipgloss.NewStyle().Width(11).Render(labelFor(label, field)), valueStyle.Render(value))
+	}
+
+	submitBtn := "[ Submit New Issue ]"
+	if m.focusIndex == composerSubmit {
+		submitBtn = activeStyle.Bold(true).Render(submitBtn)
+	} else {
+		submitBtn = inactiveStyle.Render(submitBtn)
+	}
+
+	statusDisplay := ""
+	switch {
+	case m.submitting:
+		statusDisplay = fmt.Sprintf("%s Creating issue...", m.spinner.View())
+	case m.err != nil:
+		statusDisplay = renderErrorState(m.err, 60)
+	case m.statusMsg != "":
+		statusDisplay = lipgloss.NewStyle().Foreground(warning).Bold(true).Render(m.statusMsg)
+	}
+
+	formContent := lipgloss.JoinVertical(lipgloss.Left,
+		heading.Render("New Issue · "+m.repo.FullName),
+		"",
+		modeIndicator,
+		"",
+		labelFor("Title", composerTitle),
+		m.title.View(),
+		"",
+		labelFor("Description", composerBody),
+		m.body.View(),
+		"",
+		renderChoice("Labels", composerLabels, repoLabels),
+		renderChoice("Assignees", composerAssignees, repoAssignees),
+		renderChoice("Milestone", composerMilestone, repoMilestones),
+		"",
+		submitBtn,
+		"",
+		statusDisplay,
+		inactiveStyle.Render("enter edit/choose • esc leave field • ctrl+s submit • backspace cancel"),
+	)
+
+	return lipgloss.Place(
+		m.Width, m.Height,
+		lipgloss.Center, lipgloss.Center,
+		window.Render(formContent),
+	)
+}
+
+func (m issueComposerPage) editing() bool {
+	return m.mode == ModeEdit || m.picker != nil
+}
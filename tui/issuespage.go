@@ -0,0 +1,930 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+type issuesMsg struct {
+	Repo     string
+	Request  int
+	Page     int
+	NextPage int
+	Issues   []githubapi.Issue
+	Err      error
+}
+
+// linkedPR is a pull request that mentions an issue, found through the
+// issue's timeline.
+type linkedPR struct {
+	Repo   string
+	Number int
+	Title  string
+	State  string
+	Merged bool
+	URL    string
+}
+
+type threadMsg struct {
+	Repo     string
+	Number   int
+	Issue    githubapi.Issue
+	Comments []githubapi.IssueComment
+	Linked   []linkedPR
+	Err      error
+}
+
+// issueUpdatedMsg carries an issue after a state or label change.
+type issueUpdatedMsg struct {
+	Repo  string
+	Issue githubapi.Issue
+	Err   error
+}
+
+type commentCreatedMsg struct {
+	Repo    string
+	Number  int
+	Comment githubapi.IssueComment
+	Err     error
+}
+
+const (
+	issuesList int = iota
+	issuesFilter
+	issuesThread
+)
+
+const (
+	pickLabels int = iota
+	pickCloseReason
+)
+
+const (
+	issuesChrome = 6
+	// threadPages bounds how many pages of comments and timeline events
+	// are read for one thread.
+	threadPages = 10
+)
+
+type IssuesPageModel struct {
+	Width  int
+	Height int
+
+	repo     githubapi.Repository
+	CameFrom int
+	filters  filterPanel
+	opts     githubapi.IssueListOptions
+
+	issues      []githubapi.Issue
+	cursor      int
+	windowStart int
+	listPager
+
+	mode          int
+	thread        *githubapi.Issue
+	comments      []githubapi.IssueComment
+	linked        []linkedPR
+	threadErr     error
+	loadingThread bool
+	commentLines  []int
+	threadView    viewport.Model
+
+	// Thread actions: the label or close reason picker, the comment box
+	// and the outcome of the last write.
+	picker     *picker
+	pickerKind int
+	composing  bool
+	comment    textarea.Model
+	posting    bool
+	action     string
+
+	spinner spinner.Model
+	client  *githubapi.Client
+}
+
+func NewIssuesPageModel(client *githubapi.Client, repo githubapi.Repository, camefrom int) IssuesPageModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	filters := newIssueFilterPanel()
+
+	comment := textarea.New()
+	comment.Placeholder = "Leave a comment (markdown)"
+	comment.ShowLineNumbers = false
+	comment.CharLimit = 0
+	comment.SetWidth(readmeWrap)
+	comment.SetHeight(10)
+	comment.FocusedStyle.CursorLine = lipgloss.NewStyle()
+
+	m := IssuesPageModel{
+		repo:       repo,
+		CameFrom:   camefrom,
+		filters:    filters,
+		opts:       filters.issueOptions(),
+		mode:       issuesList,
+		threadView: viewport.New(0, 0),
+		comment:    comment,
+		spinner:    s,
+		client:     client,
+	}
+	m.restart()
+	return m
+}
+
+func fetchIssuesCmd(client *githubapi.Client, repo githubapi.Repository, opts githubapi.IssueListOptions, request, page int) tea.Cmd {
+	opts.Page = page
+	opts.PerPage = githubapi.DefaultPerPage
+	return func() tea.Msg {
+		milestone, err := client.ResolveMilestone(repo, opts.Milestone)
+		if err != nil {
+			return issuesMsg{Repo: repo.FullName, Request: request, Page: page, Err: err}
+		}
+		opts.Milestone = milestone
+
+		issues, resp, err := client.ListIssues(repo, opts)
+		msg := issuesMsg{Repo: repo.FullName, Request: request, Page: page, Issues: issues, Err: err}
+		if resp != nil {
+			msg.NextPage = resp.NextPage
+		}
+		return msg
+	}
+}
+
+func fetchThreadCmd(client *githubapi.Client, repo githubapi.Repository, number int) tea.Cmd {
+	return func() tea.Msg {
+		issue, err := client.GetIssue(repo, number)
+		if err != nil {
+			return threadMsg{Repo: repo.FullName, Number: number, Err: err}
+		}
+
+		var comments []githubapi.IssueComment
+		opts := githubapi.ListOptions{Page: 1, PerPage: 100}
+		for range threadPages {
+			page, resp, err := client.ListIssueComments(repo, number, opts)
+			if err != nil {
+				return threadMsg{Repo: repo.FullName, Number: number, Err: err}
+			}
+			comments = append(comments, page...)
+			if resp == nil || resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+
+		return threadMsg{Repo: repo.FullName, Number: number, Issue: issue, Comments: comments, Linked: linkedPullRequests(client, repo, number)}
+	}
+}
+
+func setIssueStateCmd(client *githubapi.Client, repo githubapi.Repository, number int, state, reason string) tea.Cmd {
+	return func() tea.Msg {
+		issue, err := client.SetIssueState(repo, number, state, reason)
+		return issueUpdatedMsg{Repo: repo.FullName, Issue: issue, Err: err}
+	}
+}
+
+// setIssueLabelsCmd replaces the labels on issue and reports it with the
+// labels GitHub now has.
+func setIssueLabelsCmd(client *githubapi.Client, repo githubapi.Repository, issue githubapi.Issue, names []string) tea.Cmd {
+	return func() tea.Msg {
+		labels, err := client.SetIssueLabels(repo, issue.Number, names)
+		issue.Labels = labels
+		return issueUpdatedMsg{Repo: repo.FullName, Issue: issue, Err: err}
+	}
+}
+
+func createCommentCmd(client *githubapi.Client, repo githubapi.Repository, number int, body string) tea.Cmd {
+	return func() tea.Msg {
+		comment, err := client.CreateIssueComment(repo, number, body)
+		return commentCreatedMsg{Repo: repo.FullName, Number: number, Comment: comment, Err: err}
+	}
+}
+
+// linkedPullRequests collects the pull requests that cross-reference the
+// issue. The timeline is best effort: a failure only hides this section.
+func linkedPullRequests(client *githubapi.Client, repo githubapi.Repository, number int) []linkedPR {
+	var linked []linkedPR
+	seen := make(map[string]bool)
+	opts := githubapi.ListOptions{Page: 1, PerPage: 100}
+	for range threadPages {
+		events, resp, err := client.ListIssueTimeline(repo, number, opts)
+		if err != nil {
+			break
+		}
+		for _, e := range events {
+			if e.Event != "cross-referenced" || e.Source == nil || e.Source.Issue == nil || !e.Source.Issue.IsPullRequest() {
+				continue
+			}
+			src := e.Source.Issue
+			pr := linkedPR{
+				Repo:   repo.FullName,
+				Number: src.Number,
+				Title:  src.Title,
+				State:  src.State,
+				Merged: src.PullRequest.MergedAt != nil,
+				URL:    src.HTMLURL,
+			}
+			if src.Repository != nil {
+				pr.Repo = src.Repository.FullName
+			}
+			key := pr.Repo + "#" + strconv.Itoa(pr.Number)
+			if !seen[key] {
+				seen[key] = true
+				linked = append(linked, pr)
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return linked
+}
+
+func (m IssuesPageModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, fetchIssuesCmd(m.client, m.repo, m.opts, m.request, 1))
+}
+
+func (m IssuesPageModel) listHeight() int {
+	return max(m.Height-issuesChrome, 3)
+}
+
+func (m *IssuesPageModel) reload() tea.Cmd {
+	m.issues = nil
+	m.cursor = 0
+	m.windowStart = 0
+	request := m.restart()
+	return tea.Batch(m.spinner.Tick, fetchIssuesCmd(m.client, m.repo, m.opts, request, 1))
+}
+
+func (m *IssuesPageModel) maybeLoadMore() tea.Cmd {
+	if !m.wantMore(m.cursor, len(m.issues)) {
+		return nil
+	}
+	return tea.Batch(m.spinner.Tick, fetchIssuesCmd(m.client, m.repo, m.opts, m.request, m.nextPage))
+}
+
+func (m *IssuesPageModel) moveCursor(step int) {
+	if len(m.issues) == 0 {
+		return
+	}
+	m.cursor = max(0, min(m.cursor+step, len(m.issues)-1))
+	if m.cursor < m.windowStart {
+		m.windowStart = m.cursor
+	}
+	if m.cursor >= m.windowStart+m.listHeight() {
+		m.windowStart = m.cursor - m.listHeight() + 1
+	}
+}
+
+func (m *IssuesPageModel) refreshThread() {
+	var content string
+	content, m.commentLines = m.renderThread()
+	m.threadView.SetContent(content)
+}
+
+func (m IssuesPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.threadView.Width = m.Width
+		m.threadView.Height = max(m.Height-2, 1)
+		if m.thread != nil {
+			m.refreshThread()
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading && !m.loadingMore && !m.loadingThread && !m.posting {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case issuesMsg:
+		if msg.Repo != m.repo.FullName || !m.landed(msg.Request, msg.Page, msg.NextPage, msg.Err) {
+			return m, nil
+		}
+		if msg.Err == nil {
+			if msg.Page == 1 {
+				m.issues = msg.Issues
+			} else {
+				m.issues = append(m.issues, msg.Issues...)
+			}
+		}
+		return m, m.maybeLoadMore()
+
+	case threadMsg:
+		if msg.Repo != m.repo.FullName || m.mode != issuesThread || m.thread == nil || m.thread.Number != msg.Number {
+			return m, nil
+		}
+		m.loadingThread = false
+		m.threadErr = msg.Err
+		if msg.Err == nil {
+			issue := msg.Issue
+			m.thread = &issue
+			m.comments = msg.Comments
+			m.linked = msg.Linked
+		}
+		m.refreshThread()
+		return m, nil
+
+	case issueCreatedMsg:
+		if msg.Repo != m.repo.FullName || msg.Err != nil {
+			return m, nil
+		}
+		m.openThread(msg.Issue)
+		return m, tea.Batch(m.reload(), fetchThreadCmd(m.client, m.repo, msg.Issue.Number))
+
+	case issueUpdatedMsg:
+		if msg.Repo != m.repo.FullName || !m.posting {
+			return m, nil
+		}
+		m.posting = false
+		if msg.Err != nil {
+			m.action = actionError(msg.Err)
+			return m, nil
+		}
+		for i := range m.issues {
+			if m.issues[i].Number == msg.Issue.Number {
+				m.issues[i] = msg.Issue
+			}
+		}
+		if m.thread != nil && m.thread.Number == msg.Issue.Number {
+			issue := msg.Issue
+			m.thread = &issue
+			m.refreshThread()
+		}
+		m.action = lipgloss.NewStyle().Foreground(special).Render(fmt.Sprintf("Updated #%d.", msg.Issue.Number))
+		return m, nil
+
+	case commentCreatedMsg:
+		if msg.Repo != m.repo.FullName || !m.posting {
+			return m, nil
+		}
+		m.posting = false
+		if msg.Err != nil {
+			m.action = actionError(msg.Err)
+			return m, nil
+		}
+		m.composing = false
+		m.comment.Reset()
+		m.comment.Blur()
+		m.action = lipgloss.NewStyle().Foreground(special).Render("Comment posted.")
+		if m.thread != nil && m.thread.Number == msg.Number {
+			m.thread.Comments++
+			m.comments = append(m.comments, msg.Comment)
+			m.refreshThread()
+			m.threadView.GotoBottom()
+		}
+		return m, nil
+
+	case repoItemsMsg:
+		if msg.Repo == m.repo.FullName && msg.Kind == repoLabels && m.picker != nil && m.pickerKind == pickLabels {
+			m.picker.setItems(msg.Items, msg.Err)
+		}
+		return m, nil
+
+	case browserMsg:
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case issuesFilter:
+			return m.updateFilter(msg)
+		case issuesThread:
+			return m.updateThread(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m IssuesPageModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var (
+		cmd    tea.Cmd
+		closed bool
+	)
+	m.filters, cmd, closed = m.filters.Update(msg)
+	if !closed {
+		return m, cmd
+	}
+	m.mode = issuesList
+	m.opts = m.filters.issueOptions()
+	return m, m.reload()
+}
+
+func (m IssuesPageModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		m.moveCursor(1)
+		return m, m.maybeLoadMore()
+	case "k", "up":
+		m.moveCursor(-1)
+	case "d", "ctrl+d":
+		m.moveCursor(m.listHeight() / 2)
+		return m, m.maybeLoadMore()
+	case "u", "ctrl+u":
+		m.moveCursor(-m.listHeight() / 2)
+	case "f":
+		m.mode = issuesFilter
+	case "r":
+		if m.err != nil {
+			return m, m.reload()
+		}
+		if m.moreErr != nil {
+			m.moreErr = nil
+			return m, m.maybeLoadMore()
+		}
+	case "o":
+		if len(m.issues) > 0 {
+			return m, openBrowserCmd(m.issues[m.cursor].HTMLURL)
+		}
+	case "enter":
+		if len(m.issues) == 0 {
+			return m, nil
+		}
+		issue := m.issues[m.cursor]
+		m.openThread(issue)
+		return m, tea.Batch(m.spinner.Tick, fetchThreadCmd(m.client, m.repo, issue.Number))
+	case "n":
+		return m, func() tea.Msg {
+			return NavMsg{to: IssueComposerPage, from: IssuesPage, repodata: m.repo}
+		}
+	case "backspace":
+		return m, func() tea.Msg {
+			return NavMsg{to: m.CameFrom, from: IssuesPage, repodata: m.repo, back: true}
+		}
+	}
+	return m, nil
+}
+
+// openThread switches to the thread view for issue, which is shown as it
+// is until the full thread arrives.
+func (m *IssuesPageModel) openThread(issue githubapi.Issue) {
+	m.mode = issuesThread
+	m.thread = &issue
+	m.comments = nil
+	m.linked = nil
+	m.threadErr = nil
+	m.action = ""
+	m.loadingThread = true
+	m.refreshThread()
+	m.threadView.GotoTop()
+}
+
+func actionError(err error) string {
+	return lipgloss.NewStyle().Foreground(warning).Render(errorTitle(err) + ": " + err.Error())
+}
+
+func (m IssuesPageModel) updateComment(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.posting {
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.composing = false
+		m.comment.Blur()
+		return m, nil
+	case "ctrl+s":
+		body := strings.TrimSpace(m.comment.Value())
+		if body == "" || m.thread == nil {
+			return m, nil
+		}
+		m.posting = true
+		m.action = ""
+		return m, tea.Batch(m.spinner.Tick, createCommentCmd(m.client, m.repo, m.thread.Number, body))
+	}
+	var cmd tea.Cmd
+	m.comment, cmd = m.comment.Update(msg)
+	return m, cmd
+}
+
+func (m IssuesPageModel) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p, item, done, cmd := m.picker.update(msg)
+	m.picker = &p
+	if !done {
+		return m, cmd
+	}
+	m.picker = nil
+	if item == nil || m.thread == nil {
+		return m, nil
+	}
+	m.posting = true
+	m.action = ""
+	if m.pickerKind == pickLabels {
+		return m, tea.Batch(m.spinner.Tick, setIssueLabelsCmd(m.client, m.repo, *m.thread, p.selection()))
+	}
+	return m, tea.Batch(m.spinner.Tick, setIssueStateCmd(m.client, m.repo, m.thread.Number, githubapi.IssueStateClosed, item.Value))
+}
+
+func (m IssuesPageModel) updateThread(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.composing {
+		return m.updateComment(msg)
+	}
+	if m.picker != nil {
+		return m.updatePicker(msg)
+	}
+
+	switch msg.String() {
+	case "backspace", "esc":
+		m.mode = issuesList
+		m.thread = nil
+		return m, nil
+	case "c":
+		if m.thread == nil || m.posting {
+			return m, nil
+		}
+		m.composing = true
+		m.action = ""
+		return m, m.comment.Focus()
+	case "x":
+		if m.thread == nil || m.posting {
+			return m, nil
+		}
+		if m.thread.State == githubapi.IssueStateClosed {
+			m.posting = true
+			m.action = ""
+			return m, tea.Batch(m.spinner.Tick, setIssueStateCmd(m.client, m.repo, m.thread.Number, githubapi.IssueStateOpen, githubapi.StateReasonReopened))
+		}
+		p := newPicker(fmt.Sprintf("Close #%d as", m.thread.Number))
+		p.setItems([]pickerItem{
+			{Label: "Completed", Detail: "done, closed, fixed, resolved", Value: githubapi.StateReasonCompleted},
+			{Label: "Not planned", Detail: "won't fix, can't repro, duplicate, stale", Value: githubapi.StateReasonNotPlanned},
+		}, nil)
+		m.picker = &p
+		m.pickerKind = pickCloseReason
+	case "l":
+		if m.thread == nil || m.posting {
+			return m, nil
+		}
+		var current []string
+		for _, l := range m.thread.Labels {
+			current = append(current, l.Name)
+		}
+		p := newMultiPicker(fmt.Sprintf("Labels on #%d", m.thread.Number), current)
+		m.picker = &p
+		m.pickerKind = pickLabels
+		return m, fetchRepoItemsCmd(m.client, m.repo, repoLabels)
+	case "j", "down":
+		m.threadView.ScrollDown(1)
+	case "k", "up":
+		m.threadView.ScrollUp(1)
+	case "d", "ctrl+d":
+		m.threadView.HalfPageDown()
+	case "u", "ctrl+u":
+		m.threadView.HalfPageUp()
+	case "g":
+		m.threadView.GotoTop()
+	case "G":
+		m.threadView.GotoBottom()
+	case "n":
+		for _, line := range m.commentLines {
+			if line > m.threadView.YOffset {
+				m.threadView.SetYOffset(line)
+				break
+			}
+		}
+	case "N":
+		for i := len(m.commentLines) - 1; i >= 0; i-- {
+			if m.commentLines[i] < m.threadView.YOffset {
+				m.threadView.SetYOffset(m.commentLines[i])
+				break
+			}
+		}
+	case "o":
+		if m.thread != nil {
+			return m, openBrowserCmd(m.thread.HTMLURL)
+		}
+	case "r":
+		if m.threadErr != nil {
+			m.threadErr = nil
+			m.loadingThread = true
+			m.refreshThread()
+			return m, tea.Batch(m.spinner.Tick, fetchThreadCmd(m.client, m.repo, m.thread.Number))
+		}
+	}
+	return m, nil
+}
+
+// labelChip renders a label in its GitHub colour, with black or white text
+// depending on how light the colour is.
+func labelChip(l githubapi.Label) string {
+	fg := lipgloss.Color("#FFFFFF")
+	if r, g, b, ok := parseHexColor(l.Color); ok && (299*r+587*g+114*b)/1000 > 150 {
+		fg = lipgloss.Color("#000000")
+	}
+	return lipgloss.NewStyle().
+		Background(lipgloss.Color("#"+l.Color)).
+		Foreground(fg).
+		Padding(0, 1).
+		Render(l.Name)
+}
+
+func parseHexColor(hex string) (int, int, int, bool) {
+	v, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(hex, "#")) != 6 {
+		return 0, 0, 0, false
+	}
+	return int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff), true
+}
+
+func labelChips(labels []githubapi.Label) string {
+	var chips []string
+	for _, l := range labels {
+		chips = append(chips, labelChip(l))
+	}
+	return strings.Join(chips, " ")
+}
+
+func reactionsLine(r githubapi.Reactions) string {
+	if r.TotalCount == 0 {
+		return ""
+	}
+	counts := []struct {
+		emoji string
+		n     int
+	}{
+		{"👍", r.PlusOne}, {"👎", r.MinusOne}, {"😄", r.Laugh}, {"🎉", r.Hooray},
+		{"😕", r.Confused}, {"❤️", r.Heart}, {"🚀", r.Rocket}, {"👀", r.Eyes},
+	}
+	var parts []string
+	for _, c := range counts {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", c.emoji, c.n))
+		}
+	}
+	return lipgloss.NewStyle().Foreground(subtle).Render(strings.Join(parts, "  "))
+}
+
+func issueState(issue githubapi.Issue) string {
+	if issue.State == githubapi.IssueStateOpen {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#43BF6D")).Render("●")
+	}
+	if issue.StateReason == "not_planned" {
+		return lipgloss.NewStyle().Foreground(subtle).Render("⊘")
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#A371F7")).Render("✓")
+}
+
+func issueStateBadge(issue githubapi.Issue) string {
+	label, color := "Open", lipgloss.Color("#43BF6D")
+	if issue.State == githubapi.IssueStateClosed {
+		label, color = "Closed", lipgloss.Color("#A371F7")
+		if issue.StateReason == "not_planned" {
+			label, color = "Closed as not planned", lipgloss.Color("#6E7681")
+		}
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Background(color).Bold(true).Padding(0, 1).Render(label)
+}
+
+func (m IssuesPageModel) renderHeader() string {
+	title := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(m.repo.FullName + " · issues")
+
+	scope := []string{m.opts.State}
+	if scope[0] == "" {
+		scope[0] = githubapi.IssueStateOpen
+	}
+	if m.opts.Labels != "" {
+		scope = append(scope, "labelled "+m.opts.Labels)
+	}
+	if m.opts.Assignee != "" {
+		scope = append(scope, "assigned to "+m.opts.Assignee)
+	}
+	if m.opts.Milestone != "" {
+		scope = append(scope, "in milestone "+m.opts.Milestone)
+	}
+	if m.opts.Creator != "" {
+		scope = append(scope, "opened by "+m.opts.Creator)
+	}
+	scope = append(scope, "sorted by "+m.opts.Sort)
+	return lipgloss.JoinVertical(lipgloss.Left, title, lipgloss.NewStyle().Foreground(subtle).Render(strings.Join(scope, " · ")))
+}
+
+func (m IssuesPageModel) renderRow(i int) string {
+	issue := m.issues[i]
+	numStyle := lipgloss.NewStyle().Foreground(special)
+	metaStyle := lipgloss.NewStyle().Foreground(subtle)
+	titleStyle := lipgloss.NewStyle().Foreground(text)
+	pointer := "  "
+	if i == m.cursor {
+		pointer = "> "
+		titleStyle = titleStyle.Foreground(highlight).Bold(true)
+	}
+
+	meta := fmt.Sprintf("%s · %s", issue.User.Login, formatDate(issue.CreatedAt))
+	if issue.Comments > 0 {
+		meta += fmt.Sprintf(" · 💬 %d", issue.Comments)
+	}
+	meta = metaStyle.Render(meta)
+	labels := labelChips(issue.Labels)
+	if labels != "" {
+		labels = " " + labels
+	}
+
+	room := m.Width - lipgloss.Width(meta) - lipgloss.Width(labels) - 14
+	title := ansi.Truncate(issue.Title, max(room, 10), "…")
+	row := fmt.Sprintf("%s%s %s %s%s  %s", pointer, issueState(issue), numStyle.Render(fmt.Sprintf("#%d", issue.Number)), titleStyle.Render(title), labels, meta)
+	return ansi.Truncate(row, m.Width, "…")
+}
+
+func (m IssuesPageModel) renderList() string {
+	switch {
+	case m.loading:
+		return fmt.Sprintf("%s Loading issues...", m.spinner.View())
+	case m.err != nil:
+		return renderErrorState(m.err, m.Width-4)
+	case len(m.issues) == 0:
+		return lipgloss.NewStyle().Foreground(subtle).Render("No issues match these filters.")
+	}
+
+	var rows []string
+	end := min(m.windowStart+m.listHeight(), len(m.issues))
+	for i := m.windowStart; i < end; i++ {
+		rows = append(rows, m.renderRow(i))
+	}
+
+	status := fmt.Sprintf("%d issues loaded", len(m.issues))
+	switch {
+	case m.loadingMore:
+		status += fmt.Sprintf(" • %s loading more", m.spinner.View())
+	case m.moreErr != nil:
+		status += " • failed to load more (" + errorTitle(m.moreErr) + "), r to retry"
+	case m.nextPage == 0:
+		status += " • end of list"
+	}
+	rows = append(rows, "", lipgloss.NewStyle().Foreground(subtle).Render(status))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderPost renders one entry of a thread: a header naming the author,
+// the markdown body and its reactions.
+func renderPost(author, association, when, body string, reactions githubapi.Reactions) string {
+	head := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(author)
+	if association != "" && association != "NONE" {
+		head += " " + lipgloss.NewStyle().Foreground(special).Render(strings.ToLower(association))
+	}
+	head += lipgloss.NewStyle().Foreground(subtle).Render(" · " + when)
+
+	rendered := lipgloss.NewStyle().Foreground(subtle).Italic(true).Render("  No description provided.")
+	if strings.TrimSpace(body) != "" {
+		if out, err := renderMarkdown(body); err == nil {
+			rendered = strings.TrimRight(out, "\n")
+		} else {
+			rendered = body
+		}
+	}
+
+	lines := []string{head, rendered}
+	if r := reactionsLine(reactions); r != "" {
+		lines = append(lines, "  "+r)
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(subtle).
+		Padding(0, 1).
+		Width(readmeWrap + 4).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// renderThread renders the issue and its comments, returning the line each
+// comment starts on for n/N navigation.
+func (m IssuesPageModel) renderThread() (string, []int) {
+	if m.thread == nil {
+		return "", nil
+	}
+	issue := m.thread
+	label := lipgloss.NewStyle().Foreground(subtle).Bold(true)
+	value := lipgloss.NewStyle().Foreground(text)
+
+	lines := []string{
+		lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(fmt.Sprintf("%s #%d", issue.Title, issue.Number)),
+		issueStateBadge(*issue) + value.Render(fmt.Sprintf("  %s opened this on %s · %d comments", issue.User.Login, formatDate(issue.CreatedAt), issue.Comments)),
+		"",
+	}
+	if len(issue.Labels) > 0 {
+		lines = append(lines, label.Render("Labels:    ")+labelChips(issue.Labels))
+	}
+	if len(issue.Assignees) > 0 {
+		var logins []string
+		for _, a := range issue.Assignees {
+			logins = append(logins, a.Login)
+		}
+		lines = append(lines, label.Render("Assignees: ")+value.Render(strings.Join(logins, ", ")))
+	}
+	if issue.Milestone != nil {
+		lines = append(lines, label.Render("Milestone: ")+value.Render(issue.Milestone.Title))
+	}
+	if len(m.linked) > 0 {
+		lines = append(lines, label.Render("Linked pull requests:"))
+		for _, pr := range m.linked {
+			state := lipgloss.NewStyle().Foreground(lipgloss.Color("#43BF6D")).Render("open")
+			switch {
+			case pr.Merged:
+				state = lipgloss.NewStyle().Foreground(lipgloss.Color("#A371F7")).Render("merged")
+			case pr.State == githubapi.IssueStateClosed:
+				state = lipgloss.NewStyle().Foreground(lipgloss.Color("#E05252")).Render("closed")
+			}
+			ref := fmt.Sprintf("#%d", pr.Number)
+			if pr.Repo != m.repo.FullName {
+				ref = pr.Repo + ref
+			}
+			lines = append(lines, fmt.Sprintf("  %s %s %s", lipgloss.NewStyle().Foreground(special).Render(ref), value.Render(pr.Title), state))
+		}
+	}
+	lines = append(lines, "", renderPost(issue.User.Login, issue.AuthorAssociation, formatDate(issue.CreatedAt), issue.Body, issue.Reactions))
+
+	var commentLines []int
+	switch {
+	case m.loadingThread:
+		lines = append(lines, "", fmt.Sprintf("%s Loading comments...", m.spinner.View()))
+	case m.threadErr != nil:
+		lines = append(lines, "", renderErrorState(m.threadErr, m.Width-4))
+	default:
+		offset := lipgloss.Height(strings.Join(lines, "\n"))
+		for _, c := range m.comments {
+			post := renderPost(c.User.Login, c.AuthorAssociation, formatDate(c.CreatedAt), c.Body, c.Reactions)
+			commentLines = append(commentLines, offset+1)
+			lines = append(lines, "", post)
+			offset += 1 + lipgloss.Height(post)
+		}
+	}
+	return strings.Join(lines, "\n"), commentLines
+}
+
+func (m IssuesPageModel) View() string {
+	hint := lipgloss.NewStyle().Foreground(subtle)
+
+	switch m.mode {
+	case issuesThread:
+		if m.picker != nil {
+			return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, m.picker.View(m.Width-4, m.Height-4))
+		}
+		if m.composing {
+			return m.renderComment()
+		}
+		status := hint.Render("j/k d/u scroll • n/N next/previous comment • c comment • x close/reopen • l labels • o open on web • backspace back to list")
+		switch {
+		case m.posting:
+			status = fmt.Sprintf("%s Saving...", m.spinner.View())
+		case m.action != "":
+			status = m.action
+		}
+		return lipgloss.JoinVertical(lipgloss.Left,
+			m.threadView.View(),
+			"",
+			status,
+		)
+	case issuesFilter:
+		return lipgloss.JoinVertical(lipgloss.Left,
+			m.renderHeader(),
+			"",
+			m.filters.View(min(m.Width-4, 60)),
+		)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.renderHeader(),
+		"",
+		m.renderList(),
+		"",
+		hint.Render("j/k move • enter open thread • n new issue • f filter • o open on web • backspace back"),
+	)
+}
+
+func (m IssuesPageModel) renderComment() string {
+	status := lipgloss.NewStyle().Foreground(subtle).Render("ctrl+s post • esc cancel")
+	switch {
+	case m.posting:
+		status = fmt.Sprintf("%s Posting comment...", m.spinner.View())
+	case m.action != "":
+		status = m.action
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(fmt.Sprintf("Comment on #%d %s", m.thread.Number, ansi.Truncate(m.thread.Title, readmeWrap-20, "…"))),
+			"",
+			m.comment.View(),
+			"",
+			status,
+		))
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m IssuesPageModel) editing() bool {
+	return (m.mode == issuesFilter && m.filters.editing()) || m.composing || m.picker != nil
+}
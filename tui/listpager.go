@@ -0,0 +1,56 @@
+package tui
+
+// listPager is the pagination state of a list page, embedded so that its
+// fields read as the page's own. Each load from the first page gets a new
+// request ID; pages fetched for an earlier load, or by another page of the
+// same kind in the history, carry a different one and are dropped.
+type listPager struct {
+	request     int
+	nextPage    int
+	loading     bool
+	loadingMore bool
+	err         error
+	moreErr     error
+}
+
+// restart begins a new load from the first page and returns its ID.
+func (p *listPager) restart() int {
+	*p = listPager{request: nextRequestID(), loading: true}
+	return p.request
+}
+
+// wantMore reports whether the next page should be fetched now that the
+// cursor is at cursor of loaded items, and marks it as loading if so.
+func (p *listPager) wantMore(cursor, loaded int) bool {
+	if p.loading || p.loadingMore || p.nextPage == 0 || p.moreErr != nil || cursor < loaded-loadMoreThreshold {
+		return false
+	}
+	p.loadingMore = true
+	return true
+}
+
+// landed records the arrival of page of load request. It returns false for
+// pages of another load and ones not asked for; otherwise the caller
+// replaces its list with page 1, or appends later pages, unless err is set.
+func (p *listPager) landed(request, page, nextPage int, err error) bool {
+	if request != p.request {
+		return false
+	}
+	if page == 1 {
+		if !p.loading {
+			return false
+		}
+		p.loading = false
+		p.err = err
+	} else {
+		if !p.loadingMore {
+			return false
+		}
+		p.loadingMore = false
+		p.moreErr = err
+	}
+	if err == nil {
+		p.nextPage = nextPage
+	}
+	return true
+}
@@ -28,6 +28,15 @@ const (
 	RepoPage
 	UserPage
 	CreateRepoPage
+	CommitsPage
+	DiffPage
+	TreePage
+	RefsPage
+	IssuesPage
+	IssueComposerPage
+	PullsPage
+	MyReposPage
+	RepoSettingsPage
 )
 
 type RepoLoaded struct {
@@ -43,16 +52,53 @@ type NavMsg struct {
 	from     int
 	userdata githubapi.UserSummary
 	repodata githubapi.Repository
+	ref      string
+
+	// base and head select a commit (head alone) or a comparison for the
+	// diff page; path is the file to show first.
+	base string
+	head string
+	path string
+	// pull is a pull request number, shown by the diff page with its
+	// review comments.
+	pull int
+
+	// back returns to the most recent page of kind to, as it was left,
+	// instead of building a fresh one.
+	back bool
+}
+
+// textEntry is implemented by pages with text inputs, so that typing a "q"
+// into one does not quit the program.
+type textEntry interface {
+	editing() bool
+}
+
+// lastRequestID numbers requests across all pages. Messages whose other
+// keys can repeat, such as two loads of one repository's commits with
+// different filters, carry a number from nextRequestID.
+var lastRequestID int
+
+func nextRequestID() int {
+	lastRequestID++
+	return lastRequestID
+}
+
+type pageEntry struct {
+	id    int
+	model tea.Model
 }
 
 type Manager struct {
-	page      tea.Model
-	Width     int
-	Height    int
-	githubPAT string
+	page    tea.Model
+	current int
+	history []pageEntry
+	Width   int
+	Height  int
+	client  *githubapi.Client
 }
 
-func NewManager(c config.ConfigObj) Manager {
+func NewManager(c config.ConfigObj, client *githubapi.Client) Manager {
 	subtle = lipgloss.Color(c.Subtle)
 	highlight = lipgloss.Color(c.Highlight)
 	text = lipgloss.Color(c.Text)
@@ -73,13 +119,15 @@ func NewManager(c config.ConfigObj) Manager {
 
 	if c.Showhome {
 		return Manager{
-			page:      NewHomePageModel(),
-			githubPAT: c.PAT,
+			page:    NewHomePageModel(),
+			current: HomePage,
+			client:  client,
 		}
 	} else {
 		return Manager{
-			page:      NewSearchPageModel(),
-			githubPAT: c.PAT,
+			page:    NewSearchPageModel(client),
+			current: SearchPage,
+			client:  client,
 		}
 	}
 }
@@ -96,47 +144,131 @@ func (m Manager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
+		m.page, cmd = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
+		return m, cmd
 
 	case tea.KeyMsg:
-		if msg.String() == "q" || msg.String() == "ctrl+c" {
+		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
 		}
+		if msg.String() == "q" {
+			if p, ok := m.page.(textEntry); !ok || !p.editing() {
+				return m, tea.Quit
+			}
+		}
 
 	case NavMsg:
+		if msg.back {
+			for i := len(m.history) - 1; i >= 0; i-- {
+				if m.history[i].id == msg.to {
+					m.page = m.history[i].model
+					m.current = msg.to
+					m.history = m.history[:i]
+					m.page, cmd = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
+					return m, cmd
+				}
+			}
+		} else {
+			m.history = append(m.history, pageEntry{id: m.current, model: m.page})
+		}
+		m.current = msg.to
+
 		switch msg.to {
 		case HomePage:
 			m.page = NewHomePageModel()
 
 		case SearchPage:
-			m.page = NewSearchPageModel()
-			ResizeMsg := tea.WindowSizeMsg{Width: m.Width, Height: m.Height}
+			m.page = NewSearchPageModel(m.client)
+			ResizeMsg := tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()}
 			m.page, _ = m.page.Update(ResizeMsg)
 			return m, m.page.Init()
 		case RepoPage:
-			ResizeMsg := tea.WindowSizeMsg{Width: m.Width, Height: m.Height}
-			newPage := NewRepoPageModel(msg.repodata, msg.userdata, msg.from, m.Width, m.Height)
+			ResizeMsg := tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()}
+			newPage := NewRepoPageModel(m.client, msg.repodata, msg.userdata, msg.from, m.Width, m.pageHeight())
 			m.page, _ = newPage.Update(ResizeMsg)
 			return m, m.page.Init()
 		case UserPage:
-			m.page = NewUserPageModel(msg.userdata, msg.from)
+			m.page = NewUserPageModel(m.client, msg.userdata, msg.from)
+			m.page, _ = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
 			return m, m.page.Init()
 		case CreateRepoPage:
-			m.page = NewCreateRepoPage(m.githubPAT, m.Width, m.Height)
+			m.page = NewCreateRepoPage(m.client, m.Width, m.pageHeight())
+			return m, m.page.Init()
+		case CommitsPage:
+			m.page = NewCommitsPageModel(m.client, msg.repodata, msg.ref, msg.from)
+			m.page, _ = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
+			return m, m.page.Init()
+		case DiffPage:
+			m.page = NewDiffPageModel(m.client, msg.repodata, msg.base, msg.head, msg.path, msg.pull, msg.from)
+			m.page, _ = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
+			return m, m.page.Init()
+		case TreePage:
+			m.page = NewTreePageModel(m.client, msg.repodata, msg.ref, msg.from)
+			m.page, _ = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
+			return m, m.page.Init()
+		case RefsPage:
+			m.page = NewRefsPageModel(m.client, msg.repodata, msg.ref, msg.from)
+			m.page, _ = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
+			return m, m.page.Init()
+		case IssuesPage:
+			m.page = NewIssuesPageModel(m.client, msg.repodata, msg.from)
+			m.page, _ = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
+			return m, m.page.Init()
+		case IssueComposerPage:
+			m.page = NewIssueComposerPage(m.client, msg.repodata)
+			m.page, _ = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
+			return m, m.page.Init()
+		case PullsPage:
+			m.page = NewPullsPageModel(m.client, msg.repodata, msg.from)
+			m.page, _ = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
+			return m, m.page.Init()
+		case MyReposPage:
+			m.page = NewMyReposPageModel(m.client, msg.from)
+			m.page, _ = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
+			return m, m.page.Init()
+		case RepoSettingsPage:
+			m.page = NewRepoSettingsPage(m.client, msg.repodata, msg.from)
+			m.page, _ = m.page.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.pageHeight()})
 			return m, m.page.Init()
 		}
 		return m, nil
 	}
 
 	m.page, cmd = m.page.Update(msg)
-	return m, cmd
+	if _, ok := msg.(tea.KeyMsg); ok {
+		return m, cmd
+	}
+	return m, tea.Batch(cmd, m.updateBackground(msg))
+}
+
+// updateBackground delivers async results to the pages kept for back
+// navigation, so a fetch started before leaving a page still lands there.
+// Every page in the history therefore also sees the results meant for the
+// others, including earlier pages of its own kind. A message returned by a
+// command must carry what it was fetched for: the repository plus a key
+// such as a ref, path, number or request ID, which its handler checks
+// before applying it.
+func (m *Manager) updateBackground(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range m.history {
+		var cmd tea.Cmd
+		m.history[i].model, cmd = m.history[i].model.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m Manager) pageHeight() int {
+	return m.Height - StatusBarHeight
 }
 
 func (m Manager) View() string {
-	return lipgloss.Place(
+	page := lipgloss.Place(
 		m.Width,
-		m.Height,
+		m.pageHeight(),
 		lipgloss.Center,
 		lipgloss.Center,
 		m.page.View(),
 	)
+	return lipgloss.JoinVertical(lipgloss.Left, page, renderStatusBar(m.client, m.Width))
 }
@@ -0,0 +1,525 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+const (
+	myReposSortPushed  = "pushed"
+	myReposSortUpdated = "updated"
+	myReposSortName    = "name"
+	myReposSortStars   = "stars"
+)
+
+const (
+	myReposList int = iota
+	myReposFilter
+)
+
+// myReposChrome is the height of everything around the cards: title, scope,
+// filter line, status and hint with the blank lines between them.
+const myReposChrome = 7
+
+type myReposMsg struct {
+	Request  int
+	Page     int
+	NextPage int
+	Repos    []githubapi.Repository
+	Err      error
+}
+
+type MyReposPageModel struct {
+	Width  int
+	Height int
+
+	CameFrom int
+	filters  filterPanel
+	opts     githubapi.RepoListOptions
+
+	repos       []githubapi.Repository
+	cursor      int
+	windowStart int
+	listPager
+
+	mode      int
+	filter    textinput.Model
+	filtering bool
+
+	// marked holds the full names of the repositories selected for bulk
+	// deletion.
+	marked  map[string]bool
+	deleter *repoDeleter
+
+	spinner spinner.Model
+	client  *githubapi.Client
+}
+
+func NewMyReposPageModel(client *githubapi.Client, camefrom int) MyReposPageModel {
+	initCardStyles()
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	ti := textinput.New()
+	ti.Prompt = "filter: "
+	ti.Placeholder = "fuzzy match on owner/name"
+	ti.CharLimit = 100
+
+	filters := newMyReposFilterPanel()
+
+	m := MyReposPageModel{
+		CameFrom: camefrom,
+		filters:  filters,
+		opts:     filters.myRepoOptions(),
+		mode:     myReposList,
+		filter:   ti,
+		marked:   make(map[string]bool),
+		spinner:  s,
+		client:   client,
+	}
+	m.restart()
+	return m
+}
+
+// fetchMyReposCmd reads one page; the page keeps asking for the next one
+// until every repository is loaded, since the fuzzy filter and sorting work
+// on the whole list.
+func fetchMyReposCmd(client *githubapi.Client, opts githubapi.RepoListOptions, request, page int) tea.Cmd {
+	opts.Page = page
+	opts.PerPage = 100
+	return func() tea.Msg {
+		repos, resp, err := client.ListMyRepos(opts)
+		msg := myReposMsg{Request: request, Page: page, Repos: repos, Err: err}
+		if resp != nil {
+			msg.NextPage = resp.NextPage
+		}
+		return msg
+	}
+}
+
+func (m MyReposPageModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, fetchMyReposCmd(m.client, m.opts, m.request, 1))
+}
+
+func (m MyReposPageModel) editing() bool {
+	return m.filtering || (m.mode == myReposFilter && m.filters.editing()) || m.deleter != nil
+}
+
+func (m MyReposPageModel) listHeight() int {
+	return max(m.Height-myReposChrome, repoCardHeight)
+}
+
+func (m MyReposPageModel) cardsPerPage() int {
+	return max(m.listHeight()/repoCardHeight, 1)
+}
+
+// fuzzyScore reports whether the characters of query appear in s in order.
+// Runs of consecutive characters and matches at the start of a word score
+// higher.
+func fuzzyScore(query, s string) (int, bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	r := []rune(strings.ToLower(s))
+	score, qi, prev := 0, 0, -2
+	for i := 0; i < len(r) && qi < len(q); i++ {
+		if r[i] != q[qi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 2
+		}
+		if i == 0 || strings.ContainsRune("/-_. ", r[i-1]) {
+			score += 3
+		}
+		prev = i
+		qi++
+	}
+	return score, qi == len(q)
+}
+
+// visible returns the repositories that match the filter, best matches
+// first and otherwise in the chosen order.
+func (m MyReposPageModel) visible() []githubapi.Repository {
+	repos := make([]githubapi.Repository, len(m.repos))
+	copy(repos, m.repos)
+
+	desc := m.filters.value(filterOrder) == githubapi.OrderDesc
+	less := func(a, b githubapi.Repository) bool {
+		switch m.filters.value(filterSort) {
+		case myReposSortUpdated:
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		case myReposSortName:
+			return strings.ToLower(a.FullName) < strings.ToLower(b.FullName)
+		case myReposSortStars:
+			return a.StargazersCount < b.StargazersCount
+		}
+		return a.PushedAt.Before(b.PushedAt)
+	}
+	sort.SliceStable(repos, func(i, j int) bool {
+		if desc {
+			return less(repos[j], repos[i])
+		}
+		return less(repos[i], repos[j])
+	})
+
+	query := strings.TrimSpace(m.filter.Value())
+	if query == "" {
+		return repos
+	}
+	scores := make(map[string]int)
+	var matched []githubapi.Repository
+	for _, r := range repos {
+		if score, ok := fuzzyScore(query, r.FullName); ok {
+			scores[r.FullName] = score
+			matched = append(matched, r)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return scores[matched[i].FullName] > scores[matched[j].FullName]
+	})
+	return matched
+}
+
+func (m *MyReposPageModel) resetCursor() {
+	m.cursor = 0
+	m.windowStart = 0
+}
+
+func (m *MyReposPageModel) moveCursor(step int) {
+	total := len(m.visible())
+	if total == 0 {
+		return
+	}
+	m.cursor = max(0, min(m.cursor+step, total-1))
+	if m.cursor < m.windowStart {
+		m.windowStart = m.cursor
+	}
+	if m.cursor >= m.windowStart+m.cardsPerPage() {
+		m.windowStart = m.cursor - m.cardsPerPage() + 1
+	}
+}
+
+func (m *MyReposPageModel) reload() tea.Cmd {
+	m.repos = nil
+	m.resetCursor()
+	request := m.restart()
+	return tea.Batch(m.spinner.Tick, fetchMyReposCmd(m.client, m.opts, request, 1))
+}
+
+// selection returns the marked repositories, or the one under the cursor
+// when none are marked.
+func (m MyReposPageModel) selection() []githubapi.Repository {
+	var repos []githubapi.Repository
+	for _, r := range m.repos {
+		if m.marked[r.FullName] {
+			repos = append(repos, r)
+		}
+	}
+	if len(repos) == 0 {
+		if visible := m.visible(); len(visible) > 0 {
+			repos = append(repos, visible[m.cursor])
+		}
+	}
+	return repos
+}
+
+func (m MyReposPageModel) updateDeleter(msg tea.Msg) (tea.Model, tea.Cmd) {
+	d, cmd, closed := m.deleter.update(msg)
+	m.deleter = &d
+	if closed {
+		m.deleter = nil
+	}
+	return m, cmd
+}
+
+func (m MyReposPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.deleter != nil {
+		switch msg.(type) {
+		case tea.KeyMsg, tokenScopesMsg:
+			return m.updateDeleter(msg)
+		case spinner.TickMsg:
+			d, cmd, _ := m.deleter.update(msg)
+			m.deleter = &d
+			if cmd != nil {
+				return m, cmd
+			}
+		}
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.moveCursor(0)
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading && !m.loadingMore {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case myReposMsg:
+		if !m.landed(msg.Request, msg.Page, msg.NextPage, msg.Err) || msg.Err != nil {
+			return m, nil
+		}
+		if msg.Page == 1 {
+			m.repos = msg.Repos
+		} else {
+			m.repos = append(m.repos, msg.Repos...)
+		}
+		if m.nextPage == 0 {
+			return m, nil
+		}
+		m.loadingMore = true
+		return m, fetchMyReposCmd(m.client, m.opts, m.request, m.nextPage)
+
+	case repoDeletedMsg:
+		// Deletions from the repository page land here too, while this
+		// page waits in the history.
+		if msg.Err == nil {
+			m.repos = slices.DeleteFunc(m.repos, func(r githubapi.Repository) bool {
+				return r.FullName == msg.Repo
+			})
+			delete(m.marked, msg.Repo)
+			m.moveCursor(0)
+		}
+		if m.deleter != nil {
+			return m.updateDeleter(msg)
+		}
+		return m, nil
+
+	case repoUpdatedMsg:
+		if msg.Updated.FullName != "" {
+			for i, r := range m.repos {
+				if r.FullName == msg.Repo {
+					m.repos[i] = msg.Updated
+				}
+			}
+			if m.marked[msg.Repo] {
+				delete(m.marked, msg.Repo)
+				m.marked[msg.Updated.FullName] = true
+			}
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case m.mode == myReposFilter:
+			return m.updateFilterPanel(msg)
+		case m.filtering:
+			return m.updateFilter(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m MyReposPageModel) updateFilterPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var (
+		cmd    tea.Cmd
+		closed bool
+	)
+	m.filters, cmd, closed = m.filters.Update(msg)
+	if !closed {
+		return m, cmd
+	}
+	m.mode = myReposList
+	m.resetCursor()
+	// Sorting is local; only a different affiliation or visibility needs
+	// the list fetched again.
+	if opts := m.filters.myRepoOptions(); opts != m.opts {
+		m.opts = opts
+		return m, m.reload()
+	}
+	return m, nil
+}
+
+func (m MyReposPageModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "enter":
+		m.filtering = false
+		m.filter.Blur()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.resetCursor()
+	return m, cmd
+}
+
+func (m MyReposPageModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "backspace":
+		return m, func() tea.Msg {
+			return NavMsg{to: m.CameFrom, from: MyReposPage, back: true}
+		}
+	case "/":
+		m.filtering = true
+		return m, m.filter.Focus()
+	case "esc":
+		if m.filter.Value() != "" {
+			m.filter.SetValue("")
+			m.resetCursor()
+		}
+	case "f":
+		m.mode = myReposFilter
+	case " ":
+		if repos := m.visible(); len(repos) > 0 {
+			name := repos[m.cursor].FullName
+			if m.marked[name] {
+				delete(m.marked, name)
+			} else {
+				m.marked[name] = true
+			}
+			m.moveCursor(1)
+		}
+	case "D":
+		if repos := m.selection(); len(repos) > 0 {
+			d, cmd := newRepoDeleter(m.client, repos)
+			m.deleter = &d
+			return m, cmd
+		}
+	case "r":
+		if m.err != nil {
+			return m, m.reload()
+		}
+		if m.moreErr != nil {
+			m.moreErr = nil
+			m.loadingMore = true
+			return m, tea.Batch(m.spinner.Tick, fetchMyReposCmd(m.client, m.opts, m.request, m.nextPage))
+		}
+	case "j", "down":
+		m.moveCursor(1)
+	case "k", "up":
+		m.moveCursor(-1)
+	case "d", "ctrl+d":
+		m.moveCursor(m.cardsPerPage())
+	case "u", "ctrl+u":
+		m.moveCursor(-m.cardsPerPage())
+	case "s":
+		if repos := m.visible(); len(repos) > 0 {
+			repo := repos[m.cursor]
+			return m, func() tea.Msg {
+				return NavMsg{to: RepoSettingsPage, from: MyReposPage, repodata: repo}
+			}
+		}
+	case "o":
+		if repos := m.visible(); len(repos) > 0 {
+			return m, openBrowserCmd(repos[m.cursor].HTMLURL)
+		}
+	case "enter":
+		repos := m.visible()
+		if len(repos) == 0 {
+			return m, nil
+		}
+		repo := repos[m.cursor]
+		return m, func() tea.Msg {
+			return NavMsg{to: RepoPage, from: MyReposPage, repodata: repo}
+		}
+	}
+	return m, nil
+}
+
+func (m MyReposPageModel) renderHeader() string {
+	title := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render("Your repositories")
+
+	var scope []string
+	for _, f := range m.filters.fields {
+		scope = append(scope, strings.ToLower(f.label)+": "+f.labels[f.choice])
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, title, lipgloss.NewStyle().Foreground(subtle).Render(strings.Join(scope, " · ")))
+}
+
+func (m MyReposPageModel) renderList() string {
+	switch {
+	case m.loading:
+		return fmt.Sprintf("%s Loading your repositories...", m.spinner.View())
+	case m.err != nil:
+		return renderErrorState(m.err, m.Width-4)
+	}
+
+	repos := m.visible()
+	if len(repos) == 0 {
+		if len(m.repos) == 0 {
+			return lipgloss.NewStyle().Foreground(subtle).Render("No repositories match these filters.")
+		}
+		return lipgloss.NewStyle().Foreground(subtle).Render("Nothing matches " + m.filter.Value() + ".")
+	}
+
+	var cards []string
+	end := min(m.windowStart+m.cardsPerPage(), len(repos))
+	for i := m.windowStart; i < end; i++ {
+		mark := ""
+		if m.marked[repos[i].FullName] {
+			mark = "\n\n" + lipgloss.NewStyle().Foreground(warning).Bold(true).Render("✓")
+		}
+		gutter := lipgloss.NewStyle().Width(2).Render(mark)
+		cards = append(cards, lipgloss.JoinHorizontal(lipgloss.Top, gutter, renderRepoCard(repos[i], i == m.cursor, m.Width-2)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, cards...)
+}
+
+func (m MyReposPageModel) renderStatus() string {
+	status := fmt.Sprintf("%d repositories", len(m.repos))
+	if m.filter.Value() != "" {
+		status = fmt.Sprintf("%d of %d repositories", len(m.visible()), len(m.repos))
+	}
+	if len(m.marked) > 0 {
+		status += fmt.Sprintf(" • %d selected", len(m.marked))
+	}
+	switch {
+	case m.loadingMore:
+		status += fmt.Sprintf(" • %s loading more", m.spinner.View())
+	case m.moreErr != nil:
+		status += " • failed to load more (" + errorTitle(m.moreErr) + "), r to retry"
+	}
+	return lipgloss.NewStyle().Foreground(subtle).Render(status)
+}
+
+func (m MyReposPageModel) View() string {
+	if m.deleter != nil {
+		return m.deleter.View(m.Width, m.Height)
+	}
+	if m.mode == myReposFilter {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			m.renderHeader(),
+			"",
+			m.filters.View(min(m.Width-4, 60)),
+		)
+	}
+
+	filterLine := m.filter.View()
+	if !m.filtering && m.filter.Value() == "" {
+		filterLine = lipgloss.NewStyle().Foreground(subtle).Render("/ to filter")
+	}
+
+	hint := "j/k move • / filter • f affiliation, visibility and sort • enter open • o open on web • s settings • space select • D delete • backspace back"
+	if m.filtering {
+		hint = "type to filter • enter/esc done"
+	}
+
+	body := lipgloss.NewStyle().Height(m.listHeight()).Render(m.renderList())
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.renderHeader(),
+		filterLine,
+		"",
+		body,
+		"",
+		m.renderStatus(),
+		ansi.Truncate(lipgloss.NewStyle().Foreground(subtle).Render(hint), m.Width, "…"),
+	)
+}
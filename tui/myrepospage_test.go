@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query, s string
+		match    bool
+	}{
+		{"", "owner/repo", true},
+		{"rg", "chirag-diwan/RemGit", true},
+		{"remgit", "chirag-diwan/RemGit", true},
+		{"r m g", "chirag-diwan/RemGit", true},
+		{"tigmer", "chirag-diwan/RemGit", false},
+		{"xyz", "chirag-diwan/RemGit", false},
+	}
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.query, tt.s); ok != tt.match {
+			t.Errorf("fuzzyScore(%q, %q) matched = %v, want %v", tt.query, tt.s, ok, tt.match)
+		}
+	}
+}
+
+func TestFuzzyScoreRanking(t *testing.T) {
+	// A run at the start of a word beats the same letters scattered.
+	word, _ := fuzzyScore("rem", "octo/remote")
+	scattered, _ := fuzzyScore("rem", "octo/rollercoaster-game")
+	if word <= scattered {
+		t.Errorf("word start scored %d, scattered %d", word, scattered)
+	}
+}
+
+func TestMyReposDropsEarlierLoads(t *testing.T) {
+	repo := func(name string) githubapi.Repository { return githubapi.Repository{FullName: "octo/" + name} }
+	m := NewMyReposPageModel(nil, HomePage)
+	first := m.request
+
+	// Page 1 of the first load arrives and chains page 2.
+	model, _ := m.Update(myReposMsg{Request: first, Page: 1, NextPage: 2, Repos: []githubapi.Repository{repo("a")}})
+	m = model.(MyReposPageModel)
+
+	// Toggling the filter away and back starts a new load with the same
+	// options while the first chain is still running.
+	m.reload()
+	m.reload()
+	second := m.request
+
+	for _, msg := range []myReposMsg{
+		{Request: second, Page: 1, NextPage: 2, Repos: []githubapi.Repository{repo("a")}},
+		{Request: first, Page: 2, Repos: []githubapi.Repository{repo("b")}},
+		{Request: first, Page: 1, Repos: []githubapi.Repository{repo("a")}},
+		{Request: second, Page: 2, Repos: []githubapi.Repository{repo("b")}},
+	} {
+		model, _ = m.Update(msg)
+		m = model.(MyReposPageModel)
+	}
+
+	var names []string
+	for _, r := range m.repos {
+		names = append(names, r.FullName)
+	}
+	if len(names) != 2 || names[0] != "octo/a" || names[1] != "octo/b" {
+		t.Errorf("repos = %v, want [octo/a octo/b]", names)
+	}
+	if m.loading || m.loadingMore {
+		t.Errorf("still loading: loading %v, loadingMore %v", m.loading, m.loadingMore)
+	}
+}
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type pickerItem struct {
+	Label  string
+	Detail string
+	Value  string
+}
+
+// picker is a filterable list shown over a page to choose one item from,
+// such as a branch or a label. A multi picker lets several items be marked
+// instead.
+type picker struct {
+	title    string
+	items    []pickerItem
+	filtered []int
+	cursor   int
+	input    textinput.Model
+	loading  bool
+	err      error
+
+	multi  bool
+	marked map[string]bool
+}
+
+func newPicker(title string) picker {
+	ti := textinput.New()
+	ti.Placeholder = "type to filter"
+	ti.Prompt = "› "
+	ti.CharLimit = 100
+	ti.Focus()
+
+	return picker{title: title, input: ti, loading: true}
+}
+
+// newMultiPicker returns a picker where tab marks items, starting with the
+// values in marked.
+func newMultiPicker(title string, marked []string) picker {
+	p := newPicker(title)
+	p.multi = true
+	p.marked = make(map[string]bool)
+	for _, v := range marked {
+		p.marked[v] = true
+	}
+	return p
+}
+
+// selection returns the marked values in item order.
+func (p picker) selection() []string {
+	var values []string
+	for _, item := range p.items {
+		if p.marked[item.Value] {
+			values = append(values, item.Value)
+		}
+	}
+	return values
+}
+
+func (p *picker) setItems(items []pickerItem, err error) {
+	p.items = items
+	p.err = err
+	p.loading = false
+	p.filter()
+}
+
+func (p *picker) filter() {
+	query := strings.ToLower(strings.TrimSpace(p.input.Value()))
+	p.filtered = p.filtered[:0]
+	for i, item := range p.items {
+		if query == "" || strings.Contains(strings.ToLower(item.Label), query) {
+			p.filtered = append(p.filtered, i)
+		}
+	}
+	p.cursor = min(p.cursor, max(len(p.filtered)-1, 0))
+}
+
+// update handles a key press. It reports the chosen item, or done with a nil
+// item when the picker was dismissed. A multi picker reports a non-nil item
+// once the marks are confirmed; read them with selection.
+func (p picker) update(msg tea.KeyMsg) (picker, *pickerItem, bool, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return p, nil, true, nil
+	case "tab":
+		if p.multi && len(p.filtered) > 0 {
+			v := p.items[p.filtered[p.cursor]].Value
+			p.marked[v] = !p.marked[v]
+		}
+		return p, nil, false, nil
+	case "enter":
+		if p.multi {
+			return p, &pickerItem{}, true, nil
+		}
+		if len(p.filtered) == 0 {
+			return p, nil, false, nil
+		}
+		item := p.items[p.filtered[p.cursor]]
+		return p, &item, true, nil
+	case "down", "ctrl+n", "ctrl+j":
+		p.cursor = min(p.cursor+1, max(len(p.filtered)-1, 0))
+		return p, nil, false, nil
+	case "up", "ctrl+p", "ctrl+k":
+		p.cursor = max(p.cursor-1, 0)
+		return p, nil, false, nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.filter()
+	return p, nil, false, cmd
+}
+
+func (p picker) View(width, height int) string {
+	width = min(width, 60)
+	rows := []string{
+		lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(p.title),
+		p.input.View(),
+		"",
+	}
+
+	visible := max(height-7, 1)
+	switch {
+	case p.loading:
+		rows = append(rows, lipgloss.NewStyle().Foreground(subtle).Render("Loading..."))
+	case p.err != nil:
+		rows = append(rows, lipgloss.NewStyle().Foreground(warning).Width(width-4).Render(errorTitle(p.err)+": "+p.err.Error()))
+	case len(p.filtered) == 0:
+		rows = append(rows, lipgloss.NewStyle().Foreground(subtle).Render("No matches."))
+	default:
+		start := max(0, min(p.cursor-visible/2, len(p.filtered)-visible))
+		for i := start; i < min(len(p.filtered), start+visible); i++ {
+			item := p.items[p.filtered[i]]
+			style := lipgloss.NewStyle().Foreground(text)
+			pointer := "  "
+			if i == p.cursor {
+				style = style.Foreground(highlight).Bold(true)
+				pointer = "> "
+			}
+			if p.multi {
+				mark := "[ ] "
+				if p.marked[item.Value] {
+					mark = lipgloss.NewStyle().Foreground(special).Render("[x]") + " "
+				}
+				pointer += mark
+			}
+			row := pointer + style.Render(item.Label)
+			if item.Detail != "" {
+				row += "  " + lipgloss.NewStyle().Foreground(subtle).Render(item.Detail)
+			}
+			rows = append(rows, ansi.Truncate(row, width-4, "…"))
+		}
+	}
+	hint := "↑/↓ choose • enter select • esc cancel"
+	if p.multi {
+		hint = "↑/↓ choose • tab mark • enter confirm • esc cancel"
+	}
+	rows = append(rows, "", lipgloss.NewStyle().Foreground(subtle).Render(hint))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(0, 1).
+		Width(width).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
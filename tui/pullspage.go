@@ -0,0 +1,1401 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+// pullRow is a pull request as listed, from either a repository's pull
+// requests or a search across repositories.
+type pullRow struct {
+	Repo      string
+	Number    int
+	Title     string
+	Author    string
+	State     string
+	Draft     bool
+	Merged    bool
+	CreatedAt time.Time
+	URL       string
+}
+
+func (r pullRow) key() githubapi.PullKey {
+	return githubapi.PullKey{Repo: r.Repo, Number: r.Number}
+}
+
+type pullsMsg struct {
+	Repo     string
+	Request  int
+	Page     int
+	NextPage int
+	Rows     []pullRow
+	Err      error
+}
+
+// pullStatusMsg carries review and check summaries for listed rows. It is
+// best effort, so failures are dropped.
+type pullStatusMsg struct {
+	Statuses map[githubapi.PullKey]githubapi.PullStatus
+}
+
+// checkRow is a commit status or check run on the head commit. State is
+// success, failure, pending or neutral.
+type checkRow struct {
+	Name  string
+	State string
+	URL   string
+}
+
+type pullDetailMsg struct {
+	Repo           string
+	Number         int
+	Pull           githubapi.PullRequest
+	Commits        []githubapi.CommitItem
+	Files          []githubapi.CommitFile
+	Reviews        []githubapi.Review
+	ReviewComments []githubapi.ReviewComment
+	Comments       []githubapi.IssueComment
+	Checks         []checkRow
+	ChecksErr      error
+	Err            error
+}
+
+type reviewCreatedMsg struct {
+	Repo   string
+	Number int
+	Review githubapi.Review
+	Err    error
+}
+
+// pullMergedMsg reports a merge and, when it was asked for, the deletion
+// of the head branch afterwards.
+type pullMergedMsg struct {
+	Repo      string
+	Number    int
+	Result    githubapi.MergeResult
+	Branch    string
+	DeleteErr error
+	Err       error
+}
+
+const (
+	pullsList int = iota
+	pullsFilter
+	pullsDetail
+)
+
+const (
+	pullOverview int = iota
+	pullCommits
+	pullFiles
+)
+
+const (
+	pickReviewEvent int = iota
+	pickMergeMethod
+	pickDeleteBranch
+	pickMergeUnstable
+)
+
+const (
+	pullsChrome = 6
+	// pullPages bounds how many pages of 100 are read for one pull
+	// request's files, commits, reviews and comments. GitHub stops at
+	// 3000 files and 250 commits.
+	pullPages = 30
+	// mergeableRetry is how long to wait before asking again when GitHub
+	// has not yet worked out whether a pull request can be merged.
+	mergeableRetry = 2 * time.Second
+)
+
+// listAll reads up to pullPages pages of 100 from fetch.
+func listAll[T any](fetch func(githubapi.ListOptions) ([]T, *githubapi.Response, error)) ([]T, error) {
+	var all []T
+	opts := githubapi.ListOptions{Page: 1, PerPage: 100}
+	for range pullPages {
+		page, resp, err := fetch(opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+func allPullFiles(client *githubapi.Client, repo githubapi.Repository, number int) ([]githubapi.CommitFile, error) {
+	return listAll(func(opts githubapi.ListOptions) ([]githubapi.CommitFile, *githubapi.Response, error) {
+		return client.ListPullFiles(repo, number, opts)
+	})
+}
+
+func allReviewComments(client *githubapi.Client, repo githubapi.Repository, number int) ([]githubapi.ReviewComment, error) {
+	return listAll(func(opts githubapi.ListOptions) ([]githubapi.ReviewComment, *githubapi.Response, error) {
+		return client.ListReviewComments(repo, number, opts)
+	})
+}
+
+// repoFromFullName builds enough of a repository to address it in API
+// calls, for rows that only carry owner/name.
+func repoFromFullName(fullName string) githubapi.Repository {
+	owner, name, _ := strings.Cut(fullName, "/")
+	return githubapi.Repository{Name: name, FullName: fullName, Owner: githubapi.Owner{Login: owner}}
+}
+
+type PullsPageModel struct {
+	Width  int
+	Height int
+
+	// repo is empty when listing the pull requests awaiting the user's
+	// review across all repositories.
+	repo     githubapi.Repository
+	CameFrom int
+	filters  filterPanel
+	opts     githubapi.PullListOptions
+
+	rows        []pullRow
+	statuses    map[githubapi.PullKey]githubapi.PullStatus
+	cursor      int
+	windowStart int
+	listPager
+
+	mode          int
+	tab           int
+	detail        *pullDetailMsg
+	detailRow     pullRow
+	loadingDetail bool
+	itemCursor    int
+	overview      viewport.Model
+
+	picker      *picker
+	pickerKind  int
+	reviewEvent string
+	mergeMethod string
+	composing   bool
+	reviewBody  textarea.Model
+	posting     bool
+	action      string
+
+	spinner spinner.Model
+	client  *githubapi.Client
+}
+
+func NewPullsPageModel(client *githubapi.Client, repo githubapi.Repository, camefrom int) PullsPageModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	filters := newPullFilterPanel()
+
+	body := textarea.New()
+	body.Placeholder = "Leave a review (markdown)"
+	body.ShowLineNumbers = false
+	body.CharLimit = 0
+	body.SetWidth(readmeWrap)
+	body.SetHeight(10)
+	body.FocusedStyle.CursorLine = lipgloss.NewStyle()
+
+	m := PullsPageModel{
+		reviewBody: body,
+		repo:       repo,
+		CameFrom:   camefrom,
+		filters:    filters,
+		opts:       filters.pullOptions(),
+		statuses:   make(map[githubapi.PullKey]githubapi.PullStatus),
+		mode:       pullsList,
+		overview:   viewport.New(0, 0),
+		spinner:    s,
+		client:     client,
+	}
+	m.restart()
+	return m
+}
+
+func (m PullsPageModel) reviewRequests() bool {
+	return m.repo.FullName == ""
+}
+
+func fetchPullsCmd(client *githubapi.Client, repo githubapi.Repository, opts githubapi.PullListOptions, request, page int) tea.Cmd {
+	opts.Page = page
+	opts.PerPage = githubapi.DefaultPerPage
+	return func() tea.Msg {
+		msg := pullsMsg{Repo: repo.FullName, Request: request, Page: page}
+		var resp *githubapi.Response
+
+		if repo.FullName == "" {
+			var result githubapi.IssueSearchResponse
+			result, resp, msg.Err = client.SearchIssues(githubapi.ReviewRequestsQuery, opts.ListOptions)
+			for _, issue := range result.Items {
+				msg.Rows = append(msg.Rows, pullRow{
+					Repo:      issue.RepoFullName(),
+					Number:    issue.Number,
+					Title:     issue.Title,
+					Author:    issue.User.Login,
+					State:     issue.State,
+					Draft:     issue.Draft,
+					CreatedAt: issue.CreatedAt,
+					URL:       issue.HTMLURL,
+				})
+			}
+		} else {
+			var pulls []githubapi.PullRequest
+			pulls, resp, msg.Err = client.ListPulls(repo, opts)
+			for _, pr := range pulls {
+				msg.Rows = append(msg.Rows, pullRow{
+					Repo:      repo.FullName,
+					Number:    pr.Number,
+					Title:     pr.Title,
+					Author:    pr.User.Login,
+					State:     pr.State,
+					Draft:     pr.Draft,
+					Merged:    pr.MergedAt != nil,
+					CreatedAt: pr.CreatedAt,
+					URL:       pr.HTMLURL,
+				})
+			}
+		}
+
+		if resp != nil {
+			msg.NextPage = resp.NextPage
+		}
+		return msg
+	}
+}
+
+// fetchPullStatusesCmd fills in the review and check columns, which need
+// GraphQL and so a token.
+func fetchPullStatusesCmd(client *githubapi.Client, rows []pullRow) tea.Cmd {
+	if client.Token == "" || len(rows) == 0 {
+		return nil
+	}
+	var keys []githubapi.PullKey
+	for _, r := range rows {
+		keys = append(keys, r.key())
+	}
+	return func() tea.Msg {
+		statuses, err := client.GetPullStatuses(keys)
+		if err != nil {
+			return nil
+		}
+		return pullStatusMsg{Statuses: statuses}
+	}
+}
+
+func fetchPullDetailCmd(client *githubapi.Client, repo githubapi.Repository, number int) tea.Cmd {
+	return func() tea.Msg {
+		fail := func(err error) tea.Msg {
+			return pullDetailMsg{Repo: repo.FullName, Number: number, Err: err}
+		}
+
+		pr, err := client.GetPull(repo, number)
+		if err != nil {
+			return fail(err)
+		}
+		if pr.Mergeable == nil && pr.State == githubapi.IssueStateOpen {
+			// The first request starts a background mergeability check.
+			time.Sleep(mergeableRetry)
+			if again, err := client.GetPull(repo, number); err == nil {
+				pr = again
+			}
+		}
+
+		msg := pullDetailMsg{Repo: repo.FullName, Number: number, Pull: pr}
+		if msg.Commits, err = listAll(func(opts githubapi.ListOptions) ([]githubapi.CommitItem, *githubapi.Response, error) {
+			return client.ListPullCommits(repo, number, opts)
+		}); err != nil {
+			return fail(err)
+		}
+		if msg.Files, err = allPullFiles(client, repo, number); err != nil {
+			return fail(err)
+		}
+		if msg.Reviews, err = listAll(func(opts githubapi.ListOptions) ([]githubapi.Review, *githubapi.Response, error) {
+			return client.ListReviews(repo, number, opts)
+		}); err != nil {
+			return fail(err)
+		}
+		if msg.ReviewComments, err = allReviewComments(client, repo, number); err != nil {
+			return fail(err)
+		}
+		if msg.Comments, err = listAll(func(opts githubapi.ListOptions) ([]githubapi.IssueComment, *githubapi.Response, error) {
+			return client.ListIssueComments(repo, number, opts)
+		}); err != nil {
+			return fail(err)
+		}
+		msg.Checks, msg.ChecksErr = headChecks(client, repo, pr.Head.SHA)
+		return msg
+	}
+}
+
+func createReviewCmd(client *githubapi.Client, repo githubapi.Repository, pr githubapi.PullRequest, event, body string) tea.Cmd {
+	return func() tea.Msg {
+		review, err := client.CreateReview(repo, pr.Number, githubapi.ReviewRequest{CommitID: pr.Head.SHA, Body: body, Event: event})
+		return reviewCreatedMsg{Repo: repo.FullName, Number: pr.Number, Review: review, Err: err}
+	}
+}
+
+// mergePullCmd merges pr as long as its head has not moved since it was
+// loaded, then deletes the head branch if asked to.
+func mergePullCmd(client *githubapi.Client, repo githubapi.Repository, pr githubapi.PullRequest, method string, deleteBranch bool) tea.Cmd {
+	return func() tea.Msg {
+		result, err := client.MergePull(repo, pr.Number, githubapi.MergeRequest{SHA: pr.Head.SHA, MergeMethod: method})
+		msg := pullMergedMsg{Repo: repo.FullName, Number: pr.Number, Result: result, Err: err}
+		if err == nil && deleteBranch {
+			msg.Branch = pr.Head.Ref
+			msg.DeleteErr = client.DeleteBranch(repo, pr.Head.Ref)
+		}
+		return msg
+	}
+}
+
+// headChecks merges the commit statuses and check runs on sha into one
+// list.
+func headChecks(client *githubapi.Client, repo githubapi.Repository, sha string) ([]checkRow, error) {
+	status, err := client.GetCombinedStatus(repo, sha)
+	if err != nil {
+		return nil, err
+	}
+	var checks []checkRow
+	for _, s := range status.Statuses {
+		state := s.State
+		if state == "error" {
+			state = "failure"
+		}
+		checks = append(checks, checkRow{Name: s.Context, State: state, URL: s.TargetURL})
+	}
+
+	runs, err := listAll(func(opts githubapi.ListOptions) ([]githubapi.CheckRun, *githubapi.Response, error) {
+		return client.ListCheckRuns(repo, sha, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range runs {
+		state := "pending"
+		if r.Status == "completed" {
+			switch r.Conclusion {
+			case "success":
+				state = "success"
+			case "neutral", "skipped":
+				state = "neutral"
+			default:
+				state = "failure"
+			}
+		}
+		checks = append(checks, checkRow{Name: r.Name, State: state, URL: r.HTMLURL})
+	}
+	return checks, nil
+}
+
+func (m PullsPageModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, fetchPullsCmd(m.client, m.repo, m.opts, m.request, 1))
+}
+
+func (m PullsPageModel) listHeight() int {
+	return max(m.Height-pullsChrome, 3)
+}
+
+func (m *PullsPageModel) reload() tea.Cmd {
+	m.rows = nil
+	m.cursor = 0
+	m.windowStart = 0
+	request := m.restart()
+	return tea.Batch(m.spinner.Tick, fetchPullsCmd(m.client, m.repo, m.opts, request, 1))
+}
+
+func (m *PullsPageModel) maybeLoadMore() tea.Cmd {
+	if !m.wantMore(m.cursor, len(m.rows)) {
+		return nil
+	}
+	return tea.Batch(m.spinner.Tick, fetchPullsCmd(m.client, m.repo, m.opts, m.request, m.nextPage))
+}
+
+func (m *PullsPageModel) moveCursor(step int) {
+	if len(m.rows) == 0 {
+		return
+	}
+	m.cursor = max(0, min(m.cursor+step, len(m.rows)-1))
+	if m.cursor < m.windowStart {
+		m.windowStart = m.cursor
+	}
+	if m.cursor >= m.windowStart+m.listHeight() {
+		m.windowStart = m.cursor - m.listHeight() + 1
+	}
+}
+
+func (m *PullsPageModel) openDetail(row pullRow) tea.Cmd {
+	m.mode = pullsDetail
+	m.tab = pullOverview
+	m.detail = nil
+	m.detailRow = row
+	m.itemCursor = 0
+	m.action = ""
+	m.loadingDetail = true
+	m.refreshOverview()
+	m.overview.GotoTop()
+	return tea.Batch(m.spinner.Tick, fetchPullDetailCmd(m.client, repoFromFullName(row.Repo), row.Number))
+}
+
+// reloadDetail fetches the open pull request again after acting on it,
+// keeping the tab and the action message.
+func (m *PullsPageModel) reloadDetail() tea.Cmd {
+	m.loadingDetail = true
+	m.refreshOverview()
+	return tea.Batch(m.spinner.Tick, fetchPullDetailCmd(m.client, repoFromFullName(m.detailRow.Repo), m.detailRow.Number))
+}
+
+func (m *PullsPageModel) refreshOverview() {
+	m.overview.SetContent(m.renderOverview())
+}
+
+func (m PullsPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.overview.Width = m.Width
+		m.overview.Height = max(m.Height-3, 1)
+		if m.mode == pullsDetail {
+			m.refreshOverview()
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading && !m.loadingMore && !m.loadingDetail && !m.posting {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		if m.loadingDetail {
+			m.refreshOverview()
+		}
+		return m, cmd
+
+	case pullsMsg:
+		if msg.Repo != m.repo.FullName || !m.landed(msg.Request, msg.Page, msg.NextPage, msg.Err) || msg.Err != nil {
+			return m, nil
+		}
+		if msg.Page == 1 {
+			m.rows = msg.Rows
+		} else {
+			m.rows = append(m.rows, msg.Rows...)
+		}
+		return m, tea.Batch(fetchPullStatusesCmd(m.client, msg.Rows), m.maybeLoadMore())
+
+	case pullStatusMsg:
+		for k, s := range msg.Statuses {
+			m.statuses[k] = s
+		}
+		return m, nil
+
+	case pullDetailMsg:
+		if m.mode != pullsDetail || msg.Repo != m.detailRow.Repo || msg.Number != m.detailRow.Number {
+			return m, nil
+		}
+		m.loadingDetail = false
+		m.detail = &msg
+		m.refreshOverview()
+		return m, nil
+
+	case reviewCreatedMsg:
+		if msg.Repo != m.detailRow.Repo || msg.Number != m.detailRow.Number || !m.posting {
+			return m, nil
+		}
+		m.posting = false
+		if msg.Err != nil {
+			m.action = actionError(msg.Err)
+			return m, nil
+		}
+		m.composing = false
+		m.reviewBody.Reset()
+		m.reviewBody.Blur()
+		m.action = lipgloss.NewStyle().Foreground(special).Render("Review submitted: " + reviewVerb(msg.Review.State) + ".")
+		return m, m.reloadDetail()
+
+	case pullMergedMsg:
+		if msg.Repo != m.detailRow.Repo || msg.Number != m.detailRow.Number || !m.posting {
+			return m, nil
+		}
+		m.posting = false
+		if msg.Err != nil {
+			m.action = actionError(msg.Err)
+			return m, nil
+		}
+		for i := range m.rows {
+			if m.rows[i].key() == m.detailRow.key() {
+				m.rows[i].State = githubapi.IssueStateClosed
+				m.rows[i].Merged = true
+			}
+		}
+		m.action = lipgloss.NewStyle().Foreground(special).Render(fmt.Sprintf("Merged as %s.", shortRef(msg.Result.SHA)))
+		switch {
+		case msg.DeleteErr != nil:
+			m.action += " " + actionError(fmt.Errorf("could not delete %s: %w", msg.Branch, msg.DeleteErr))
+		case msg.Branch != "":
+			m.action += lipgloss.NewStyle().Foreground(special).Render(" Deleted " + msg.Branch + ".")
+		}
+		return m, m.reloadDetail()
+
+	case browserMsg:
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case pullsFilter:
+			return m.updateFilter(msg)
+		case pullsDetail:
+			return m.updateDetail(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m PullsPageModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var (
+		cmd    tea.Cmd
+		closed bool
+	)
+	m.filters, cmd, closed = m.filters.Update(msg)
+	if !closed {
+		return m, cmd
+	}
+	m.mode = pullsList
+	m.opts = m.filters.pullOptions()
+	return m, m.reload()
+}
+
+func (m PullsPageModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		m.moveCursor(1)
+		return m, m.maybeLoadMore()
+	case "k", "up":
+		m.moveCursor(-1)
+	case "d", "ctrl+d":
+		m.moveCursor(m.listHeight() / 2)
+		return m, m.maybeLoadMore()
+	case "u", "ctrl+u":
+		m.moveCursor(-m.listHeight() / 2)
+	case "f":
+		if !m.reviewRequests() {
+			m.mode = pullsFilter
+		}
+	case "r":
+		if m.err != nil {
+			return m, m.reload()
+		}
+		if m.moreErr != nil {
+			m.moreErr = nil
+			return m, m.maybeLoadMore()
+		}
+	case "o":
+		if len(m.rows) > 0 {
+			return m, openBrowserCmd(m.rows[m.cursor].URL)
+		}
+	case "enter":
+		if len(m.rows) > 0 {
+			return m, m.openDetail(m.rows[m.cursor])
+		}
+	case "backspace":
+		return m, func() tea.Msg {
+			return NavMsg{to: m.CameFrom, from: PullsPage, repodata: m.repo, back: true}
+		}
+	}
+	return m, nil
+}
+
+func (m PullsPageModel) tabLength() int {
+	if m.detail == nil {
+		return 0
+	}
+	switch m.tab {
+	case pullCommits:
+		return len(m.detail.Commits)
+	case pullFiles:
+		return len(m.detail.Files)
+	}
+	return 0
+}
+
+// mergeBlocker explains why d cannot be merged from here, or returns ""
+// when it can. It follows GitHub's mergeable_state, so checks and reviews
+// only block a merge when branch protection requires them; checking first
+// gives a reason instead of a bare 405.
+func mergeBlocker(d *pullDetailMsg, decision string) string {
+	pr := d.Pull
+	switch {
+	case pr.Merged:
+		return "it is already merged"
+	case pr.State == githubapi.IssueStateClosed:
+		return "it is closed"
+	case pr.Draft:
+		return "it is a draft; mark it ready for review first"
+	case pr.Mergeable == nil:
+		return "GitHub is still checking whether it can be merged; r to refresh"
+	}
+
+	switch pr.MergeableState {
+	case "clean", "has_hooks", "unstable":
+		return ""
+	case "dirty":
+		return "it has conflicts with " + pr.Base.Ref + " that must be resolved first"
+	case "behind":
+		return "the head branch is behind " + pr.Base.Ref + " and must be updated first"
+	case "blocked":
+		failing, pending := checkNames(d.Checks)
+		var reasons []string
+		if len(failing) > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d failing checks (%s)", len(failing), strings.Join(failing, ", ")))
+		}
+		if len(pending) > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d checks still running (%s)", len(pending), strings.Join(pending, ", ")))
+		}
+
+		changesRequested := decision == githubapi.ReviewChangesRequested
+		for _, r := range latestReviews(d.Reviews) {
+			if r.State == githubapi.ReviewChangesRequested {
+				changesRequested = true
+			}
+		}
+		switch {
+		case changesRequested:
+			reasons = append(reasons, "changes were requested")
+		case decision == "REVIEW_REQUIRED" || len(reasons) == 0:
+			reasons = append(reasons, "it needs an approving review")
+		}
+		return strings.Join(reasons, "; ")
+	}
+	if !*pr.Mergeable {
+		return "GitHub reports that it cannot be merged"
+	}
+	return ""
+}
+
+// mergeWarning describes the checks that are not passing on a pull request
+// GitHub will merge anyway, or returns "" when there is nothing to confirm.
+func mergeWarning(d *pullDetailMsg) string {
+	if d.Pull.MergeableState != "unstable" {
+		return ""
+	}
+	failing, pending := checkNames(d.Checks)
+	var reasons []string
+	if len(failing) > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d failing (%s)", len(failing), strings.Join(failing, ", ")))
+	}
+	if len(pending) > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d still running (%s)", len(pending), strings.Join(pending, ", ")))
+	}
+	if len(reasons) == 0 {
+		return "some checks are not passing"
+	}
+	return "checks " + strings.Join(reasons, "; ")
+}
+
+func checkNames(checks []checkRow) (failing, pending []string) {
+	for _, c := range checks {
+		switch c.State {
+		case "failure":
+			failing = append(failing, c.Name)
+		case "pending":
+			pending = append(pending, c.Name)
+		}
+	}
+	return failing, pending
+}
+
+// pickMerge opens the merge method picker.
+func (m *PullsPageModel) pickMerge() {
+	p := newPicker(fmt.Sprintf("Merge #%d into %s", m.detailRow.Number, m.detail.Pull.Base.Ref))
+	p.setItems([]pickerItem{
+		{Label: "Create a merge commit", Detail: "all commits are added with a merge commit", Value: githubapi.MergeMethodMerge},
+		{Label: "Squash and merge", Detail: "the commits are combined into one", Value: githubapi.MergeMethodSquash},
+		{Label: "Rebase and merge", Detail: "the commits are replayed onto " + m.detail.Pull.Base.Ref, Value: githubapi.MergeMethodRebase},
+	}, nil)
+	m.picker = &p
+	m.pickerKind = pickMergeMethod
+}
+
+func (m PullsPageModel) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.posting {
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.composing = false
+		m.reviewBody.Blur()
+		return m, nil
+	case "ctrl+s":
+		body := strings.TrimSpace(m.reviewBody.Value())
+		if body == "" && m.reviewEvent != githubapi.ReviewEventApprove {
+			m.action = lipgloss.NewStyle().Foreground(warning).Render("Only an approval can be submitted without a message.")
+			return m, nil
+		}
+		m.posting = true
+		m.action = ""
+		return m, tea.Batch(m.spinner.Tick, createReviewCmd(m.client, repoFromFullName(m.detailRow.Repo), m.detail.Pull, m.reviewEvent, body))
+	}
+	var cmd tea.Cmd
+	m.reviewBody, cmd = m.reviewBody.Update(msg)
+	return m, cmd
+}
+
+func (m PullsPageModel) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p, item, done, cmd := m.picker.update(msg)
+	m.picker = &p
+	if !done {
+		return m, cmd
+	}
+	m.picker = nil
+	if item == nil || m.detail == nil {
+		return m, nil
+	}
+	pr := m.detail.Pull
+	repo := repoFromFullName(m.detailRow.Repo)
+
+	switch m.pickerKind {
+	case pickReviewEvent:
+		m.reviewEvent = item.Value
+		m.composing = true
+		m.action = ""
+		return m, m.reviewBody.Focus()
+	case pickMergeUnstable:
+		m.action = ""
+		if item.Value == "merge" {
+			m.pickMerge()
+		}
+		return m, nil
+	case pickMergeMethod:
+		m.mergeMethod = item.Value
+		// Only branches in the same repository can be deleted from here.
+		if pr.Head.Repo != nil && pr.Base.Repo != nil && pr.Head.Repo.FullName == pr.Base.Repo.FullName {
+			p := newPicker("After merging")
+			p.setItems([]pickerItem{
+				{Label: "Keep " + pr.Head.Ref, Value: "keep"},
+				{Label: "Delete " + pr.Head.Ref, Value: "delete"},
+			}, nil)
+			m.picker = &p
+			m.pickerKind = pickDeleteBranch
+			return m, nil
+		}
+	}
+	m.posting = true
+	m.action = ""
+	return m, tea.Batch(m.spinner.Tick, mergePullCmd(m.client, repo, pr, m.mergeMethod, item.Value == "delete"))
+}
+
+func (m PullsPageModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.composing {
+		return m.updateReview(msg)
+	}
+	if m.picker != nil {
+		return m.updatePicker(msg)
+	}
+	repo := repoFromFullName(m.detailRow.Repo)
+	loaded := m.detail != nil && m.detail.Err == nil && !m.loadingDetail && !m.posting
+
+	switch msg.String() {
+	case "a":
+		if !loaded {
+			return m, nil
+		}
+		p := newPicker(fmt.Sprintf("Review #%d", m.detailRow.Number))
+		p.setItems([]pickerItem{
+			{Label: "Comment", Detail: "general feedback without explicit approval", Value: githubapi.ReviewEventComment},
+			{Label: "Approve", Detail: "approve merging these changes", Value: githubapi.ReviewEventApprove},
+			{Label: "Request changes", Detail: "feedback that must be addressed before merging", Value: githubapi.ReviewEventRequestChanges},
+		}, nil)
+		m.picker = &p
+		m.pickerKind = pickReviewEvent
+		return m, nil
+	case "m":
+		if !loaded {
+			return m, nil
+		}
+		if reason := mergeBlocker(m.detail, m.statuses[m.detailRow.key()].ReviewDecision); reason != "" {
+			m.action = lipgloss.NewStyle().Foreground(warning).Render("Cannot merge: " + reason)
+			return m, nil
+		}
+		if concern := mergeWarning(m.detail); concern != "" {
+			m.action = lipgloss.NewStyle().Foreground(warning).Render("Not all checks pass: " + concern)
+			p := newPicker(fmt.Sprintf("Merge #%d anyway?", m.detailRow.Number))
+			p.setItems([]pickerItem{
+				{Label: "Cancel", Value: "cancel"},
+				{Label: "Merge anyway", Detail: "GitHub allows it, the checks are not required", Value: "merge"},
+			}, nil)
+			m.picker = &p
+			m.pickerKind = pickMergeUnstable
+			return m, nil
+		}
+		m.pickMerge()
+		return m, nil
+	case "backspace", "esc":
+		m.mode = pullsList
+		m.detail = nil
+		return m, nil
+	case "tab":
+		m.tab = (m.tab + 1) % 3
+		m.itemCursor = 0
+		return m, nil
+	case "shift+tab":
+		m.tab = (m.tab + 2) % 3
+		m.itemCursor = 0
+		return m, nil
+	case "o":
+		return m, openBrowserCmd(m.detailRow.URL)
+	case "r":
+		if m.posting {
+			return m, nil
+		}
+		return m, m.reloadDetail()
+	case "v":
+		return m, func() tea.Msg {
+			return NavMsg{to: DiffPage, from: PullsPage, repodata: repo, pull: m.detailRow.Number}
+		}
+	}
+
+	if m.tab == pullOverview {
+		switch msg.String() {
+		case "j", "down":
+			m.overview.ScrollDown(1)
+		case "k", "up":
+			m.overview.ScrollUp(1)
+		case "d", "ctrl+d":
+			m.overview.HalfPageDown()
+		case "u", "ctrl+u":
+			m.overview.HalfPageUp()
+		case "g":
+			m.overview.GotoTop()
+		case "G":
+			m.overview.GotoBottom()
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "j", "down":
+		m.itemCursor = min(m.itemCursor+1, max(m.tabLength()-1, 0))
+	case "k", "up":
+		m.itemCursor = max(m.itemCursor-1, 0)
+	case "enter":
+		if m.tabLength() == 0 {
+			return m, nil
+		}
+		if m.tab == pullCommits {
+			sha := m.detail.Commits[m.itemCursor].SHA
+			return m, func() tea.Msg {
+				return NavMsg{to: DiffPage, from: PullsPage, repodata: repo, head: sha}
+			}
+		}
+		file := m.detail.Files[m.itemCursor].Filename
+		return m, func() tea.Msg {
+			return NavMsg{to: DiffPage, from: PullsPage, repodata: repo, pull: m.detailRow.Number, path: file}
+		}
+	}
+	return m, nil
+}
+
+var (
+	colorOpen   = lipgloss.Color("#43BF6D")
+	colorMerged = lipgloss.Color("#A371F7")
+	colorClosed = lipgloss.Color("#E05252")
+	colorDraft  = lipgloss.Color("#6E7681")
+)
+
+func pullStateIcon(r pullRow) string {
+	switch {
+	case r.Merged:
+		return lipgloss.NewStyle().Foreground(colorMerged).Render("⮌")
+	case r.State == githubapi.IssueStateClosed:
+		return lipgloss.NewStyle().Foreground(colorClosed).Render("✗")
+	case r.Draft:
+		return lipgloss.NewStyle().Foreground(colorDraft).Render("◌")
+	}
+	return lipgloss.NewStyle().Foreground(colorOpen).Render("●")
+}
+
+func pullStateBadge(pr githubapi.PullRequest) string {
+	label, color := "Open", colorOpen
+	switch {
+	case pr.Merged || pr.MergedAt != nil:
+		label, color = "Merged", colorMerged
+	case pr.State == githubapi.IssueStateClosed:
+		label, color = "Closed", colorClosed
+	case pr.Draft:
+		label, color = "Draft", colorDraft
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Background(color).Bold(true).Padding(0, 1).Render(label)
+}
+
+func reviewBadge(decision string) string {
+	switch decision {
+	case githubapi.ReviewApproved:
+		return lipgloss.NewStyle().Foreground(colorOpen).Render("✓ approved")
+	case githubapi.ReviewChangesRequested:
+		return lipgloss.NewStyle().Foreground(colorClosed).Render("± changes requested")
+	case "REVIEW_REQUIRED":
+		return lipgloss.NewStyle().Foreground(warning).Render("◔ review required")
+	}
+	return ""
+}
+
+func checksBadge(state string) string {
+	switch state {
+	case "SUCCESS":
+		return lipgloss.NewStyle().Foreground(colorOpen).Render("✓ checks")
+	case "FAILURE", "ERROR":
+		return lipgloss.NewStyle().Foreground(colorClosed).Render("✗ checks")
+	case "PENDING", "EXPECTED":
+		return lipgloss.NewStyle().Foreground(warning).Render("◔ checks")
+	}
+	return ""
+}
+
+func checkIcon(state string) string {
+	switch state {
+	case "success":
+		return lipgloss.NewStyle().Foreground(colorOpen).Render("✓")
+	case "failure":
+		return lipgloss.NewStyle().Foreground(colorClosed).Render("✗")
+	case "neutral":
+		return lipgloss.NewStyle().Foreground(subtle).Render("-")
+	}
+	return lipgloss.NewStyle().Foreground(warning).Render("◔")
+}
+
+// mergeability describes whether pr can be merged, from GitHub's
+// mergeable_state.
+func mergeability(pr githubapi.PullRequest) string {
+	ok := lipgloss.NewStyle().Foreground(colorOpen)
+	warn := lipgloss.NewStyle().Foreground(warning)
+	bad := lipgloss.NewStyle().Foreground(colorClosed)
+	note := lipgloss.NewStyle().Foreground(subtle)
+
+	switch {
+	case pr.Merged:
+		by := ""
+		if pr.MergedBy != nil {
+			by = " by " + pr.MergedBy.Login
+		}
+		when := ""
+		if pr.MergedAt != nil {
+			when = " on " + formatDate(*pr.MergedAt)
+		}
+		return lipgloss.NewStyle().Foreground(colorMerged).Render("Merged" + by + when)
+	case pr.State == githubapi.IssueStateClosed:
+		return note.Render("Closed without merging")
+	case pr.Draft:
+		return note.Render("Draft: not ready to merge")
+	case pr.Mergeable == nil:
+		return note.Render("GitHub is still checking whether this can be merged (r to refresh)")
+	}
+
+	switch pr.MergeableState {
+	case "clean":
+		return ok.Render("✓ Ready to merge")
+	case "has_hooks":
+		return ok.Render("✓ Ready to merge (pre-receive hooks will run)")
+	case "unstable":
+		return warn.Render("! Mergeable, but some checks are not passing")
+	case "behind":
+		return warn.Render("! The head branch is behind the base branch")
+	case "blocked":
+		return bad.Render("✗ Blocked: required reviews or checks are missing")
+	case "dirty":
+		return bad.Render("✗ Has conflicts that must be resolved")
+	}
+	if *pr.Mergeable {
+		return ok.Render("✓ Mergeable")
+	}
+	return bad.Render("✗ Cannot be merged")
+}
+
+// latestReviews keeps each reviewer's most recent verdict. A plain comment
+// only counts when the reviewer has not approved or requested changes.
+func latestReviews(reviews []githubapi.Review) []githubapi.Review {
+	latest := make(map[string]int)
+	var out []githubapi.Review
+	for _, r := range reviews {
+		if r.State == githubapi.ReviewPending {
+			continue
+		}
+		i, seen := latest[r.User.Login]
+		switch {
+		case !seen:
+			latest[r.User.Login] = len(out)
+			out = append(out, r)
+		case r.State != githubapi.ReviewCommented || out[i].State == githubapi.ReviewCommented:
+			out[i] = r
+		}
+	}
+	return out
+}
+
+func reviewVerb(state string) string {
+	switch state {
+	case githubapi.ReviewApproved:
+		return "approved"
+	case githubapi.ReviewChangesRequested:
+		return "requested changes"
+	case githubapi.ReviewDismissed:
+		return "review dismissed"
+	}
+	return "commented"
+}
+
+func (m PullsPageModel) renderHeader() string {
+	if m.reviewRequests() {
+		title := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render("Review requests")
+		return lipgloss.JoinVertical(lipgloss.Left, title, lipgloss.NewStyle().Foreground(subtle).Render("open pull requests waiting on your review"))
+	}
+
+	title := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(m.repo.FullName + " · pull requests")
+	scope := []string{m.opts.State}
+	if scope[0] == "" {
+		scope[0] = githubapi.IssueStateOpen
+	}
+	if m.opts.Base != "" {
+		scope = append(scope, "into "+m.opts.Base)
+	}
+	if m.opts.Head != "" {
+		scope = append(scope, "from "+m.opts.Head)
+	}
+	scope = append(scope, "sorted by "+m.opts.Sort)
+	return lipgloss.JoinVertical(lipgloss.Left, title, lipgloss.NewStyle().Foreground(subtle).Render(strings.Join(scope, " · ")))
+}
+
+func (m PullsPageModel) renderRow(i int) string {
+	r := m.rows[i]
+	metaStyle := lipgloss.NewStyle().Foreground(subtle)
+	titleStyle := lipgloss.NewStyle().Foreground(text)
+	pointer := "  "
+	if i == m.cursor {
+		pointer = "> "
+		titleStyle = titleStyle.Foreground(highlight).Bold(true)
+	}
+
+	number := fmt.Sprintf("#%d", r.Number)
+	if m.reviewRequests() {
+		number = r.Repo + number
+	}
+
+	var badges []string
+	if r.Draft {
+		badges = append(badges, lipgloss.NewStyle().Foreground(colorDraft).Render("draft"))
+	}
+	status := m.statuses[r.key()]
+	if b := reviewBadge(status.ReviewDecision); b != "" {
+		badges = append(badges, b)
+	}
+	if b := checksBadge(status.Checks); b != "" {
+		badges = append(badges, b)
+	}
+	extra := strings.Join(badges, "  ")
+	if extra != "" {
+		extra = "  " + extra
+	}
+
+	meta := metaStyle.Render(fmt.Sprintf("%s · %s", r.Author, formatDate(r.CreatedAt)))
+	room := m.Width - lipgloss.Width(meta) - lipgloss.Width(extra) - lipgloss.Width(number) - 10
+	title := ansi.Truncate(r.Title, max(room, 10), "…")
+	row := fmt.Sprintf("%s%s %s %s%s  %s", pointer, pullStateIcon(r), lipgloss.NewStyle().Foreground(special).Render(number), titleStyle.Render(title), extra, meta)
+	return ansi.Truncate(row, m.Width, "…")
+}
+
+func (m PullsPageModel) renderList() string {
+	switch {
+	case m.loading:
+		return fmt.Sprintf("%s Loading pull requests...", m.spinner.View())
+	case m.err != nil:
+		return renderErrorState(m.err, m.Width-4)
+	case len(m.rows) == 0 && m.reviewRequests():
+		return lipgloss.NewStyle().Foreground(subtle).Render("Nothing is waiting on your review.")
+	case len(m.rows) == 0:
+		return lipgloss.NewStyle().Foreground(subtle).Render("No pull requests match these filters.")
+	}
+
+	var rows []string
+	end := min(m.windowStart+m.listHeight(), len(m.rows))
+	for i := m.windowStart; i < end; i++ {
+		rows = append(rows, m.renderRow(i))
+	}
+
+	status := fmt.Sprintf("%d pull requests loaded", len(m.rows))
+	switch {
+	case m.loadingMore:
+		status += fmt.Sprintf(" • %s loading more", m.spinner.View())
+	case m.moreErr != nil:
+		status += " • failed to load more (" + errorTitle(m.moreErr) + "), r to retry"
+	case m.nextPage == 0:
+		status += " • end of list"
+	}
+	if m.client.Token == "" {
+		status += " • review and check status need a PAT"
+	}
+	rows = append(rows, "", lipgloss.NewStyle().Foreground(subtle).Render(status))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m PullsPageModel) renderOverview() string {
+	switch {
+	case m.loadingDetail:
+		return fmt.Sprintf("%s Loading pull request...", m.spinner.View())
+	case m.detail == nil:
+		return ""
+	case m.detail.Err != nil:
+		return renderErrorState(m.detail.Err, m.Width-4)
+	}
+
+	d := m.detail
+	pr := d.Pull
+	label := lipgloss.NewStyle().Foreground(subtle).Bold(true)
+	value := lipgloss.NewStyle().Foreground(text)
+	branch := lipgloss.NewStyle().Foreground(special)
+
+	lines := []string{
+		pullStateBadge(pr) + value.Render(fmt.Sprintf("  %s wants to merge %d commits into ", pr.User.Login, pr.Commits)) +
+			branch.Render(pr.Base.Label) + value.Render(" from ") + branch.Render(pr.Head.Label),
+		lipgloss.NewStyle().Foreground(subtle).Render(fmt.Sprintf("opened %s · %d files changed · ", formatDate(pr.CreatedAt), pr.ChangedFiles)) +
+			lipgloss.NewStyle().Foreground(colorOpen).Render(fmt.Sprintf("+%d", pr.Additions)) + " " +
+			lipgloss.NewStyle().Foreground(colorClosed).Render(fmt.Sprintf("-%d", pr.Deletions)),
+		"",
+	}
+	if len(pr.Labels) > 0 {
+		lines = append(lines, label.Render("Labels:  ")+labelChips(pr.Labels))
+	}
+	lines = append(lines, label.Render("Merge:   ")+mergeability(pr), "")
+
+	lines = append(lines, label.Render("Reviews"))
+	reviews := latestReviews(d.Reviews)
+	for _, r := range reviews {
+		icon := lipgloss.NewStyle().Foreground(subtle).Render("💬")
+		switch r.State {
+		case githubapi.ReviewApproved:
+			icon = lipgloss.NewStyle().Foreground(colorOpen).Render("✓")
+		case githubapi.ReviewChangesRequested:
+			icon = lipgloss.NewStyle().Foreground(colorClosed).Render("±")
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s %s", icon, value.Render(r.User.Login), lipgloss.NewStyle().Foreground(subtle).Render(reviewVerb(r.State))))
+	}
+	for _, u := range pr.RequestedReviewers {
+		lines = append(lines, fmt.Sprintf("  %s %s %s", lipgloss.NewStyle().Foreground(warning).Render("◔"), value.Render(u.Login), lipgloss.NewStyle().Foreground(subtle).Render("review requested")))
+	}
+	if len(reviews) == 0 && len(pr.RequestedReviewers) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(subtle).Render("  No reviews yet."))
+	}
+
+	lines = append(lines, "", label.Render("Checks"))
+	switch {
+	case d.ChecksErr != nil:
+		lines = append(lines, lipgloss.NewStyle().Foreground(warning).Render("  Checks unavailable: "+errorTitle(d.ChecksErr)))
+	case len(d.Checks) == 0:
+		lines = append(lines, lipgloss.NewStyle().Foreground(subtle).Render("  No checks reported."))
+	default:
+		counts := make(map[string]int)
+		for _, c := range d.Checks {
+			counts[c.State]++
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(subtle).Render(fmt.Sprintf("  %d passed · %d failed · %d pending · %d skipped",
+			counts["success"], counts["failure"], counts["pending"], counts["neutral"])))
+		for _, c := range d.Checks {
+			lines = append(lines, fmt.Sprintf("  %s %s", checkIcon(c.State), value.Render(c.Name)))
+		}
+	}
+
+	lines = append(lines, "", renderPost(pr.User.Login, pr.AuthorAssociation, formatDate(pr.CreatedAt), pr.Body, githubapi.Reactions{}))
+
+	// The conversation interleaves comments with the reviews that carry a
+	// message, oldest first.
+	type post struct {
+		at       time.Time
+		rendered string
+	}
+	var posts []post
+	for _, c := range d.Comments {
+		posts = append(posts, post{c.CreatedAt, renderPost(c.User.Login, c.AuthorAssociation, formatDate(c.CreatedAt), c.Body, c.Reactions)})
+	}
+	for _, r := range d.Reviews {
+		if strings.TrimSpace(r.Body) == "" || r.SubmittedAt == nil {
+			continue
+		}
+		posts = append(posts, post{*r.SubmittedAt, renderPost(r.User.Login+" "+reviewVerb(r.State), r.AuthorAssociation, formatDate(*r.SubmittedAt), r.Body, githubapi.Reactions{})})
+	}
+	sort.SliceStable(posts, func(i, j int) bool { return posts[i].at.Before(posts[j].at) })
+	for _, p := range posts {
+		lines = append(lines, "", p.rendered)
+	}
+	if len(d.ReviewComments) > 0 {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(subtle).Render(fmt.Sprintf("%d review comments on the diff: v to read them inline", len(d.ReviewComments))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderItems renders the commits or files tab as a list around the cursor.
+func (m PullsPageModel) renderItems() string {
+	switch {
+	case m.loadingDetail:
+		return fmt.Sprintf("%s Loading pull request...", m.spinner.View())
+	case m.detail == nil:
+		return ""
+	case m.detail.Err != nil:
+		return renderErrorState(m.detail.Err, m.Width-4)
+	case m.tabLength() == 0:
+		return lipgloss.NewStyle().Foreground(subtle).Render("Nothing here.")
+	}
+
+	commentCounts := make(map[string]int)
+	for _, c := range m.detail.ReviewComments {
+		commentCounts[c.Path]++
+	}
+
+	height := max(m.Height-3, 1)
+	start := max(0, min(m.itemCursor-height/2, m.tabLength()-height))
+	var rows []string
+	for i := start; i < min(m.tabLength(), start+height); i++ {
+		style := lipgloss.NewStyle().Foreground(text)
+		pointer := "  "
+		if i == m.itemCursor {
+			style = style.Foreground(highlight).Bold(true)
+			pointer = "> "
+		}
+
+		var row string
+		if m.tab == pullCommits {
+			c := m.detail.Commits[i]
+			author := c.Commit.Author.Name
+			if c.Author != nil {
+				author = c.Author.Login
+			}
+			row = fmt.Sprintf("%s%s %s  %s", pointer,
+				lipgloss.NewStyle().Foreground(special).Render(c.ShortSHA()),
+				style.Render(c.Summary()),
+				lipgloss.NewStyle().Foreground(subtle).Render(author+" · "+formatDate(c.Commit.Author.Date)))
+		} else {
+			f := m.detail.Files[i]
+			row = fmt.Sprintf("%s%s %s %s %s", pointer, fileStatus(f.Status),
+				lipgloss.NewStyle().Foreground(colorOpen).Width(6).Render(fmt.Sprintf("+%d", f.Additions)),
+				lipgloss.NewStyle().Foreground(colorClosed).Width(6).Render(fmt.Sprintf("-%d", f.Deletions)),
+				style.Render(f.Filename))
+			if n := commentCounts[f.Filename]; n > 0 {
+				row += lipgloss.NewStyle().Foreground(subtle).Render(fmt.Sprintf("  💬 %d", n))
+			}
+		}
+		rows = append(rows, ansi.Truncate(row, m.Width, "…"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m PullsPageModel) renderTabs() string {
+	names := []string{"Overview", "Commits", "Files"}
+	if m.detail != nil && m.detail.Err == nil {
+		names[1] = fmt.Sprintf("Commits (%d)", len(m.detail.Commits))
+		names[2] = fmt.Sprintf("Files (%d)", len(m.detail.Files))
+	}
+	var tabs []string
+	for i, name := range names {
+		style := lipgloss.NewStyle().Foreground(subtle).Padding(0, 1)
+		if i == m.tab {
+			style = style.Foreground(highlight).Bold(true).Underline(true)
+		}
+		tabs = append(tabs, style.Render(name))
+	}
+	return strings.Join(tabs, " ")
+}
+
+func (m PullsPageModel) View() string {
+	hint := lipgloss.NewStyle().Foreground(subtle)
+
+	switch m.mode {
+	case pullsDetail:
+		if m.picker != nil {
+			return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, m.picker.View(m.Width-4, m.Height-4))
+		}
+		if m.composing {
+			return m.renderReview()
+		}
+		title := fmt.Sprintf("%s #%d", m.detailRow.Title, m.detailRow.Number)
+		if m.reviewRequests() {
+			title = m.detailRow.Repo + " · " + title
+		}
+		body := m.overview.View()
+		keys := "tab switch view • j/k d/u scroll • a review • m merge • v full diff • r refresh • o open on web • backspace back to list"
+		if m.tab != pullOverview {
+			body = lipgloss.NewStyle().Height(max(m.Height-3, 1)).Render(m.renderItems())
+			keys = "tab switch view • j/k move • enter open diff • a review • m merge • v full diff • o open on web • backspace back to list"
+		}
+		status := hint.Render(keys)
+		switch {
+		case m.posting:
+			status = fmt.Sprintf("%s Saving...", m.spinner.View())
+		case m.action != "":
+			status = m.action
+		}
+		return lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(ansi.Truncate(title, m.Width, "…")),
+			m.renderTabs(),
+			body,
+			ansi.Truncate(status, m.Width, "…"),
+		)
+	case pullsFilter:
+		return lipgloss.JoinVertical(lipgloss.Left,
+			m.renderHeader(),
+			"",
+			m.filters.View(min(m.Width-4, 60)),
+		)
+	}
+
+	keys := "j/k move • enter open • f filter • o open on web • backspace back"
+	if m.reviewRequests() {
+		keys = "j/k move • enter open • o open on web • backspace back"
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.renderHeader(),
+		"",
+		m.renderList(),
+		"",
+		hint.Render(keys),
+	)
+}
+
+func (m PullsPageModel) renderReview() string {
+	verb := map[string]string{
+		githubapi.ReviewEventComment:        "Comment on",
+		githubapi.ReviewEventApprove:        "Approve",
+		githubapi.ReviewEventRequestChanges: "Request changes on",
+	}[m.reviewEvent]
+	status := lipgloss.NewStyle().Foreground(subtle).Render("ctrl+s submit • esc cancel")
+	switch {
+	case m.posting:
+		status = fmt.Sprintf("%s Submitting review...", m.spinner.View())
+	case m.action != "":
+		status = m.action
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(fmt.Sprintf("%s #%d %s", verb, m.detailRow.Number, ansi.Truncate(m.detailRow.Title, readmeWrap-30, "…"))),
+			"",
+			m.reviewBody.View(),
+			"",
+			status,
+		))
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func (m PullsPageModel) editing() bool {
+	return (m.mode == pullsFilter && m.filters.editing()) || m.composing || m.picker != nil
+}
@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/blacktop/go-termimg"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chirag-diwan/RemGit/githubapi"
+	"github.com/chirag-diwan/RemGit/markdown"
+)
+
+const (
+	readmeWrap        = 76
+	outlineWidth      = 32
+	readmeImageHeight = 20
+	imageWorkers      = 6
+)
+
+type readmeImagesMsg struct {
+	Repo   string
+	Ref    string
+	Path   string
+	Blocks map[string]string
+	Errs   map[string]error
+}
+
+type docMsg struct {
+	Repo    string
+	Ref     string
+	Path    string
+	Anchor  string
+	Content string
+	Err     error
+}
+
+// readmeDoc is a document the viewer navigated away from, kept so
+// backspace can return to it.
+type readmeDoc struct {
+	Path    string
+	Raw     string
+	YOffset int
+}
+
+func readmeRef(repo githubapi.Repository) string {
+	if repo.DefaultBranch == "" {
+		return "HEAD"
+	}
+	return repo.DefaultBranch
+}
+
+// repoPath joins a relative destination onto dir the way GitHub does, with a
+// leading slash meaning the repository root.
+func repoPath(dir, p string) string {
+	if !strings.HasPrefix(p, "/") {
+		p = path.Join(dir, p)
+	}
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+func isMarkdownFile(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".md", ".markdown", ".mdown", ".mkd":
+		return true
+	}
+	return false
+}
+
+func renderMarkdown(md string) (string, error) {
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle("dark"),
+		glamour.WithWordWrap(readmeWrap),
+	)
+	if err != nil {
+		return "", err
+	}
+	return r.Render(md)
+}
+
+// resolveImageURL maps an image destination from a document in dir onto a
+// URL that serves the file itself: relative paths are read from ref and blob
+// links on the forge are rewritten to their raw form.
+func resolveImageURL(client *githubapi.Client, repo githubapi.Repository, ref, dir, dest string) string {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return dest
+	}
+	if u.Host != "" {
+		if u.Scheme == "" {
+			u.Scheme = "https"
+		}
+		if u.Host == client.WebURL.Host && strings.Contains(u.Path, "/blob/") {
+			u.Path = strings.Replace(u.Path, "/blob/", "/raw/", 1)
+		}
+		return u.String()
+	}
+
+	return client.RawURL(repo, ref, repoPath(dir, u.Path))
+}
+
+// resolveLinkURL turns a relative link into the blob URL of the file at ref,
+// keeping any fragment.
+func resolveLinkURL(client *githubapi.Client, repo githubapi.Repository, ref, dir, dest string) string {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return dest
+	}
+	resolved := client.BlobURL(repo, ref, repoPath(dir, u.Path))
+	if u.Fragment != "" {
+		resolved += "#" + u.Fragment
+	}
+	return resolved
+}
+
+// repoFilePath reports the file a blob URL of this repository points at,
+// along with its fragment.
+func repoFilePath(client *githubapi.Client, repo githubapi.Repository, ref, dest string) (string, string, bool) {
+	u, err := url.Parse(dest)
+	if err != nil || u.Host != client.WebURL.Host {
+		return "", "", false
+	}
+	prefix := client.WebURL.Path + repo.Owner.Login + "/" + repo.Name + "/blob/"
+	if len(u.Path) <= len(prefix) || !strings.EqualFold(u.Path[:len(prefix)], prefix) {
+		return "", "", false
+	}
+	rest := u.Path[len(prefix):]
+	if after, ok := strings.CutPrefix(rest, ref+"/"); ok {
+		rest = after
+	} else {
+		_, rest, _ = strings.Cut(rest, "/")
+	}
+	return rest, u.Fragment, rest != ""
+}
+
+func fetchDocCmd(client *githubapi.Client, repo githubapi.Repository, ref, filePath, anchor string) tea.Cmd {
+	return func() tea.Msg {
+		content, err := client.GetContents(repo, filePath, ref)
+		if err != nil {
+			return docMsg{Repo: repo.FullName, Ref: ref, Path: filePath, Err: err}
+		}
+		data, err := content.Decode()
+		return docMsg{Repo: repo.FullName, Ref: ref, Path: filePath, Anchor: anchor, Content: string(data), Err: err}
+	}
+}
+
+// prepareMarkdown readies the document at docPath for rendering: relative
+// links point at ref and images are swapped for tokens to splice in later.
+func prepareMarkdown(client *githubapi.Client, repo githubapi.Repository, ref, docPath, raw string) (string, []markdown.Image) {
+	dir := path.Dir(docPath)
+	prepared := markdown.RewriteLinks(raw, func(dest string) string {
+		return resolveLinkURL(client, repo, ref, dir, dest)
+	})
+	return markdown.ExtractImages(prepared)
+}
+
+func imagePlaceholder(img markdown.Image, note string) string {
+	label := img.Alt
+	if label == "" {
+		label = path.Base(img.Dest)
+	}
+	return lipgloss.NewStyle().Foreground(subtle).Italic(true).Render("  ▨ " + label + note)
+}
+
+// fetchImagesCmd downloads and renders the README images on a small worker
+// pool, reporting every image in a single message once all have finished.
+func fetchImagesCmd(client *githubapi.Client, repo githubapi.Repository, ref, docPath string, images []markdown.Image) tea.Cmd {
+	if len(images) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := readmeImagesMsg{
+			Repo:   repo.FullName,
+			Ref:    ref,
+			Path:   docPath,
+			Blocks: make(map[string]string),
+			Errs:   make(map[string]error),
+		}
+
+		var (
+			mu  sync.Mutex
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, imageWorkers)
+		)
+		for _, img := range images {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				rendered, err := func() (string, error) {
+					data, err := client.Download(resolveImageURL(client, repo, ref, path.Dir(docPath), img.Dest))
+					if err != nil {
+						return "", err
+					}
+					return renderImage(data, termimg.Halfblocks, readmeWrap, readmeImageHeight)
+				}()
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					msg.Errs[img.Token] = err
+					return
+				}
+				msg.Blocks[img.Token] = rendered
+			}()
+		}
+		wg.Wait()
+		return msg
+	}
+}
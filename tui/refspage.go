@@ -0,0 +1,548 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+const (
+	refTabBranches int = iota
+	refTabTags
+)
+
+const (
+	refSortDefault int = iota
+	refSortName
+	refSortNewest
+)
+
+var refSortNames = []string{"GitHub order", "name", "newest commit"}
+
+// refMsg sets the branch or tag the pages of a repository work on.
+type refMsg struct {
+	Repo string
+	Ref  string
+	Tag  bool
+}
+
+type clonedMsg struct {
+	Repo string
+	Ref  string
+	Path string
+	Err  error
+}
+
+type refRow struct {
+	Name      string
+	Protected bool
+	SHA       string
+}
+
+type refListMsg struct {
+	Repo string
+	Tab  int
+	Rows []refRow
+	Err  error
+}
+
+type refCommitsMsg struct {
+	Repo    string
+	Tab     int
+	Commits map[string]githubapi.CommitItem
+	Err     error
+}
+
+type refCommitMsg struct {
+	Repo   string
+	SHA    string
+	Commit githubapi.CommitItem
+	Err    error
+}
+
+type RefsPageModel struct {
+	Width  int
+	Height int
+
+	repo     githubapi.Repository
+	current  string
+	CameFrom int
+
+	tab     int
+	rows    [2][]refRow
+	commits [2]map[string]githubapi.CommitItem
+	loading [2]bool
+	errs    [2]error
+	// details holds commits fetched one at a time when GraphQL is not
+	// available, keyed by SHA.
+	details       map[string]githubapi.CommitItem
+	fetching      map[string]bool
+	commitsFailed [2]bool
+
+	sortBy      int
+	filter      textinput.Model
+	filtering   bool
+	cursor      int
+	windowStart int
+	status      string
+
+	spinner spinner.Model
+	client  *githubapi.Client
+}
+
+func NewRefsPageModel(client *githubapi.Client, repo githubapi.Repository, current string, camefrom int) RefsPageModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	ti := textinput.New()
+	ti.Prompt = "filter: "
+	ti.Placeholder = "branch or tag name"
+	ti.CharLimit = 100
+
+	if current == "" {
+		current = readmeRef(repo)
+	}
+	return RefsPageModel{
+		repo:     repo,
+		current:  current,
+		CameFrom: camefrom,
+		loading:  [2]bool{true, true},
+		details:  make(map[string]githubapi.CommitItem),
+		fetching: make(map[string]bool),
+		filter:   ti,
+		spinner:  s,
+		client:   client,
+	}
+}
+
+func fetchRefListCmd(client *githubapi.Client, repo githubapi.Repository, tab int) tea.Cmd {
+	return func() tea.Msg {
+		var rows []refRow
+		opts := githubapi.ListOptions{Page: 1, PerPage: 100}
+		for len(rows) < githubapi.MaxRefs {
+			var (
+				resp *githubapi.Response
+				err  error
+			)
+			if tab == refTabTags {
+				var tags []githubapi.Tag
+				tags, resp, err = client.ListTags(repo, opts)
+				for _, t := range tags {
+					rows = append(rows, refRow{Name: t.Name, SHA: t.Commit.SHA})
+				}
+			} else {
+				var branches []githubapi.Branch
+				branches, resp, err = client.ListBranches(repo, opts)
+				for _, b := range branches {
+					rows = append(rows, refRow{Name: b.Name, Protected: b.Protected, SHA: b.Commit.SHA})
+				}
+			}
+			if err != nil {
+				return refListMsg{Repo: repo.FullName, Tab: tab, Err: err}
+			}
+			if resp == nil || resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+		return refListMsg{Repo: repo.FullName, Tab: tab, Rows: rows}
+	}
+}
+
+func fetchRefCommitsCmd(client *githubapi.Client, repo githubapi.Repository, tab int) tea.Cmd {
+	return func() tea.Msg {
+		commits, err := client.GetRefCommits(repo, tab == refTabTags)
+		return refCommitsMsg{Repo: repo.FullName, Tab: tab, Commits: commits, Err: err}
+	}
+}
+
+func fetchRefCommitCmd(client *githubapi.Client, repo githubapi.Repository, sha string) tea.Cmd {
+	return func() tea.Msg {
+		commit, err := client.GetCommit(repo, sha)
+		return refCommitMsg{Repo: repo.FullName, SHA: sha, Commit: commit, Err: err}
+	}
+}
+
+// cloneRefCmd clones the repository with ref checked out. Refs other than
+// the default branch get their own directory so they sit beside a normal
+// clone.
+func cloneRefCmd(client *githubapi.Client, repo githubapi.Repository, ref string, tag bool) tea.Cmd {
+	return func() tea.Msg {
+		dir := repo.Name
+		if tag || ref != repo.DefaultBranch {
+			dir += "-" + strings.ReplaceAll(ref, "/", "-")
+		}
+		err := client.CloneRef(repo.CloneURL, dir, ref, tag, nil)
+		return clonedMsg{Repo: repo.FullName, Ref: ref, Path: dir, Err: err}
+	}
+}
+
+func cloneStatus(msg clonedMsg) string {
+	if msg.Err != nil {
+		return fmt.Sprintf("clone of %s failed: %v", msg.Ref, msg.Err)
+	}
+	return fmt.Sprintf("cloned %s into ./%s", msg.Ref, msg.Path)
+}
+
+func (m RefsPageModel) Init() tea.Cmd {
+	cmds := []tea.Cmd{
+		m.spinner.Tick,
+		fetchRefListCmd(m.client, m.repo, refTabBranches),
+		fetchRefListCmd(m.client, m.repo, refTabTags),
+	}
+	if m.client.Token != "" {
+		cmds = append(cmds,
+			fetchRefCommitsCmd(m.client, m.repo, refTabBranches),
+			fetchRefCommitsCmd(m.client, m.repo, refTabTags),
+		)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m RefsPageModel) editing() bool {
+	return m.filtering
+}
+
+func (m RefsPageModel) listHeight() int {
+	return max(m.Height-7, 1)
+}
+
+func (m RefsPageModel) commitFor(row refRow) (githubapi.CommitItem, bool) {
+	if c, ok := m.commits[m.tab][row.Name]; ok {
+		return c, true
+	}
+	c, ok := m.details[row.SHA]
+	return c, ok
+}
+
+// visible returns the rows of the current tab that pass the filter, in the
+// chosen order.
+func (m RefsPageModel) visible() []refRow {
+	query := strings.ToLower(strings.TrimSpace(m.filter.Value()))
+	var rows []refRow
+	for _, r := range m.rows[m.tab] {
+		if query == "" || strings.Contains(strings.ToLower(r.Name), query) {
+			rows = append(rows, r)
+		}
+	}
+
+	switch m.sortBy {
+	case refSortName:
+		sort.SliceStable(rows, func(i, j int) bool {
+			return strings.ToLower(rows[i].Name) < strings.ToLower(rows[j].Name)
+		})
+	case refSortNewest:
+		sort.SliceStable(rows, func(i, j int) bool {
+			ci, oki := m.commitFor(rows[i])
+			cj, okj := m.commitFor(rows[j])
+			if oki != okj {
+				return oki
+			}
+			return ci.Commit.Author.Date.After(cj.Commit.Author.Date)
+		})
+	}
+	return rows
+}
+
+func (m RefsPageModel) selected() (refRow, bool) {
+	rows := m.visible()
+	if m.cursor < 0 || m.cursor >= len(rows) {
+		return refRow{}, false
+	}
+	return rows[m.cursor], true
+}
+
+func (m *RefsPageModel) moveCursor(step int) {
+	n := len(m.visible())
+	m.cursor = max(0, min(m.cursor+step, n-1))
+	if m.cursor < m.windowStart {
+		m.windowStart = m.cursor
+	}
+	if m.cursor >= m.windowStart+m.listHeight() {
+		m.windowStart = m.cursor - m.listHeight() + 1
+	}
+}
+
+func (m *RefsPageModel) resetCursor() {
+	m.cursor = 0
+	m.windowStart = 0
+}
+
+// loadDetail fetches the selected ref's commit when the GraphQL listing is
+// not available, so only refs the user looks at cost a request.
+func (m *RefsPageModel) loadDetail() tea.Cmd {
+	row, ok := m.selected()
+	if !ok || row.SHA == "" || m.fetching[row.SHA] {
+		return nil
+	}
+	if _, ok := m.commitFor(row); ok {
+		return nil
+	}
+	if m.client.Token != "" && !m.commitsFailed[m.tab] {
+		return nil
+	}
+	m.fetching[row.SHA] = true
+	return fetchRefCommitCmd(m.client, m.repo, row.SHA)
+}
+
+func (m RefsPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.filter.Width = max(m.Width-12, 10)
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading[m.tab] {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case refListMsg:
+		if msg.Repo != m.repo.FullName {
+			return m, nil
+		}
+		m.loading[msg.Tab] = false
+		m.errs[msg.Tab] = msg.Err
+		m.rows[msg.Tab] = msg.Rows
+		if msg.Tab == m.tab {
+			m.resetCursor()
+			return m, m.loadDetail()
+		}
+		return m, nil
+
+	case refCommitsMsg:
+		if msg.Repo != m.repo.FullName {
+			return m, nil
+		}
+		if msg.Err != nil {
+			// Fall back to fetching the selected ref's commit on demand.
+			m.commitsFailed[msg.Tab] = true
+			if msg.Tab == m.tab {
+				return m, m.loadDetail()
+			}
+			return m, nil
+		}
+		m.commits[msg.Tab] = msg.Commits
+		return m, nil
+
+	case refCommitMsg:
+		if msg.Repo != m.repo.FullName {
+			return m, nil
+		}
+		delete(m.fetching, msg.SHA)
+		if msg.Err == nil {
+			m.details[msg.SHA] = msg.Commit
+		}
+		return m, nil
+
+	case clonedMsg:
+		if msg.Repo != m.repo.FullName {
+			return m, nil
+		}
+		m.status = cloneStatus(msg)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m RefsPageModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "enter":
+		m.filtering = false
+		m.filter.Blur()
+		return m, m.loadDetail()
+	}
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.resetCursor()
+	return m, cmd
+}
+
+func (m RefsPageModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "backspace":
+		return m, func() tea.Msg {
+			return NavMsg{to: m.CameFrom, from: RefsPage, repodata: m.repo, back: true}
+		}
+	case "tab":
+		m.tab = (m.tab + 1) % 2
+		m.resetCursor()
+		return m, tea.Batch(m.spinner.Tick, m.loadDetail())
+	case "/":
+		m.filtering = true
+		return m, m.filter.Focus()
+	case "esc":
+		if m.filter.Value() != "" {
+			m.filter.SetValue("")
+			m.resetCursor()
+			return m, m.loadDetail()
+		}
+	case "s":
+		m.sortBy = (m.sortBy + 1) % len(refSortNames)
+		m.resetCursor()
+		return m, m.loadDetail()
+	case "r":
+		if m.errs[m.tab] != nil {
+			m.errs[m.tab] = nil
+			m.loading[m.tab] = true
+			return m, tea.Batch(m.spinner.Tick, fetchRefListCmd(m.client, m.repo, m.tab))
+		}
+	case "j", "down":
+		m.moveCursor(1)
+		return m, m.loadDetail()
+	case "k", "up":
+		m.moveCursor(-1)
+		return m, m.loadDetail()
+	case "d", "ctrl+d":
+		m.moveCursor(m.listHeight() / 2)
+		return m, m.loadDetail()
+	case "u", "ctrl+u":
+		m.moveCursor(-m.listHeight() / 2)
+		return m, m.loadDetail()
+	case "enter":
+		row, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
+		choice := refMsg{Repo: m.repo.FullName, Ref: row.Name, Tag: m.tab == refTabTags}
+		return m, tea.Sequence(
+			func() tea.Msg {
+				return NavMsg{to: m.CameFrom, from: RefsPage, repodata: m.repo, back: true}
+			},
+			func() tea.Msg { return choice },
+		)
+	case "e", "h":
+		row, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
+		to := TreePage
+		if msg.String() == "h" {
+			to = CommitsPage
+		}
+		return m, func() tea.Msg {
+			return NavMsg{to: to, from: RefsPage, repodata: m.repo, ref: row.Name}
+		}
+	case "c":
+		row, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
+		m.status = fmt.Sprintf("cloning %s…", row.Name)
+		return m, cloneRefCmd(m.client, m.repo, row.Name, m.tab == refTabTags)
+	}
+	return m, nil
+}
+
+func (m RefsPageModel) renderTabs() string {
+	names := []string{"Branches", "Tags"}
+	var tabs []string
+	for i, name := range names {
+		label := name
+		if !m.loading[i] && m.errs[i] == nil {
+			label = fmt.Sprintf("%s (%d)", name, len(m.rows[i]))
+		}
+		style := lipgloss.NewStyle().Foreground(subtle).Padding(0, 1)
+		if i == m.tab {
+			style = style.Foreground(highlight).Bold(true).Underline(true)
+		}
+		tabs = append(tabs, style.Render(label))
+	}
+	sortLabel := lipgloss.NewStyle().Foreground(subtle).Render("  sorted by " + refSortNames[m.sortBy])
+	return lipgloss.JoinHorizontal(lipgloss.Top, append(tabs, sortLabel)...)
+}
+
+func (m RefsPageModel) renderRow(row refRow, active bool) string {
+	nameStyle := lipgloss.NewStyle().Foreground(text)
+	pointer := "  "
+	if active {
+		pointer = "> "
+		nameStyle = nameStyle.Foreground(highlight).Bold(true)
+	}
+
+	line := pointer + nameStyle.Render(row.Name)
+	if m.tab == refTabBranches && row.Name == m.repo.DefaultBranch {
+		line += " " + lipgloss.NewStyle().Foreground(special).Render("default")
+	}
+	if row.Protected {
+		line += " " + lipgloss.NewStyle().Foreground(warning).Render("protected")
+	}
+	if row.Name == m.current {
+		line += " " + lipgloss.NewStyle().Foreground(special).Render("● current")
+	}
+
+	meta := lipgloss.NewStyle().Foreground(special).Render(shortRef(row.SHA))
+	if c, ok := m.commitFor(row); ok {
+		summary := c.Summary()
+		meta += " " + lipgloss.NewStyle().Foreground(text).Render(summary) +
+			lipgloss.NewStyle().Foreground(subtle).Render(fmt.Sprintf(" · %s · %s", commitAuthor(c), formatDate(c.Commit.Author.Date)))
+	} else if m.fetching[row.SHA] {
+		meta += " " + m.spinner.View()
+	}
+
+	pad := max(36-ansi.StringWidth(line), 2)
+	return ansi.Truncate(line+strings.Repeat(" ", pad)+meta, m.Width, "…")
+}
+
+func (m RefsPageModel) renderList() string {
+	switch {
+	case m.loading[m.tab]:
+		return fmt.Sprintf("%s Loading refs...", m.spinner.View())
+	case m.errs[m.tab] != nil:
+		return renderErrorState(m.errs[m.tab], m.Width-4)
+	}
+
+	rows := m.visible()
+	if len(rows) == 0 {
+		if m.filter.Value() != "" {
+			return lipgloss.NewStyle().Foreground(subtle).Render("Nothing matches the filter.")
+		}
+		return lipgloss.NewStyle().Foreground(subtle).Render("None yet.")
+	}
+
+	var lines []string
+	end := min(m.windowStart+m.listHeight(), len(rows))
+	for i := m.windowStart; i < end; i++ {
+		lines = append(lines, m.renderRow(rows[i], i == m.cursor))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (m RefsPageModel) View() string {
+	title := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(m.repo.FullName + " · branches and tags")
+
+	filterLine := m.filter.View()
+	if !m.filtering && m.filter.Value() == "" {
+		filterLine = lipgloss.NewStyle().Foreground(subtle).Render("/ to filter")
+	}
+
+	hint := "j/k move • tab branches/tags • / filter • s sort • enter use for repository • e files • h history • c clone • backspace back"
+	if m.filtering {
+		hint = "type to filter • enter/esc done"
+	}
+	footer := lipgloss.NewStyle().Foreground(subtle).Render(hint)
+	if m.status != "" {
+		footer = lipgloss.NewStyle().Foreground(special).Render(m.status) + "  " + footer
+	}
+
+	body := lipgloss.NewStyle().Height(m.listHeight()).Render(m.renderList())
+	return lipgloss.JoinVertical(lipgloss.Left, title, m.renderTabs(), filterLine, "", body, "", ansi.Truncate(footer, m.Width, "…"))
+}
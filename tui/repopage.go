@@ -2,11 +2,17 @@ package tui
 
 import (
 	"fmt"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/chirag-diwan/RemGit/githubapi"
+	"github.com/chirag-diwan/RemGit/markdown"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -22,24 +28,55 @@ var (
 	readmeBoxStyle lipgloss.Style
 )
 
-type ReadmeMsg string
+type ReadmeMsg struct {
+	Repo    string
+	Ref     string
+	Content string
+	Err     error
+}
 
 type RepoPageModel struct {
-	Width         int
-	Height        int
-	CurrentRepo   githubapi.Repository
-	CameFrom      int
-	UserData      githubapi.UserSummary
-	Viewport      viewport.Model
-	ReadmeText    string
-	RawReadme     string
-	LoadingReadme bool
-	Imgmap        map[string]string
-	Count         int
-	CacheHeader   string
-}
-
-func NewRepoPageModel(data githubapi.Repository, userdata githubapi.UserSummary, camefrom int, width int, height int) RepoPageModel {
+	Width          int
+	Height         int
+	CurrentRepo    githubapi.Repository
+	Ref            string
+	CameFrom       int
+	UserData       githubapi.UserSummary
+	Viewport       viewport.Model
+	ReadmeText     string
+	RawReadme      string
+	PreparedReadme string
+	RenderedReadme string
+	DocPath        string
+	LoadingReadme  bool
+	Err            error
+	Images         []markdown.Image
+	Imgmap         map[string]string
+	Links          []markdown.Link
+	Outline        []markdown.Heading
+	Count          int
+	CacheHeader    string
+
+	refTag        bool
+	docStack      []readmeDoc
+	pendingDoc    string
+	hinting       bool
+	hintInput     string
+	linkStatus    string
+	showOutline   bool
+	outlineCursor int
+	pendingKey    string
+	searching     bool
+	searchInput   textinput.Model
+	searchQuery   string
+	matches       []markdown.Match
+	matchIndex    int
+	deleter       *repoDeleter
+
+	client *githubapi.Client
+}
+
+func NewRepoPageModel(client *githubapi.Client, data githubapi.Repository, userdata githubapi.UserSummary, camefrom int, width int, height int) RepoPageModel {
 	docStyle = lipgloss.NewStyle().Padding(1, 2)
 	descStyle = lipgloss.NewStyle().Foreground(text).Italic(true)
 
@@ -78,13 +115,23 @@ func NewRepoPageModel(data githubapi.Repository, userdata githubapi.UserSummary,
 	vp := viewport.New(width, height)
 	vp.Style = lipgloss.NewStyle().Align(lipgloss.Left)
 
+	si := textinput.New()
+	si.Prompt = "/"
+	si.Placeholder = "search document"
+	si.CharLimit = 100
+	si.Width = 40
+
 	m := RepoPageModel{
 		CurrentRepo:   data,
+		Ref:           readmeRef(data),
 		CameFrom:      camefrom,
 		UserData:      userdata,
 		Viewport:      vp,
 		LoadingReadme: true,
+		DocPath:       "README.md",
 		Imgmap:        make(map[string]string),
+		searchInput:   si,
+		client:        client,
 	}
 
 	m.CacheStaticContent()
@@ -93,10 +140,10 @@ func NewRepoPageModel(data githubapi.Repository, userdata githubapi.UserSummary,
 
 }
 
-func fetchReadmeCmd(repo githubapi.Repository) tea.Cmd {
+func fetchReadmeCmd(client *githubapi.Client, repo githubapi.Repository, ref string) tea.Cmd {
 	return func() tea.Msg {
-		content := githubapi.GetReadme(repo)
-		return ReadmeMsg(content)
+		content, err := client.GetReadme(repo, ref)
+		return ReadmeMsg{Repo: repo.FullName, Ref: ref, Content: content, Err: err}
 	}
 }
 
@@ -104,7 +151,7 @@ func (m RepoPageModel) Init() tea.Cmd {
 	m.CacheStaticContent()
 	m.Viewport.SetContent(m.renderFullPage())
 
-	return fetchReadmeCmd(m.CurrentRepo)
+	return fetchReadmeCmd(m.client, m.CurrentRepo, m.Ref)
 }
 
 func (m RepoPageModel) renderFullPage() string {
@@ -112,10 +159,18 @@ func (m RepoPageModel) renderFullPage() string {
 	var readmeBlock string
 	if m.LoadingReadme {
 		readmeBlock = lipgloss.NewStyle().Foreground(subtle).Padding(2).Render("Loading README...")
+	} else if m.Err != nil {
+		readmeBlock = renderErrorState(m.Err, 76)
 	} else {
+		readmeText := m.ReadmeText
+		if m.hinting {
+			readmeText = m.hintedText()
+		} else if len(m.matches) > 0 {
+			readmeText = markdown.Highlight(readmeText, m.matches, m.matchIndex)
+		}
 		readmeBlock = fmt.Sprintf("\n%s\n\n%s",
-			labelStyle.Render("README.md"),
-			m.ReadmeText,
+			labelStyle.Render(m.DocPath),
+			readmeText,
 		)
 	}
 	readmeSection := readmeBoxStyle.Width(82).Render(readmeBlock)
@@ -124,10 +179,240 @@ func (m RepoPageModel) renderFullPage() string {
 		lipgloss.Center,
 		m.CacheHeader,
 		readmeSection,
-		"\n"+lipgloss.NewStyle().Foreground(subtle).Render("(Scroll with j/k • backspace to go back)"),
 	)
-	return lipgloss.PlaceHorizontal(m.Width, lipgloss.Center, content)
+	return lipgloss.PlaceHorizontal(m.Viewport.Width, lipgloss.Center, content)
+
+}
+
+func (m RepoPageModel) renderFooter() string {
+	hint := "(j/k scroll • t contents • ]] [[ sections • / search • f follow link • h history • e files • b branches • i issues • p pulls • c clone • s settings • D delete • backspace back)"
+	if m.showOutline {
+		hint = "(j/k choose heading • enter jump • t close contents • backspace to go back)"
+	}
+	if m.hinting {
+		hint = fmt.Sprintf("follow link: %s_ • esc cancel", m.hintInput)
+	}
+	if m.searching {
+		hint = m.searchInput.View()
+	}
+	footer := lipgloss.NewStyle().Foreground(subtle).Render(hint)
+	if m.searchQuery != "" && !m.searching {
+		counter := fmt.Sprintf("no matches for %q", m.searchQuery)
+		if len(m.matches) > 0 {
+			counter = fmt.Sprintf("match %d/%d for %q • n/N next/prev • esc clear", m.matchIndex+1, len(m.matches), m.searchQuery)
+		}
+		footer = lipgloss.NewStyle().Foreground(special).Render(counter) + "  " + footer
+	}
+	if m.linkStatus != "" {
+		footer += "  " + lipgloss.NewStyle().Foreground(warning).Render(m.linkStatus)
+	}
+	return lipgloss.PlaceHorizontal(m.Width, lipgloss.Center, footer)
+}
+
+// readmeOffset is the line of the page content on which the document's
+// first rendered line sits: the header, the box border and the label.
+func (m RepoPageModel) readmeOffset() int {
+	return lipgloss.Height(m.CacheHeader) + 4
+}
+
+// showDocument renders raw as the document at docPath, resolving its links
+// and images relative to that path, and starts fetching the images.
+func (m *RepoPageModel) showDocument(docPath, raw string) tea.Cmd {
+	m.DocPath = docPath
+	m.RawReadme = raw
+	m.outlineCursor = 0
+
+	var prepared string
+	prepared, m.Images = prepareMarkdown(m.client, m.CurrentRepo, m.Ref, docPath, raw)
+	m.PreparedReadme = prepared
+
+	var err error
+	if m.RenderedReadme, err = renderMarkdown(prepared); err != nil {
+		m.Err = err
+		return nil
+	}
+
+	m.Imgmap = make(map[string]string)
+	for _, img := range m.Images {
+		m.Imgmap[img.Token] = imagePlaceholder(img, " (loading…)")
+	}
+	m.spliceImages()
+	return fetchImagesCmd(m.client, m.CurrentRepo, m.Ref, docPath, m.Images)
+}
+
+// spliceImages rebuilds ReadmeText from the current image blocks. Blocks
+// change the line count, so links and headings are located afresh.
+func (m *RepoPageModel) spliceImages() {
+	m.ReadmeText = markdown.SpliceImages(m.RenderedReadme, m.Imgmap)
+	m.Links = markdown.Links(m.PreparedReadme, m.ReadmeText)
+	m.Outline = markdown.Outline(m.PreparedReadme, m.ReadmeText)
+	m.matches = markdown.Search(m.ReadmeText, m.searchQuery)
+	m.matchIndex = min(m.matchIndex, max(len(m.matches)-1, 0))
+}
+
+// runSearch finds the query in the document and jumps to the first match
+// at or below the top of the viewport.
+func (m *RepoPageModel) runSearch(query string) {
+	m.searchQuery = query
+	m.matches = markdown.Search(m.ReadmeText, query)
+	m.matchIndex = 0
+
+	top := m.Viewport.YOffset - m.readmeOffset()
+	for i, match := range m.matches {
+		if match.Line >= top {
+			m.matchIndex = i
+			break
+		}
+	}
+	m.Viewport.SetContent(m.renderFullPage())
+	m.scrollToMatch()
+}
+
+func (m *RepoPageModel) scrollToMatch() {
+	if len(m.matches) == 0 {
+		return
+	}
+	line := m.readmeOffset() + m.matches[m.matchIndex].Line
+	if line < m.Viewport.YOffset || line >= m.Viewport.YOffset+m.Viewport.Height {
+		m.Viewport.SetYOffset(line - m.Viewport.Height/3)
+	}
+}
+
+func (m *RepoPageModel) cycleMatch(step int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.matchIndex = (m.matchIndex + step + len(m.matches)) % len(m.matches)
+	m.Viewport.SetContent(m.renderFullPage())
+	m.scrollToMatch()
+}
+
+func (m RepoPageModel) updateSearch(msg tea.KeyMsg) (RepoPageModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.searching = false
+		m.searchInput.Blur()
+		return m, nil
+	case "enter":
+		m.searching = false
+		m.searchInput.Blur()
+		m.runSearch(strings.TrimSpace(m.searchInput.Value()))
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	return m, cmd
+}
+
+func (m *RepoPageModel) scrollToAnchor(anchor string) bool {
+	if unescaped, err := url.PathUnescape(anchor); err == nil {
+		anchor = unescaped
+	}
+	for _, h := range m.Outline {
+		if strings.EqualFold(h.ID, anchor) {
+			m.Viewport.SetYOffset(m.readmeOffset() + h.Line)
+			return true
+		}
+	}
+	return false
+}
+
+func (m RepoPageModel) visibleLinks() []markdown.Link {
+	top := m.Viewport.YOffset - m.readmeOffset()
+	bottom := top + m.Viewport.Height
+
+	var links []markdown.Link
+	for _, link := range m.Links {
+		if link.Line >= 0 && link.Line >= top && link.Line < bottom {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// hintedText marks each visible link with its number, placed just before
+// the link's URL or text.
+func (m RepoPageModel) hintedText() string {
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#000")).Background(highlight).Bold(true)
+
+	lines := strings.Split(m.ReadmeText, "\n")
+	for i, link := range m.visibleLinks() {
+		marker := hintStyle.Render(strconv.Itoa(i + 1))
+		line := lines[link.Line]
+
+		idx := -1
+		for _, needle := range []string{link.Dest, link.Text} {
+			if needle != "" {
+				if idx = strings.Index(line, needle); idx >= 0 {
+					break
+				}
+			}
+		}
+		if idx >= 0 {
+			line = line[:idx] + marker + line[idx:]
+		} else {
+			line = marker + line
+		}
+		lines[link.Line] = ansi.Truncate(line, readmeWrap+2, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *RepoPageModel) followLink(link markdown.Link) tea.Cmd {
+	if anchor, ok := strings.CutPrefix(link.Dest, "#"); ok {
+		if !m.scrollToAnchor(anchor) {
+			m.linkStatus = "no heading #" + anchor
+		}
+		return nil
+	}
+
+	if filePath, anchor, ok := repoFilePath(m.client, m.CurrentRepo, m.Ref, link.Dest); ok {
+		if filePath == m.DocPath && anchor != "" {
+			if !m.scrollToAnchor(anchor) {
+				m.linkStatus = "no heading #" + anchor
+			}
+			return nil
+		}
+		if isMarkdownFile(filePath) {
+			m.linkStatus = "opening " + filePath + "…"
+			m.pendingDoc = filePath
+			return fetchDocCmd(m.client, m.CurrentRepo, m.Ref, filePath, anchor)
+		}
+	}
 
+	m.linkStatus = "opening " + link.Dest
+	return openBrowserCmd(link.Dest)
+}
+
+func (m RepoPageModel) updateHint(msg tea.KeyMsg) (RepoPageModel, tea.Cmd) {
+	links := m.visibleLinks()
+	switch key := msg.String(); key {
+	case "esc", "f":
+		m.hinting = false
+	case "backspace":
+		if m.hintInput != "" {
+			m.hintInput = m.hintInput[:len(m.hintInput)-1]
+		}
+	case "enter":
+		m.hinting = false
+		if n, err := strconv.Atoi(m.hintInput); err == nil && n >= 1 && n <= len(links) {
+			return m, m.followLink(links[n-1])
+		}
+	default:
+		if len(key) != 1 || key[0] < '0' || key[0] > '9' {
+			return m, nil
+		}
+		m.hintInput += key
+		n, _ := strconv.Atoi(m.hintInput)
+		if n > len(links) {
+			m.hintInput = ""
+			m.linkStatus = "no link " + strconv.Itoa(n)
+		} else if n >= 1 && n*10 > len(links) {
+			m.hinting = false
+			return m, m.followLink(links[n-1])
+		}
+	}
+	return m, nil
 }
 
 func (m *RepoPageModel) CacheStaticContent() {
@@ -162,6 +447,10 @@ func (m *RepoPageModel) CacheStaticContent() {
 	makeStat := func(label, icon string, val int) string {
 		return fmt.Sprintf("%s %s  %s", icon, statValStyle.Render(fmt.Sprintf("%d", val)), labelStyle.Render(label))
 	}
+	refLabel := "Branch: "
+	if m.refTag {
+		refLabel = "Tag: "
+	}
 	statsContent := lipgloss.JoinVertical(lipgloss.Left,
 		makeStat("Stars", "★", m.CurrentRepo.StargazersCount),
 		makeStat("Forks", "⑂", m.CurrentRepo.ForksCount),
@@ -169,13 +458,17 @@ func (m *RepoPageModel) CacheStaticContent() {
 		makeStat("Watchers", "👁", m.CurrentRepo.WatchersCount),
 		"\n",
 		labelStyle.Render("Size: ")+fmt.Sprintf("%d KB", m.CurrentRepo.Size),
-		labelStyle.Render("Branch: ")+m.CurrentRepo.DefaultBranch,
+		labelStyle.Render(refLabel)+m.Ref,
 	)
 	rightBox := boxStyle.Width(25).Height(12).Render(statsContent)
 	middleSection := lipgloss.JoinHorizontal(lipgloss.Top, leftBox, rightBox)
 
-	cloneLink := lipgloss.NewStyle().Foreground(lipgloss.Color("#43BF6D")).Render(m.CurrentRepo.CloneURL)
-	footer := lipgloss.JoinVertical(lipgloss.Left, labelStyle.Render("HTTP Clone:"), cloneLink)
+	linkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#43BF6D"))
+	footer := lipgloss.JoinVertical(lipgloss.Left,
+		labelStyle.Render("Web:"), linkStyle.Render(m.CurrentRepo.HTMLURL),
+		labelStyle.Render("HTTP Clone:"), linkStyle.Render(m.CurrentRepo.CloneURL),
+		labelStyle.Render("SSH Clone:"), linkStyle.Render(m.CurrentRepo.SSHURL),
+	)
 	footerBox := boxStyle.Width(82).Render(footer)
 
 	m.CacheHeader = lipgloss.JoinVertical(lipgloss.Center,
@@ -185,30 +478,244 @@ func (m *RepoPageModel) CacheStaticContent() {
 
 func (m RepoPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
-	var err error
+	if m.deleter != nil {
+		switch msg.(type) {
+		case tea.KeyMsg, tokenScopesMsg, repoDeletedMsg, spinner.TickMsg:
+			return m.updateDeleter(msg)
+		}
+	}
+
 	switch msg := msg.(type) {
 
 	case ReadmeMsg:
+		if msg.Repo != m.CurrentRepo.FullName || msg.Ref != m.Ref {
+			return m, nil
+		}
 		m.LoadingReadme = false
-		m.ReadmeText, err = glamour.Render(string(msg), "dark")
-		if err != nil {
-			panic(err)
+		m.Err = msg.Err
+		if m.Err == nil {
+			cmd = m.showDocument("README.md", msg.Content)
 		}
 		m.Viewport.SetContent(m.renderFullPage())
+		return m, cmd
+
+	case refMsg:
+		if msg.Repo != m.CurrentRepo.FullName || (msg.Ref == m.Ref && msg.Tag == m.refTag) {
+			return m, nil
+		}
+		m.Ref = msg.Ref
+		m.refTag = msg.Tag
+		m.docStack = nil
+		m.pendingDoc = ""
+		m.searchQuery = ""
+		m.matches = nil
+		m.LoadingReadme = true
+		m.Err = nil
+		m.CacheStaticContent()
+		m.Viewport.SetContent(m.renderFullPage())
+		m.Viewport.GotoTop()
+		return m, fetchReadmeCmd(m.client, m.CurrentRepo, m.Ref)
+
+	case repoUpdatedMsg:
+		if msg.Repo != m.CurrentRepo.FullName || msg.Updated.FullName == "" {
+			return m, nil
+		}
+		m.CurrentRepo = msg.Updated
+		m.CacheStaticContent()
+		m.Viewport.SetContent(m.renderFullPage())
+		return m, nil
+
+	case clonedMsg:
+		if msg.Repo != m.CurrentRepo.FullName {
+			return m, nil
+		}
+		m.linkStatus = cloneStatus(msg)
+		return m, nil
+
+	case docMsg:
+		if msg.Repo != m.CurrentRepo.FullName || msg.Ref != m.Ref || msg.Path != m.pendingDoc {
+			return m, nil
+		}
+		m.pendingDoc = ""
+		if msg.Err != nil {
+			m.linkStatus = errorTitle(msg.Err) + ": " + msg.Path
+			return m, nil
+		}
+		m.docStack = append(m.docStack, readmeDoc{Path: m.DocPath, Raw: m.RawReadme, YOffset: m.Viewport.YOffset})
+		m.linkStatus = ""
+		cmd = m.showDocument(msg.Path, msg.Content)
+		m.Viewport.SetContent(m.renderFullPage())
+		if msg.Anchor == "" || !m.scrollToAnchor(msg.Anchor) {
+			m.Viewport.SetYOffset(lipgloss.Height(m.CacheHeader))
+		}
+		return m, cmd
+
+	case browserMsg:
+		m.linkStatus = ""
+		if msg.Err != nil {
+			m.linkStatus = "could not open browser: " + msg.Err.Error()
+		}
+		return m, nil
+
+	case readmeImagesMsg:
+		if msg.Repo != m.CurrentRepo.FullName || msg.Ref != m.Ref || msg.Path != m.DocPath {
+			return m, nil
+		}
+		for _, img := range m.Images {
+			if block, ok := msg.Blocks[img.Token]; ok {
+				m.Imgmap[img.Token] = block
+			} else if _, failed := msg.Errs[img.Token]; failed {
+				m.Imgmap[img.Token] = imagePlaceholder(img, "")
+			}
+		}
+		m.spliceImages()
+		m.Viewport.SetContent(m.renderFullPage())
+		return m, nil
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
 
-		m.Viewport.Width = m.Width
-		m.Viewport.Height = m.Height
-
-		m.Viewport.SetContent(m.renderFullPage())
+		m.layout()
 
 	case tea.KeyMsg:
-		switch msg.String() {
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		if m.hinting {
+			m, cmd = m.updateHint(msg)
+			if !m.hinting {
+				m.hintInput = ""
+			}
+			m.Viewport.SetContent(m.renderFullPage())
+			return m, cmd
+		}
+		m.linkStatus = ""
+
+		key := msg.String()
+		if m.pendingKey != "" {
+			pair := m.pendingKey + key
+			m.pendingKey = ""
+			switch pair {
+			case "]]":
+				m.nextSection()
+				return m, nil
+			case "[[":
+				m.prevSection()
+				return m, nil
+			}
+		}
+		if key == "]" || key == "[" {
+			m.pendingKey = key
+			return m, nil
+		}
+
+		if m.showOutline {
+			switch key {
+			case "t", "esc":
+				m.showOutline = false
+				m.layout()
+			case "j", "down":
+				m.outlineCursor = min(m.outlineCursor+1, len(m.Outline)-1)
+			case "k", "up":
+				m.outlineCursor = max(m.outlineCursor-1, 0)
+			case "enter":
+				if m.outlineCursor < len(m.Outline) {
+					m.Viewport.SetYOffset(m.readmeOffset() + m.Outline[m.outlineCursor].Line)
+				}
+			}
+			if key != "backspace" {
+				return m, nil
+			}
+		}
+
+		switch key {
+		case "/":
+			if !m.LoadingReadme && m.Err == nil {
+				m.searching = true
+				m.searchInput.SetValue(m.searchQuery)
+				m.searchInput.CursorEnd()
+				return m, m.searchInput.Focus()
+			}
+			return m, nil
+		case "n":
+			m.cycleMatch(1)
+			return m, nil
+		case "N":
+			m.cycleMatch(-1)
+			return m, nil
+		case "esc":
+			if m.searchQuery != "" {
+				m.searchQuery = ""
+				m.matches = nil
+				m.Viewport.SetContent(m.renderFullPage())
+			}
+			return m, nil
+		case "h":
+			return m, func() tea.Msg {
+				return NavMsg{to: CommitsPage, from: RepoPage, repodata: m.CurrentRepo, ref: m.Ref}
+			}
+		case "e":
+			return m, func() tea.Msg {
+				return NavMsg{to: TreePage, from: RepoPage, repodata: m.CurrentRepo, ref: m.Ref}
+			}
+		case "i":
+			return m, func() tea.Msg {
+				return NavMsg{to: IssuesPage, from: RepoPage, repodata: m.CurrentRepo}
+			}
+		case "p":
+			return m, func() tea.Msg {
+				return NavMsg{to: PullsPage, from: RepoPage, repodata: m.CurrentRepo}
+			}
+		case "b":
+			return m, func() tea.Msg {
+				return NavMsg{to: RefsPage, from: RepoPage, repodata: m.CurrentRepo, ref: m.Ref}
+			}
+		case "c":
+			m.linkStatus = fmt.Sprintf("cloning %s @ %s…", m.CurrentRepo.FullName, m.Ref)
+			return m, cloneRefCmd(m.client, m.CurrentRepo, m.Ref, m.refTag)
+		case "s":
+			return m, func() tea.Msg {
+				return NavMsg{to: RepoSettingsPage, from: RepoPage, repodata: m.CurrentRepo}
+			}
+		case "D":
+			d, cmd := newRepoDeleter(m.client, []githubapi.Repository{m.CurrentRepo})
+			m.deleter = &d
+			return m, cmd
+		case "t":
+			if !m.LoadingReadme && m.Err == nil {
+				m.showOutline = true
+				m.outlineCursor = max(m.currentSection(), 0)
+				m.layout()
+			}
+			return m, nil
 		case "backspace":
+			if n := len(m.docStack); n > 0 {
+				prev := m.docStack[n-1]
+				m.docStack = m.docStack[:n-1]
+				cmd = m.showDocument(prev.Path, prev.Raw)
+				m.Viewport.SetContent(m.renderFullPage())
+				m.Viewport.SetYOffset(prev.YOffset)
+				return m, cmd
+			}
 			return m, func() tea.Msg {
-				return NavMsg{to: m.CameFrom, from: RepoPage, repodata: m.CurrentRepo, userdata: m.UserData}
+				return NavMsg{to: m.CameFrom, from: RepoPage, repodata: m.CurrentRepo, userdata: m.UserData, back: true}
+			}
+
+		case "f":
+			if !m.LoadingReadme && m.Err == nil && len(m.visibleLinks()) > 0 {
+				m.hinting = true
+				m.Viewport.SetContent(m.renderFullPage())
+			} else {
+				m.linkStatus = "no links on screen"
+			}
+			return m, nil
+
+		case "r":
+			if m.Err != nil {
+				m.Err = nil
+				m.LoadingReadme = true
+				m.Viewport.SetContent(m.renderFullPage())
+				return m, fetchReadmeCmd(m.client, m.CurrentRepo, m.Ref)
 			}
 
 		case "j", "down":
@@ -226,6 +733,23 @@ func (m RepoPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateDeleter runs the delete dialog and leaves the page once the
+// repository is gone.
+func (m RepoPageModel) updateDeleter(msg tea.Msg) (tea.Model, tea.Cmd) {
+	d, cmd, closed := m.deleter.update(msg)
+	m.deleter = &d
+	if !closed {
+		return m, cmd
+	}
+	m.deleter = nil
+	if slices.Contains(d.deleted(), m.CurrentRepo.FullName) {
+		return m, func() tea.Msg {
+			return NavMsg{to: m.CameFrom, from: RepoPage, userdata: m.UserData, back: true}
+		}
+	}
+	return m, nil
+}
+
 func safeStr(s *string, fallback string) string {
 	if s == nil || *s == "" {
 		return fallback
@@ -237,6 +761,100 @@ func formatDate(t time.Time) string {
 	return t.Format("02 Jan 2006")
 }
 
+// layout sizes the viewport around the contents sidebar and footer line.
+func (m *RepoPageModel) layout() {
+	m.Viewport.Width = m.Width
+	if m.showOutline {
+		m.Viewport.Width = max(m.Width-outlineWidth, 0)
+	}
+	m.Viewport.Height = m.Height - 1
+	m.Viewport.SetContent(m.renderFullPage())
+}
+
+// currentSection is the index of the last heading at or above the top of
+// the viewport, or -1 before the first heading.
+func (m RepoPageModel) currentSection() int {
+	top := m.Viewport.YOffset - m.readmeOffset()
+	current := -1
+	for i, h := range m.Outline {
+		if h.Line > top {
+			break
+		}
+		current = i
+	}
+	return current
+}
+
+func (m *RepoPageModel) nextSection() {
+	for _, h := range m.Outline {
+		if offset := m.readmeOffset() + h.Line; offset > m.Viewport.YOffset {
+			m.Viewport.SetYOffset(offset)
+			return
+		}
+	}
+}
+
+func (m *RepoPageModel) prevSection() {
+	for i := len(m.Outline) - 1; i >= 0; i-- {
+		if offset := m.readmeOffset() + m.Outline[i].Line; offset < m.Viewport.YOffset {
+			m.Viewport.SetYOffset(offset)
+			return
+		}
+	}
+}
+
+func (m RepoPageModel) renderOutline() string {
+	height := max(m.Height-1, 4)
+	rows := []string{labelStyle.Render("Contents"), ""}
+
+	if len(m.Outline) == 0 {
+		rows = append(rows, lipgloss.NewStyle().Foreground(subtle).Render("No headings"))
+	} else {
+		minLevel := m.Outline[0].Level
+		for _, h := range m.Outline {
+			minLevel = min(minLevel, h.Level)
+		}
+
+		visible := max(height-4, 1)
+		start := max(0, min(m.outlineCursor-visible/2, len(m.Outline)-visible))
+		current := m.currentSection()
+		for i := start; i < min(len(m.Outline), start+visible); i++ {
+			h := m.Outline[i]
+			entry := ansi.Truncate(strings.Repeat("  ", h.Level-minLevel)+h.Text, outlineWidth-6, "…")
+
+			style := lipgloss.NewStyle().Foreground(subtle)
+			if i == current {
+				style = lipgloss.NewStyle().Foreground(special)
+			}
+			pointer := "  "
+			if i == m.outlineCursor {
+				style = lipgloss.NewStyle().Foreground(highlight).Bold(true)
+				pointer = "> "
+			}
+			rows = append(rows, pointer+style.Render(entry))
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(subtle).
+		Padding(0, 1).
+		Width(outlineWidth - 2).
+		Height(height - 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
 func (m RepoPageModel) View() string {
-	return m.Viewport.View()
+	if m.deleter != nil {
+		return m.deleter.View(m.Width, m.Height)
+	}
+	body := m.Viewport.View()
+	if m.showOutline {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, m.renderOutline(), body)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, body, m.renderFooter())
+}
+
+func (m RepoPageModel) editing() bool {
+	return m.searching || m.deleter != nil
 }
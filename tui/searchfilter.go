@@ -0,0 +1,424 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+const (
+	filterLanguage   = "language"
+	filterStars      = "stars"
+	filterForkCount  = "forks"
+	filterTopic      = "topic"
+	filterUser       = "user"
+	filterOrg        = "org"
+	filterPushed     = "pushed"
+	filterLicense    = "license"
+	filterArchived   = "archived"
+	filterFork       = "fork"
+	filterVisibility = "visibility"
+	filterSort       = "sort"
+	filterOrder      = "order"
+	filterUserType   = "type"
+	filterFollowers  = "followers"
+	filterRepos      = "repos"
+	filterLocation   = "location"
+	filterBranch     = "branch"
+	filterPath       = "path"
+	filterAuthor     = "author"
+	filterDate       = "date"
+	filterState      = "state"
+	filterLabels     = "labels"
+	filterAssignee   = "assignee"
+	filterMilestone  = "milestone"
+	filterBase       = "base"
+	filterHead       = "head"
+	filterAffiliate  = "affiliation"
+)
+
+// filterField is either a free-text input or, when options is set, a choice
+// cycled with enter/space. labels mirrors options for display.
+type filterField struct {
+	key     string
+	label   string
+	input   textinput.Model
+	options []string
+	labels  []string
+	choice  int
+}
+
+func (f filterField) value() string {
+	if f.options != nil {
+		return f.options[f.choice]
+	}
+	return strings.TrimSpace(f.input.Value())
+}
+
+type filterPanel struct {
+	fields []filterField
+	focus  int
+	mode   int
+	err    string
+}
+
+func newTextFilter(key, label, placeholder string) filterField {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.Prompt = ""
+	ti.CharLimit = 100
+	ti.Width = 30
+	return filterField{key: key, label: label, input: ti}
+}
+
+func newChoiceFilter(key, label string, options, labels []string) filterField {
+	return filterField{key: key, label: label, options: options, labels: labels}
+}
+
+func newRepoFilterPanel() filterPanel {
+	return filterPanel{
+		mode: ModeNav,
+		fields: []filterField{
+			newTextFilter(filterLanguage, "Language", "go"),
+			newTextFilter(filterStars, "Stars", ">=100 or 10..500"),
+			newTextFilter(filterForkCount, "Fork count", "0 or >=10"),
+			newTextFilter(filterTopic, "Topic", "cli"),
+			newTextFilter(filterUser, "User", "octocat"),
+			newTextFilter(filterOrg, "Organisation", "github"),
+			newTextFilter(filterPushed, "Pushed", ">=2024-01-01"),
+			newTextFilter(filterLicense, "License", "mit"),
+			newChoiceFilter(filterArchived, "Archived", []string{"", "false", "true"}, []string{"any", "no", "only"}),
+			newChoiceFilter(filterFork, "Forks",
+				[]string{githubapi.ForkExclude, githubapi.ForkInclude, githubapi.ForkOnly},
+				[]string{"exclude", "include", "only"}),
+			newChoiceFilter(filterVisibility, "Visibility",
+				[]string{githubapi.VisibilityAny, githubapi.VisibilityPublic, githubapi.VisibilityPrivate},
+				[]string{"any", "public", "private"}),
+			newChoiceFilter(filterSort, "Sort",
+				[]string{"stars", "forks", "help-wanted-issues", "updated", githubapi.SortBestMatch},
+				[]string{"stars", "forks", "help wanted issues", "updated", "best match"}),
+			newChoiceFilter(filterOrder, "Order",
+				[]string{githubapi.OrderDesc, githubapi.OrderAsc},
+				[]string{"descending", "ascending"}),
+		},
+	}
+}
+
+func newUserFilterPanel() filterPanel {
+	return filterPanel{
+		mode: ModeNav,
+		fields: []filterField{
+			newChoiceFilter(filterUserType, "Account type",
+				[]string{githubapi.UserTypeAny, githubapi.UserTypeUser, githubapi.UserTypeOrg},
+				[]string{"any", "users", "organisations"}),
+			newTextFilter(filterFollowers, "Followers", ">=1000 or 10..100"),
+			newTextFilter(filterRepos, "Repositories", ">=10"),
+			newTextFilter(filterLocation, "Location", "Berlin"),
+			newTextFilter(filterLanguage, "Language", "rust"),
+			newChoiceFilter(filterSort, "Sort",
+				[]string{githubapi.SortBestMatch, "followers", "repositories", "joined"},
+				[]string{"best match", "followers", "repositories", "joined"}),
+			newChoiceFilter(filterOrder, "Order",
+				[]string{githubapi.OrderDesc, githubapi.OrderAsc},
+				[]string{"descending", "ascending"}),
+		},
+	}
+}
+
+func newCommitFilterPanel(ref string) filterPanel {
+	p := filterPanel{
+		mode: ModeNav,
+		fields: []filterField{
+			newTextFilter(filterBranch, "Branch / tag", "default branch"),
+			newTextFilter(filterPath, "Path", "cmd/main.go"),
+			newTextFilter(filterAuthor, "Author", "login or email"),
+			newTextFilter(filterDate, "Date", "2024-01-01..2024-06-30"),
+		},
+	}
+	p.fields[0].input.SetValue(ref)
+	return p
+}
+
+func newIssueFilterPanel() filterPanel {
+	return filterPanel{
+		mode: ModeNav,
+		fields: []filterField{
+			newChoiceFilter(filterState, "State",
+				[]string{githubapi.IssueStateOpen, githubapi.IssueStateClosed, githubapi.IssueStateAll},
+				[]string{"open", "closed", "all"}),
+			newTextFilter(filterLabels, "Labels", "bug,help wanted"),
+			newTextFilter(filterAssignee, "Assignee", "login, none or *"),
+			newTextFilter(filterMilestone, "Milestone", "title, number, none or *"),
+			newTextFilter(filterAuthor, "Author", "octocat"),
+			newChoiceFilter(filterSort, "Sort",
+				[]string{"created", "updated", "comments"},
+				[]string{"created", "updated", "comments"}),
+			newChoiceFilter(filterOrder, "Order",
+				[]string{githubapi.OrderDesc, githubapi.OrderAsc},
+				[]string{"descending", "ascending"}),
+		},
+	}
+}
+
+func newPullFilterPanel() filterPanel {
+	return filterPanel{
+		mode: ModeNav,
+		fields: []filterField{
+			newChoiceFilter(filterState, "State",
+				[]string{githubapi.IssueStateOpen, githubapi.IssueStateClosed, githubapi.PullStateMerged, githubapi.IssueStateAll},
+				[]string{"open", "closed", "merged", "all"}),
+			newTextFilter(filterBase, "Base branch", "main"),
+			newTextFilter(filterHead, "Head", "user:branch"),
+			newChoiceFilter(filterSort, "Sort",
+				[]string{"created", "updated", "popularity", "long-running"},
+				[]string{"created", "updated", "popularity", "long-running"}),
+			newChoiceFilter(filterOrder, "Order",
+				[]string{githubapi.OrderDesc, githubapi.OrderAsc},
+				[]string{"descending", "ascending"}),
+		},
+	}
+}
+
+// newMyReposFilterPanel filters the user's own repositories. Sort and order
+// are applied locally, so stars can be offered although the API lacks it.
+func newMyReposFilterPanel() filterPanel {
+	all := strings.Join([]string{githubapi.AffiliationOwner, githubapi.AffiliationCollaborator, githubapi.AffiliationOrgMember}, ",")
+	return filterPanel{
+		mode: ModeNav,
+		fields: []filterField{
+			newChoiceFilter(filterAffiliate, "Affiliation",
+				[]string{all, githubapi.AffiliationOwner, githubapi.AffiliationCollaborator, githubapi.AffiliationOrgMember},
+				[]string{"all", "owner", "collaborator", "organisation member"}),
+			newChoiceFilter(filterVisibility, "Visibility",
+				[]string{"all", "public", "private"},
+				[]string{"all", "public", "private"}),
+			newChoiceFilter(filterSort, "Sort",
+				[]string{myReposSortPushed, myReposSortUpdated, myReposSortName, myReposSortStars},
+				[]string{"recently pushed", "recently updated", "name", "stars"}),
+			newChoiceFilter(filterOrder, "Order",
+				[]string{githubapi.OrderDesc, githubapi.OrderAsc},
+				[]string{"descending", "ascending"}),
+		},
+	}
+}
+
+func (p filterPanel) value(key string) string {
+	for _, f := range p.fields {
+		if f.key == key {
+			return f.value()
+		}
+	}
+	return ""
+}
+
+func (p *filterPanel) reset() {
+	for i := range p.fields {
+		p.fields[i].input.SetValue("")
+		p.fields[i].choice = 0
+	}
+	p.err = ""
+}
+
+// Update handles a key while the panel is open. closed is true once the
+// user leaves the panel, signalling the caller to re-run the search.
+func (p filterPanel) Update(msg tea.KeyMsg) (filterPanel, tea.Cmd, bool) {
+	if p.mode == ModeEdit {
+		switch msg.String() {
+		case "esc", "enter":
+			p.mode = ModeNav
+			p.fields[p.focus].input.Blur()
+			return p, nil, false
+		}
+		var cmd tea.Cmd
+		p.fields[p.focus].input, cmd = p.fields[p.focus].input.Update(msg)
+		return p, cmd, false
+	}
+
+	switch msg.String() {
+	case "esc", "f":
+		return p, nil, true
+	case "j", "down", "tab":
+		p.focus = (p.focus + 1) % len(p.fields)
+	case "k", "up", "shift+tab":
+		p.focus = (p.focus - 1 + len(p.fields)) % len(p.fields)
+	case "x":
+		p.reset()
+	case "enter", " ":
+		f := &p.fields[p.focus]
+		if f.options != nil {
+			f.choice = (f.choice + 1) % len(f.options)
+			return p, nil, false
+		}
+		p.mode = ModeEdit
+		return p, f.input.Focus(), false
+	}
+	return p, nil, false
+}
+
+func (p filterPanel) View(width int) string {
+	activeStyle := lipgloss.NewStyle().Foreground(highlight)
+	inactiveStyle := lipgloss.NewStyle().Foreground(subtle)
+
+	var rows []string
+	for i, f := range p.fields {
+		style := inactiveStyle
+		pointer := "  "
+		if i == p.focus {
+			style = activeStyle
+			pointer = "> "
+		}
+
+		var val string
+		if f.options != nil {
+			val = lipgloss.NewStyle().Foreground(special).Render("‹ " + f.labels[f.choice] + " ›")
+		} else {
+			val = f.input.View()
+			if i == p.focus && p.mode == ModeEdit {
+				val += " ✐"
+			}
+		}
+		rows = append(rows, fmt.Sprintf("%s%s %s", pointer, style.Width(14).Render(f.label), val))
+	}
+
+	if p.err != "" {
+		rows = append(rows, "", lipgloss.NewStyle().Foreground(warning).Render(p.err))
+	}
+	rows = append(rows, "", inactiveStyle.Render("enter edit/cycle • x clear • esc apply"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(0, 1).
+		Width(width).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+// intRange parses the numeric range typed into the field key, naming the
+// field in the error.
+func (p filterPanel) intRange(key, label string) (githubapi.IntRange, error) {
+	r, err := githubapi.ParseIntRange(p.value(key))
+	if err != nil {
+		return r, fmt.Errorf("%s: %w", label, err)
+	}
+	return r, nil
+}
+
+func (p filterPanel) userQuery(text string) (githubapi.UserQuery, error) {
+	q := githubapi.UserQuery{
+		Text:     strings.TrimSpace(text),
+		Type:     p.value(filterUserType),
+		Location: p.value(filterLocation),
+		Language: p.value(filterLanguage),
+		Sort:     p.value(filterSort),
+		Order:    p.value(filterOrder),
+	}
+
+	var err error
+	if q.Followers, err = p.intRange(filterFollowers, "followers"); err != nil {
+		return q, err
+	}
+	if q.Repos, err = p.intRange(filterRepos, "repositories"); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+func (p filterPanel) repoQuery(text string) (githubapi.RepoQuery, error) {
+	q := githubapi.RepoQuery{
+		Text:       strings.TrimSpace(text),
+		Language:   p.value(filterLanguage),
+		Topic:      p.value(filterTopic),
+		User:       p.value(filterUser),
+		Org:        p.value(filterOrg),
+		License:    p.value(filterLicense),
+		Fork:       p.value(filterFork),
+		Visibility: p.value(filterVisibility),
+		Sort:       p.value(filterSort),
+		Order:      p.value(filterOrder),
+	}
+
+	var err error
+	if q.Stars, err = p.intRange(filterStars, "stars"); err != nil {
+		return q, err
+	}
+	if q.Forks, err = p.intRange(filterForkCount, "fork count"); err != nil {
+		return q, err
+	}
+	if q.Pushed, err = githubapi.ParseDateRange(p.value(filterPushed)); err != nil {
+		return q, fmt.Errorf("pushed: %w", err)
+	}
+
+	switch p.value(filterArchived) {
+	case "true":
+		archived := true
+		q.Archived = &archived
+	case "false":
+		archived := false
+		q.Archived = &archived
+	}
+	return q, nil
+}
+
+func (p filterPanel) commitOptions() (githubapi.CommitListOptions, error) {
+	opts := githubapi.CommitListOptions{
+		SHA:    p.value(filterBranch),
+		Path:   strings.Trim(p.value(filterPath), "/"),
+		Author: p.value(filterAuthor),
+	}
+
+	dates, err := githubapi.ParseDateRange(p.value(filterDate))
+	if err != nil {
+		return opts, fmt.Errorf("date: %w", err)
+	}
+	opts.Since = dates.From
+	if !dates.To.IsZero() {
+		// The range is inclusive, so run to the end of the last day.
+		opts.Until = dates.To.AddDate(0, 0, 1)
+	}
+	return opts, nil
+}
+
+func (p filterPanel) editing() bool {
+	return p.mode == ModeEdit
+}
+
+// issueOptions builds the list options from the panel. Milestone is passed
+// through as typed; titles are resolved to numbers when fetching.
+func (p filterPanel) issueOptions() githubapi.IssueListOptions {
+	var labels []string
+	for _, l := range strings.Split(p.value(filterLabels), ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			labels = append(labels, l)
+		}
+	}
+	return githubapi.IssueListOptions{
+		State:     p.value(filterState),
+		Labels:    strings.Join(labels, ","),
+		Assignee:  p.value(filterAssignee),
+		Milestone: p.value(filterMilestone),
+		Creator:   strings.TrimPrefix(p.value(filterAuthor), "@"),
+		Sort:      p.value(filterSort),
+		Direction: p.value(filterOrder),
+	}
+}
+
+func (p filterPanel) pullOptions() githubapi.PullListOptions {
+	return githubapi.PullListOptions{
+		State:     p.value(filterState),
+		Base:      p.value(filterBase),
+		Head:      p.value(filterHead),
+		Sort:      p.value(filterSort),
+		Direction: p.value(filterOrder),
+	}
+}
+
+func (p filterPanel) myRepoOptions() githubapi.RepoListOptions {
+	return githubapi.RepoListOptions{
+		Affiliation: p.value(filterAffiliate),
+		Visibility:  p.value(filterVisibility),
+	}
+}
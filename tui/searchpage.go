@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -17,6 +18,7 @@ import (
 const (
 	NavigationMode int = iota
 	SearchMode
+	FilterMode
 )
 
 const (
@@ -27,14 +29,19 @@ const (
 const (
 	HeaderHeight    = 3
 	SearchBarHeight = 3
+	QueryLineHeight = 1
 	FooterHeight    = 4
-	ChromeHeight    = HeaderHeight + SearchBarHeight + FooterHeight
+	ChromeHeight    = HeaderHeight + SearchBarHeight + QueryLineHeight + FooterHeight
 )
 
 var (
 	ItemsPerPage = 4
 )
 
+// loadMoreThreshold is how close the cursor gets to the end of the loaded
+// results before the next page is requested.
+const loadMoreThreshold = 3
+
 var (
 	styleSearchBar    lipgloss.Style
 	styleSearchDimmed lipgloss.Style
@@ -49,6 +56,26 @@ var (
 
 type progressMsg float64
 
+type searchResultMsg struct {
+	Result     utils.SearchResult
+	SearchType int
+	Request    int
+	Page       int
+	NextPage   int
+	Err        error
+}
+
+type userProfileMsg struct {
+	Login string
+	User  githubapi.User
+	Err   error
+}
+
+type cloneResultMsg struct {
+	Name string
+	Err  error
+}
+
 type progressWriter struct {
 	ch chan float64
 }
@@ -79,7 +106,10 @@ func (m *SearchPageModel) Resize(width, height int) {
 
 func (pw *progressWriter) Write(p []byte) (int, error) {
 	if pw.ch != nil {
-		pw.ch <- 0.05
+		select {
+		case pw.ch <- 0.05:
+		default:
+		}
 	}
 	return len(p), nil
 }
@@ -109,18 +139,34 @@ type SearchPageModel struct {
 	Width   int
 	Height  int
 	Spinner spinner.Model
-	Loading bool
 
 	CloneProgress io.Writer
 
 	ProgressBar  progress.Model
 	IsCloning    bool
 	progressChan chan float64
+	CloneStatus  string
+	CloneFailed  bool
+
+	LastQuery     string
+	LastRepoQuery githubapi.RepoQuery
+	LastUserQuery githubapi.UserQuery
+	RepoFilters   filterPanel
+	UserFilters   filterPanel
+
+	// Profiles caches full /users/{login} lookups for user cards; a nil
+	// entry means the request is in flight or failed.
+	Profiles    map[string]*githubapi.User
+	ProfileErrs map[string]error
+
+	listPager
 
 	Viewport viewport.Model
+
+	client *githubapi.Client
 }
 
-func NewSearchPageModel() SearchPageModel {
+func NewSearchPageModel(client *githubapi.Client) SearchPageModel {
 
 	styleSearchBar = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -143,21 +189,7 @@ func NewSearchPageModel() SearchPageModel {
 		Foreground(subtle).
 		Padding(0, 2)
 
-	styleCardActive = lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(highlight).
-		Padding(0, 1).
-		MarginBottom(0)
-
-	styleCardInactive = lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(subtle).
-		Padding(0, 1).
-		MarginBottom(0)
-
-	styleName = lipgloss.NewStyle().Bold(true).Foreground(text)
-	styleDesc = lipgloss.NewStyle().Italic(true).Foreground(subtle)
-	styleStats = lipgloss.NewStyle().Foreground(special)
+	initCardStyles()
 
 	ti := textinput.New()
 	ti.Placeholder = "Search GitHub..."
@@ -190,20 +222,170 @@ func NewSearchPageModel() SearchPageModel {
 		Width:       20,
 		Height:      20,
 		Spinner:     s,
-		Loading:     false,
 		ProgressBar: prog,
 		IsCloning:   false,
 		Viewport:    viewport,
+		RepoFilters: newRepoFilterPanel(),
+		UserFilters: newUserFilterPanel(),
+		Profiles:    make(map[string]*githubapi.User),
+		ProfileErrs: make(map[string]error),
+		client:      client,
 	}
 }
 
-func (m SearchPageModel) getQueryResult(query string) tea.Cmd {
+// initCardStyles sets up the styles of the result cards, which the search
+// and my repositories pages share.
+func initCardStyles() {
+	styleCardActive = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(0, 1).
+		MarginBottom(0)
+
+	styleCardInactive = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(subtle).
+		Padding(0, 1).
+		MarginBottom(0)
+
+	styleName = lipgloss.NewStyle().Bold(true).Foreground(text)
+	styleDesc = lipgloss.NewStyle().Italic(true).Foreground(subtle)
+	styleStats = lipgloss.NewStyle().Foreground(special)
+}
+
+func (m SearchPageModel) getQueryResult(request, page int) tea.Cmd {
+	client := m.client
+	searchType := m.SearchType
+	userQuery := m.LastUserQuery
+	repoQuery := m.LastRepoQuery
 	return func() tea.Msg {
+		opts := githubapi.ListOptions{Page: page, PerPage: githubapi.SearchPerPage}
+		msg := searchResultMsg{SearchType: searchType, Request: request, Page: page}
 
-		if m.SearchType == UserMode {
-			return utils.SearchResult{Users: githubapi.GetUsers(query)}
+		var resp *githubapi.Response
+		if searchType == UserMode {
+			msg.Result.Users, resp, msg.Err = client.GetUsers(userQuery, opts)
+		} else {
+			msg.Result.Repos, resp, msg.Err = client.GetRepos(repoQuery, opts)
 		}
-		return utils.SearchResult{Repos: githubapi.GetRepos(query)}
+		if resp != nil {
+			msg.NextPage = resp.NextPage
+		}
+		return msg
+	}
+}
+
+func (m SearchPageModel) totalCount() int {
+	if m.SearchType == UserMode {
+		return m.Result.Users.TotalCount
+	}
+	return m.Result.Repos.TotalCount
+}
+
+func (m SearchPageModel) reachableCount() int {
+	return min(m.totalCount(), githubapi.MaxSearchResults)
+}
+
+func (m *SearchPageModel) maybeLoadMore() tea.Cmd {
+	if m.getListLength() >= m.reachableCount() || !m.wantMore(m.Cursor, m.getListLength()) {
+		return nil
+	}
+	return tea.Batch(m.getQueryResult(m.request, m.nextPage), m.Spinner.Tick)
+}
+
+// startSearch composes the query from the search bar and filter panel and
+// kicks off the first page. On invalid filters it reopens the panel instead.
+func (m *SearchPageModel) startSearch() tea.Cmd {
+	m.LastQuery = m.SearchBar.Value()
+	filters := m.activeFilters()
+
+	var composed string
+	var err error
+	if m.SearchType == RepoMode {
+		m.LastRepoQuery, err = filters.repoQuery(m.LastQuery)
+		composed = m.LastRepoQuery.String()
+	} else {
+		m.LastUserQuery, err = filters.userQuery(m.LastQuery)
+		composed = m.LastUserQuery.String()
+	}
+	if err != nil {
+		filters.err = err.Error()
+		m.Mode = FilterMode
+		return nil
+	}
+	filters.err = ""
+	if composed == "" {
+		return nil
+	}
+
+	m.Mode = NavigationMode
+	m.SearchBar.Blur()
+	request := m.restart()
+	return tea.Batch(m.getQueryResult(request, 1), m.Spinner.Tick)
+}
+
+func (m *SearchPageModel) activeFilters() *filterPanel {
+	if m.SearchType == UserMode {
+		return &m.UserFilters
+	}
+	return &m.RepoFilters
+}
+
+func (m SearchPageModel) composedQuery() string {
+	var q fmt.Stringer
+	var sort, order string
+	var err error
+	if m.SearchType == RepoMode {
+		var rq githubapi.RepoQuery
+		rq, err = m.RepoFilters.repoQuery(m.SearchBar.Value())
+		q, sort, order = rq, rq.Sort, rq.Order
+	} else {
+		var uq githubapi.UserQuery
+		uq, err = m.UserFilters.userQuery(m.SearchBar.Value())
+		q, sort, order = uq, uq.Sort, uq.Order
+	}
+	if err != nil {
+		return lipgloss.NewStyle().Foreground(warning).Render(err.Error())
+	}
+
+	line := "q: " + q.String()
+	if sort != "" {
+		line += fmt.Sprintf(" • sort: %s %s", sort, order)
+	}
+	return styleDesc.Render(line)
+}
+
+func fetchUserProfileCmd(client *githubapi.Client, login string) tea.Cmd {
+	return func() tea.Msg {
+		user, err := client.GetUser(login)
+		return userProfileMsg{Login: login, User: user, Err: err}
+	}
+}
+
+// loadVisibleProfiles requests full profiles for the user cards currently
+// on screen that have not been asked for yet.
+func (m *SearchPageModel) loadVisibleProfiles() tea.Cmd {
+	if m.SearchType != UserMode {
+		return nil
+	}
+	end := min(m.WindowStart+ItemsPerPage, len(m.Result.Users.Items))
+
+	var cmds []tea.Cmd
+	for i := m.WindowStart; i < end; i++ {
+		login := m.Result.Users.Items[i].Login
+		if _, requested := m.Profiles[login]; requested {
+			continue
+		}
+		m.Profiles[login] = nil
+		cmds = append(cmds, fetchUserProfileCmd(m.client, login))
+	}
+	return tea.Batch(cmds...)
+}
+
+func cloneRepoCmd(client *githubapi.Client, url, path string, progress io.Writer) tea.Cmd {
+	return func() tea.Msg {
+		err := client.CloneURL(url, path, progress)
+		return cloneResultMsg{Name: path, Err: err}
 	}
 }
 
@@ -238,7 +420,10 @@ func (m *SearchPageModel) moveCursor(step int) {
 	}
 }
 
-func (m SearchPageModel) renderRepoCard(repo githubapi.Repository, isActive bool, width int) string {
+// repoCardHeight is the number of lines a repository card takes.
+const repoCardHeight = 5
+
+func renderRepoCard(repo githubapi.Repository, isActive bool, width int) string {
 	style := styleCardInactive
 	if isActive {
 		style = styleCardActive
@@ -261,15 +446,32 @@ func (m SearchPageModel) renderRepoCard(repo githubapi.Repository, isActive bool
 		lang = *repo.Language
 	}
 
+	var tags []string
+	if repo.Private {
+		tags = append(tags, "private")
+	}
+	if repo.Fork {
+		tags = append(tags, "fork")
+	}
+	if repo.Archived {
+		tags = append(tags, "archived")
+	}
+	tag := ""
+	if len(tags) > 0 {
+		tag = lipgloss.NewStyle().MarginLeft(2).Foreground(warning).Render(strings.Join(tags, " · "))
+	}
+
 	header := lipgloss.JoinHorizontal(lipgloss.Center,
 		styleName.Render(repo.FullName),
 		lipgloss.NewStyle().MarginLeft(2).Render(styleStats.Render(fmt.Sprintf("★ %d", repo.StargazersCount))),
+		tag,
 	)
 
 	body := styleDesc.Render(desc)
 	footer := lipgloss.NewStyle().Foreground(subtle).Render(fmt.Sprintf("%s • Updated %s", lang, repo.UpdatedAt.Format("02 Jan")))
 
-	content := lipgloss.JoinVertical(lipgloss.Left, header, body, footer)
+	lineStyle := lipgloss.NewStyle().MaxWidth(width - 8)
+	content := lipgloss.JoinVertical(lipgloss.Left, lineStyle.Render(header), body, footer)
 	return innerStyle.Render(content)
 }
 
@@ -279,13 +481,57 @@ func (m SearchPageModel) renderUserCard(user githubapi.UserSummary, isActive boo
 		style = styleCardActive
 	}
 	innerStyle := style.Copy().Width(width - 4)
+	lineStyle := lipgloss.NewStyle().MaxWidth(width - 8)
+
+	kind := "user"
+	if user.Type == "Organization" {
+		kind = "org"
+	}
+	profile := m.Profiles[user.Login]
 
-	content := lipgloss.JoinHorizontal(lipgloss.Center,
+	name := ""
+	if profile != nil && profile.Name != "" {
+		name = lipgloss.NewStyle().MarginLeft(1).Foreground(text).Render(profile.Name)
+	}
+	header := lipgloss.JoinHorizontal(lipgloss.Center,
 		lipgloss.NewStyle().Foreground(special).Render("(o) "),
 		styleName.Render(user.Login),
-		lipgloss.NewStyle().MarginLeft(2).Foreground(subtle).Render("View Profile →"),
+		name,
+		lipgloss.NewStyle().MarginLeft(2).Foreground(subtle).Render(kind),
 	)
 
+	if profile == nil {
+		body := styleDesc.Render("Loading profile...")
+		if err := m.ProfileErrs[user.Login]; err != nil {
+			body = lipgloss.NewStyle().Foreground(warning).Render("Profile unavailable: " + errorTitle(err))
+		}
+		return innerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lineStyle.Render(header), body, ""))
+	}
+
+	bio := profile.Bio
+	if bio == "" {
+		bio = "No bio."
+	}
+	bio = strings.Join(strings.Fields(bio), " ")
+
+	details := []string{
+		fmt.Sprintf("%d followers", profile.Followers),
+		fmt.Sprintf("%d following", profile.Following),
+		fmt.Sprintf("%d repos", profile.PublicRepos),
+	}
+	if profile.Company != "" {
+		details = append(details, profile.Company)
+	}
+	if profile.Location != "" {
+		details = append(details, profile.Location)
+	}
+	details = append(details, "joined "+profile.CreatedAt.Format("Jan 2006"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		lineStyle.Render(header),
+		lineStyle.Render(styleDesc.Render(bio)),
+		lineStyle.Render(styleStats.Render(strings.Join(details, " • "))),
+	)
 	return innerStyle.Render(content)
 }
 
@@ -336,28 +582,100 @@ func (m SearchPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, tea.Batch(cmd, waitForProgress(m.progressChan)), cmd)
 
 	case spinner.TickMsg:
-		if m.Loading {
+		if m.loading || m.loadingMore {
 			m.Spinner, cmd = m.Spinner.Update(msg)
 			cmds = append(cmds, cmd)
 		}
-	case utils.SearchResult:
-		m.Loading = false
-		m.Result = msg
+	case cloneResultMsg:
+		m.IsCloning = false
+		m.ProgressBar.SetPercent(0)
+		if msg.Err != nil {
+			m.CloneStatus = fmt.Sprintf("Clone of %s failed: %v", msg.Name, msg.Err)
+			m.CloneFailed = true
+		} else {
+			m.CloneStatus = fmt.Sprintf("Cloned into ./%s", msg.Name)
+			m.CloneFailed = false
+		}
+		return m, nil
+
+	case searchResultMsg:
+		if !m.landed(msg.Request, msg.Page, msg.NextPage, msg.Err) {
+			return m, nil
+		}
+		if msg.Page > 1 {
+			if msg.Err != nil {
+				break
+			}
+			if msg.SearchType == UserMode {
+				m.Result.Users.TotalCount = msg.Result.Users.TotalCount
+				m.Result.Users.Items = append(m.Result.Users.Items, msg.Result.Users.Items...)
+			} else {
+				m.Result.Repos.TotalCount = msg.Result.Repos.TotalCount
+				m.Result.Repos.Items = append(m.Result.Repos.Items, msg.Result.Repos.Items...)
+			}
+			break
+		}
+
+		m.Result = msg.Result
 		m.Cursor = 0
 		m.WindowStart = 0
 
 		ItemsPerPage = m.Viewport.Height / 5
-		return m, nil
+		cmds = append(cmds, m.loadVisibleProfiles())
+
+	case userProfileMsg:
+		if msg.Err != nil {
+			m.ProfileErrs[msg.Login] = msg.Err
+			break
+		}
+		user := msg.User
+		m.Profiles[msg.Login] = &user
 
 	case tea.KeyMsg:
+		if m.Mode == NavigationMode && m.err != nil {
+			switch msg.String() {
+			case "r":
+				request := m.restart()
+				return m, tea.Batch(m.getQueryResult(request, 1), m.Spinner.Tick)
+			case "backspace":
+				m.err = nil
+				m.Mode = SearchMode
+				m.SearchBar.Focus()
+				return m, textinput.Blink
+			}
+			return m, nil
+		}
+
+		if m.Mode == FilterMode {
+			var closed bool
+			filters := m.activeFilters()
+			*filters, cmd, closed = filters.Update(msg)
+			cmds = append(cmds, cmd)
+			if closed {
+				m.Mode = NavigationMode
+				cmds = append(cmds, m.startSearch())
+			}
+			return m, tea.Batch(cmds...)
+		}
+
 		if m.Mode == NavigationMode {
 			switch msg.String() {
+			case "f":
+				m.Mode = FilterMode
+				return m, nil
 			case "j", "down":
 				m.moveCursor(1)
+				cmds = append(cmds, m.maybeLoadMore(), m.loadVisibleProfiles())
 
 				m.Viewport.ScrollDown(1)
+			case "r":
+				if m.moreErr != nil {
+					m.moreErr = nil
+					cmds = append(cmds, m.maybeLoadMore())
+				}
 			case "k", "up":
 				m.moveCursor(-1)
+				cmds = append(cmds, m.loadVisibleProfiles())
 				m.Viewport.ScrollUp(1)
 			case "i", "/":
 				m.Mode = SearchMode
@@ -370,18 +688,29 @@ func (m SearchPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						from: SearchPage,
 					}
 				}
+			case "p":
+				return m, func() tea.Msg {
+					return NavMsg{to: PullsPage, from: SearchPage}
+				}
+			case "y":
+				return m, func() tea.Msg {
+					return NavMsg{to: MyReposPage, from: SearchPage}
+				}
 			case "c":
-				if m.SearchType == RepoMode && !m.IsCloning {
+				if m.SearchType == RepoMode && !m.IsCloning && m.getListLength() > 0 {
 					m.IsCloning = true
+					m.CloneStatus = ""
 					m.ProgressBar.SetPercent(0)
 
 					m.progressChan = make(chan float64)
 					pw := &progressWriter{ch: m.progressChan}
 					m.CloneProgress = pw
 					name := m.Result.Repos.Items[m.Cursor].Name
-					go githubapi.CloneURL(m.Result.Repos.Items[m.Cursor].CloneURL, name, &m.CloneProgress)
 
-					cmds = append(cmds, waitForProgress(m.progressChan))
+					cmds = append(cmds,
+						cloneRepoCmd(m.client, m.Result.Repos.Items[m.Cursor].CloneURL, name, m.CloneProgress),
+						waitForProgress(m.progressChan),
+					)
 				}
 			case "tab":
 				if m.SearchType == UserMode {
@@ -392,6 +721,9 @@ func (m SearchPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Cursor = 0
 				m.WindowStart = 0
 			case "enter":
+				if m.getListLength() == 0 {
+					break
+				}
 				if m.SearchType == UserMode {
 					return m, func() tea.Msg {
 						return NavMsg{to: UserPage, from: SearchPage, userdata: m.Result.Users.Items[m.Cursor]}
@@ -408,9 +740,11 @@ func (m SearchPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "enter":
 				m.Mode = NavigationMode
+				cmds = append(cmds, m.startSearch())
+			case "ctrl+f":
 				m.SearchBar.Blur()
-				m.Loading = true
-				cmds = append(cmds, tea.Batch(m.getQueryResult(m.SearchBar.Value()), m.Spinner.Tick))
+				m.Mode = FilterMode
+				return m, nil
 			case "esc":
 				m.Mode = NavigationMode
 				m.SearchBar.Blur()
@@ -464,7 +798,7 @@ func (m SearchPageModel) renderContentString() string {
 			for i, item := range subset {
 				isSelected := (m.WindowStart + i) == m.Cursor
 
-				listItems = append(listItems, m.renderRepoCard(item, isSelected, m.Viewport.Width))
+				listItems = append(listItems, renderRepoCard(item, isSelected, m.Viewport.Width))
 			}
 		}
 	} else {
@@ -474,9 +808,29 @@ func (m SearchPageModel) renderContentString() string {
 	return lipgloss.JoinVertical(lipgloss.Left, listItems...)
 }
 
+func (m SearchPageModel) renderResultCount() string {
+	loaded := m.getListLength()
+	if loaded == 0 {
+		return ""
+	}
+
+	status := fmt.Sprintf("Result %d • showing %d of %d", m.Cursor+1, loaded, m.totalCount())
+	if m.totalCount() > githubapi.MaxSearchResults {
+		status += fmt.Sprintf(" (GitHub only returns the first %d)", githubapi.MaxSearchResults)
+	}
+
+	switch {
+	case m.loadingMore:
+		status += fmt.Sprintf("  %s loading more...", m.Spinner.View())
+	case m.moreErr != nil:
+		status += lipgloss.NewStyle().Foreground(warning).Render(fmt.Sprintf("  failed to load more: %s (r to retry)", errorTitle(m.moreErr)))
+	}
+	return status
+}
+
 func (m SearchPageModel) View() string {
 
-	if m.Loading {
+	if m.loading {
 		if m.SearchType == UserMode {
 			return lipgloss.Place(
 				m.Width, m.Height,
@@ -512,6 +866,7 @@ func (m SearchPageModel) View() string {
 		sBar = styleSearchDimmed.Render(m.SearchBar.View())
 	}
 	sBar = lipgloss.NewStyle().Height(SearchBarHeight).Render(sBar)
+	queryLine := lipgloss.NewStyle().Height(QueryLineHeight).MaxWidth(m.Width - 4).Render(m.composedQuery())
 
 	var footerStatus string
 	if m.IsCloning {
@@ -520,17 +875,36 @@ func (m SearchPageModel) View() string {
 			m.ProgressBar.View(),
 		)
 	} else {
-		page := (m.WindowStart / ItemsPerPage) + 1
-		footerStatus = styleDesc.Render(fmt.Sprintf("Page %d", page))
+		footerStatus = styleDesc.Render(m.renderResultCount())
+		if m.CloneStatus != "" {
+			statusColor := special
+			if m.CloneFailed {
+				statusColor = warning
+			}
+			footerStatus = lipgloss.JoinVertical(lipgloss.Left,
+				footerStatus,
+				lipgloss.NewStyle().Foreground(statusColor).Render(m.CloneStatus),
+			)
+		}
 	}
 
 	footerStatus = lipgloss.NewStyle().Height(FooterHeight).Render(footerStatus)
 
+	body := m.Viewport.View()
+	if m.Mode == FilterMode {
+		body = lipgloss.Place(m.Viewport.Width, m.Viewport.Height, lipgloss.Center, lipgloss.Top,
+			m.activeFilters().View(min(m.Width-4, 70)))
+	} else if m.err != nil {
+		body = lipgloss.Place(m.Viewport.Width, m.Viewport.Height, lipgloss.Center, lipgloss.Center,
+			renderErrorState(m.err, m.Width-4))
+	}
+
 	content := lipgloss.JoinVertical(
 		lipgloss.Center,
 		header,
 		sBar,
-		m.Viewport.View(),
+		queryLine,
+		body,
 		footerStatus,
 	)
 
@@ -542,3 +916,7 @@ func (m SearchPageModel) View() string {
 		content,
 	)
 }
+
+func (m SearchPageModel) editing() bool {
+	return m.Mode == SearchMode || (m.Mode == FilterMode && m.activeFilters().editing())
+}
@@ -0,0 +1,637 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+const (
+	settingName = iota
+	settingDescription
+	settingHomepage
+	settingVisibility
+	settingDefaultBranch
+	settingIssues
+	settingProjects
+	settingWiki
+	settingDiscussions
+	settingMergeCommit
+	settingSquashMerge
+	settingRebaseMerge
+	settingAutoMerge
+	settingDeleteBranch
+	settingArchived
+	settingSave
+)
+
+const (
+	settingText int = iota
+	settingToggle
+	settingChoice
+	settingBranch
+)
+
+type repoSettingsMsg struct {
+	Repo string
+	Data githubapi.Repository
+	Err  error
+}
+
+// repoUpdatedMsg carries a repository after its settings changed. Repo is
+// the full name it had before, since a rename changes it. When a later
+// request of the update failed, Err is set and Updated is still filled in
+// with what was saved before it; Updated is empty when nothing was.
+type repoUpdatedMsg struct {
+	Repo    string
+	Updated githubapi.Repository
+	Err     error
+}
+
+// settingField is one row of the settings form. original is the value as
+// loaded, in the same form as value, so pending changes are a comparison.
+type settingField struct {
+	label    string
+	kind     int
+	input    textinput.Model
+	on       bool
+	options  []string
+	choice   int
+	original string
+}
+
+func (f settingField) value() string {
+	switch f.kind {
+	case settingToggle:
+		if f.on {
+			return "on"
+		}
+		return "off"
+	case settingChoice:
+		return f.options[f.choice]
+	}
+	return strings.TrimSpace(f.input.Value())
+}
+
+func (f settingField) changed() bool {
+	return f.value() != f.original
+}
+
+type RepoSettingsPageModel struct {
+	Width  int
+	Height int
+
+	repo     githubapi.Repository
+	CameFrom int
+
+	fields     []settingField
+	focusIndex int
+	mode       int
+	reviewing  bool
+	picker     *picker
+
+	loading   bool
+	loadErr   error
+	saving    bool
+	err       error
+	statusMsg string
+
+	spinner spinner.Model
+	client  *githubapi.Client
+}
+
+func NewRepoSettingsPage(client *githubapi.Client, repo githubapi.Repository, camefrom int) RepoSettingsPageModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	return RepoSettingsPageModel{
+		repo:     repo,
+		CameFrom: camefrom,
+		loading:  true,
+		spinner:  s,
+		client:   client,
+	}
+}
+
+// fetchRepoSettingsCmd loads the repository again, as the merge settings
+// and permissions only come with an authenticated request for it.
+func fetchRepoSettingsCmd(client *githubapi.Client, repo githubapi.Repository) tea.Cmd {
+	return func() tea.Msg {
+		data, err := client.GetRepo(repo.Owner.Login, repo.Name)
+		return repoSettingsMsg{Repo: repo.FullName, Data: data, Err: err}
+	}
+}
+
+// updateRepoCmd applies body. Archived repositories are read-only, so an
+// unarchive goes first and an archive last when other settings change too.
+func updateRepoCmd(client *githubapi.Client, repo githubapi.Repository, body githubapi.RepoUpdate) tea.Cmd {
+	original := repo.FullName
+	return func() tea.Msg {
+		var saved githubapi.Repository
+		fail := func(err error) tea.Msg {
+			return repoUpdatedMsg{Repo: original, Updated: saved, Err: err}
+		}
+
+		archived := body.Archived
+		body.Archived = nil
+		if archived != nil && !*archived {
+			updated, err := client.UpdateRepo(repo, githubapi.RepoUpdate{Archived: archived})
+			if err != nil {
+				return fail(err)
+			}
+			repo, saved, archived = updated, updated, nil
+		}
+		if body != (githubapi.RepoUpdate{}) {
+			updated, err := client.UpdateRepo(repo, body)
+			if err != nil {
+				return fail(err)
+			}
+			repo, saved = updated, updated
+		}
+		if archived != nil {
+			updated, err := client.UpdateRepo(repo, githubapi.RepoUpdate{Archived: archived})
+			if err != nil {
+				return fail(err)
+			}
+			repo = updated
+		}
+		return repoUpdatedMsg{Repo: original, Updated: repo}
+	}
+}
+
+func newSettingText(label, placeholder, value string) settingField {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.Prompt = ""
+	ti.CharLimit = 350
+	ti.Width = 50
+	ti.SetValue(value)
+	return settingField{label: label, kind: settingText, input: ti, original: value}
+}
+
+func newSettingToggle(label string, on bool) settingField {
+	f := settingField{label: label, kind: settingToggle, on: on}
+	f.original = f.value()
+	return f
+}
+
+// loadFields fills the form from repo, dropping any unsaved edits.
+func (m *RepoSettingsPageModel) loadFields(repo githubapi.Repository) {
+	visibility := []string{"public", "private"}
+	if repo.Visibility == "internal" {
+		visibility = append(visibility, "internal")
+	}
+	current := repo.Visibility
+	if current == "" {
+		current = "public"
+		if repo.Private {
+			current = "private"
+		}
+	}
+
+	branch := newSettingText("Default branch", "", repo.DefaultBranch)
+	branch.kind = settingBranch
+
+	m.fields = []settingField{
+		settingName:        newSettingText("Name", "repository name", repo.Name),
+		settingDescription: newSettingText("Description", "short description", safeStr(repo.Description, "")),
+		settingHomepage:    newSettingText("Homepage", "https://", safeStr(repo.Homepage, "")),
+		settingVisibility: {
+			label:    "Visibility",
+			kind:     settingChoice,
+			options:  visibility,
+			choice:   max(slices.Index(visibility, current), 0),
+			original: current,
+		},
+		settingDefaultBranch: branch,
+		settingIssues:        newSettingToggle("Issues", repo.HasIssues),
+		settingProjects:      newSettingToggle("Projects", repo.HasProjects),
+		settingWiki:          newSettingToggle("Wiki", repo.HasWiki),
+		settingDiscussions:   newSettingToggle("Discussions", repo.HasDiscussions),
+		settingMergeCommit:   newSettingToggle("Allow merge commits", repo.AllowMergeCommit),
+		settingSquashMerge:   newSettingToggle("Allow squash merging", repo.AllowSquashMerge),
+		settingRebaseMerge:   newSettingToggle("Allow rebase merging", repo.AllowRebaseMerge),
+		settingAutoMerge:     newSettingToggle("Allow auto-merge", repo.AllowAutoMerge),
+		settingDeleteBranch:  newSettingToggle("Delete head branches after merge", repo.DeleteBranchOnMerge),
+		settingArchived:      newSettingToggle("Archived (read-only)", repo.Archived),
+	}
+}
+
+// rebase moves the form onto repo as partly saved, keeping the edits that
+// were not so they can be saved again.
+func (m *RepoSettingsPageModel) rebase(repo githubapi.Repository) {
+	edited := m.fields
+	m.loadFields(repo)
+	for i, f := range edited {
+		if f.changed() {
+			f.original = m.fields[i].original
+			m.fields[i] = f
+		}
+	}
+}
+
+// pending builds the update for the fields that changed.
+func (m RepoSettingsPageModel) pending() githubapi.RepoUpdate {
+	var body githubapi.RepoUpdate
+	text := func(field int) *string {
+		if !m.fields[field].changed() {
+			return nil
+		}
+		v := m.fields[field].value()
+		return &v
+	}
+	toggle := func(field int) *bool {
+		if !m.fields[field].changed() {
+			return nil
+		}
+		on := m.fields[field].on
+		return &on
+	}
+	body.Name = text(settingName)
+	body.Description = text(settingDescription)
+	body.Homepage = text(settingHomepage)
+	body.Visibility = text(settingVisibility)
+	body.DefaultBranch = text(settingDefaultBranch)
+	body.HasIssues = toggle(settingIssues)
+	body.HasProjects = toggle(settingProjects)
+	body.HasWiki = toggle(settingWiki)
+	body.HasDiscussions = toggle(settingDiscussions)
+	body.AllowMergeCommit = toggle(settingMergeCommit)
+	body.AllowSquashMerge = toggle(settingSquashMerge)
+	body.AllowRebaseMerge = toggle(settingRebaseMerge)
+	body.AllowAutoMerge = toggle(settingAutoMerge)
+	body.DeleteBranchOnMerge = toggle(settingDeleteBranch)
+	body.Archived = toggle(settingArchived)
+	return body
+}
+
+func (m RepoSettingsPageModel) changes() []int {
+	var fields []int
+	for i, f := range m.fields {
+		if f.changed() {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
+
+// problem explains why the pending changes cannot be applied, or returns
+// "" when they can.
+func (m RepoSettingsPageModel) problem() string {
+	f := m.fields
+	switch {
+	case f[settingName].value() == "":
+		return "The repository needs a name."
+	case f[settingMergeCommit].value() == "off" && f[settingSquashMerge].value() == "off" && f[settingRebaseMerge].value() == "off":
+		return "At least one merge method must stay allowed."
+	case f[settingArchived].original == "on" && f[settingArchived].on && len(m.changes()) > 0:
+		return "Archived repositories are read-only; unarchive it to change other settings."
+	}
+	return ""
+}
+
+func (m RepoSettingsPageModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, fetchRepoSettingsCmd(m.client, m.repo))
+}
+
+func (m RepoSettingsPageModel) editing() bool {
+	return m.mode == ModeEdit || m.picker != nil
+}
+
+func (m RepoSettingsPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading && !m.saving {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case repoSettingsMsg:
+		if msg.Repo != m.repo.FullName || !m.loading {
+			return m, nil
+		}
+		m.loading = false
+		m.loadErr = msg.Err
+		if msg.Err != nil {
+			return m, nil
+		}
+		m.repo = msg.Data
+		m.loadFields(msg.Data)
+		return m, nil
+
+	case repoItemsMsg:
+		if msg.Repo == m.repo.FullName && msg.Kind == repoBranches && m.picker != nil {
+			m.picker.setItems(msg.Items, msg.Err)
+		}
+		return m, nil
+
+	case repoUpdatedMsg:
+		if msg.Repo != m.repo.FullName || !m.saving {
+			return m, nil
+		}
+		m.saving = false
+		m.err = msg.Err
+		if msg.Err != nil {
+			if msg.Updated.FullName != "" {
+				m.repo = msg.Updated
+				m.rebase(msg.Updated)
+			}
+			return m, nil
+		}
+		m.reviewing = false
+		m.repo = msg.Updated
+		m.loadFields(msg.Updated)
+		m.statusMsg = "Settings saved."
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.picker != nil {
+			return m.updatePicker(msg)
+		}
+		if m.mode == ModeEdit {
+			return m.updateInput(msg)
+		}
+		return m.updateNav(msg)
+	}
+	return m, nil
+}
+
+func (m RepoSettingsPageModel) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p, item, done, cmd := m.picker.update(msg)
+	m.picker = &p
+	if !done {
+		return m, cmd
+	}
+	m.picker = nil
+	if item != nil {
+		m.fields[settingDefaultBranch].input.SetValue(item.Value)
+	}
+	return m, nil
+}
+
+func (m RepoSettingsPageModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "enter":
+		m.mode = ModeNav
+		m.fields[m.focusIndex].input.Blur()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.fields[m.focusIndex].input, cmd = m.fields[m.focusIndex].input.Update(msg)
+	return m, cmd
+}
+
+func (m RepoSettingsPageModel) updateNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	back := func() tea.Msg {
+		return NavMsg{to: m.CameFrom, from: RepoSettingsPage, repodata: m.repo, back: true}
+	}
+
+	if m.loading || m.saving {
+		return m, nil
+	}
+	if m.loadErr != nil {
+		switch msg.String() {
+		case "r":
+			m.loadErr = nil
+			m.loading = true
+			return m, tea.Batch(m.spinner.Tick, fetchRepoSettingsCmd(m.client, m.repo))
+		case "backspace", "esc":
+			return m, back
+		}
+		return m, nil
+	}
+	if !m.admin() {
+		if msg.String() == "backspace" || msg.String() == "esc" {
+			return m, back
+		}
+		return m, nil
+	}
+
+	if m.reviewing {
+		switch msg.String() {
+		case "enter", "ctrl+s":
+			if m.problem() != "" {
+				return m, nil
+			}
+			m.saving = true
+			m.err = nil
+			m.statusMsg = ""
+			return m, tea.Batch(m.spinner.Tick, updateRepoCmd(m.client, m.repo, m.pending()))
+		case "esc", "backspace":
+			m.reviewing = false
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "backspace", "esc":
+		return m, back
+	case "tab", "down", "j":
+		m.focusIndex = (m.focusIndex + 1) % (settingSave + 1)
+	case "shift+tab", "up", "k":
+		m.focusIndex = (m.focusIndex + settingSave) % (settingSave + 1)
+	case "u":
+		m.loadFields(m.repo)
+		m.statusMsg = "Changes discarded."
+		m.err = nil
+	case "ctrl+s":
+		return m.review()
+	case "enter", " ":
+		if m.focusIndex == settingSave {
+			return m.review()
+		}
+		f := &m.fields[m.focusIndex]
+		switch f.kind {
+		case settingText:
+			m.mode = ModeEdit
+			return m, f.input.Focus()
+		case settingToggle:
+			f.on = !f.on
+		case settingChoice:
+			f.choice = (f.choice + 1) % len(f.options)
+		case settingBranch:
+			p := newPicker("Default branch")
+			m.picker = &p
+			return m, fetchRepoItemsCmd(m.client, m.repo, repoBranches)
+		}
+	}
+	return m, nil
+}
+
+// admin reports whether the user may change the settings. Permissions are
+// missing without a token, in which case saving fails with GitHub's reason.
+func (m RepoSettingsPageModel) admin() bool {
+	return m.repo.Permissions == nil || m.repo.Permissions.Admin
+}
+
+func (m RepoSettingsPageModel) review() (tea.Model, tea.Cmd) {
+	if len(m.changes()) == 0 {
+		m.statusMsg = "Nothing has changed."
+		return m, nil
+	}
+	m.reviewing = true
+	m.statusMsg = ""
+	m.err = nil
+	return m, nil
+}
+
+func (m RepoSettingsPageModel) renderReview() string {
+	label := lipgloss.NewStyle().Foreground(text).Bold(true).Width(34)
+	old := lipgloss.NewStyle().Foreground(colorClosed).Strikethrough(true)
+	now := lipgloss.NewStyle().Foreground(colorOpen)
+	show := func(v string) string {
+		if v == "" {
+			return "(empty)"
+		}
+		return v
+	}
+
+	rows := []string{heading.Render("Pending changes · " + m.repo.FullName), ""}
+	for _, i := range m.changes() {
+		f := m.fields[i]
+		rows = append(rows, label.Render(f.label)+old.Render(show(f.original))+" → "+now.Render(show(f.value())))
+	}
+	if m.fields[settingName].changed() {
+		rows = append(rows, "", lipgloss.NewStyle().Foreground(warning).Render("! Renaming changes the URL; GitHub redirects the old one, but update your remotes."))
+	}
+	if m.fields[settingVisibility].changed() && m.fields[settingVisibility].value() == "public" {
+		rows = append(rows, lipgloss.NewStyle().Foreground(warning).Render("! The code, issues and history become visible to everyone."))
+	}
+	if m.fields[settingArchived].changed() && m.fields[settingArchived].on {
+		rows = append(rows, lipgloss.NewStyle().Foreground(warning).Render("! Archiving makes the repository read-only until it is unarchived."))
+	}
+
+	status := lipgloss.NewStyle().Foreground(subtle).Render("enter apply • esc keep editing")
+	switch {
+	case m.saving:
+		status = fmt.Sprintf("%s Saving...", m.spinner.View())
+	case m.err != nil:
+		status = renderErrorState(m.err, 60)
+	case m.problem() != "":
+		status = lipgloss.NewStyle().Foreground(warning).Bold(true).Render(m.problem()) + "\n" +
+			lipgloss.NewStyle().Foreground(subtle).Render("esc keep editing")
+	}
+	rows = append(rows, "", status)
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m RepoSettingsPageModel) renderForm() string {
+	activeStyle := lipgloss.NewStyle().Foreground(highlight)
+	inactiveStyle := lipgloss.NewStyle().Foreground(subtle)
+	sectionStyle := lipgloss.NewStyle().Foreground(special).Bold(true)
+
+	modeStr := "NAV"
+	modeColor := subtle
+	if m.mode == ModeEdit {
+		modeStr = "EDIT"
+		modeColor = special
+	}
+	modeIndicator := lipgloss.NewStyle().Foreground(modeColor).Bold(true).Render("-- " + modeStr + " --")
+
+	renderField := func(i int) string {
+		f := m.fields[i]
+		style := inactiveStyle
+		if m.focusIndex == i {
+			style = activeStyle
+		}
+		marker := "  "
+		if f.changed() {
+			marker = lipgloss.NewStyle().Foreground(warning).Render("* ")
+		}
+
+		switch f.kind {
+		case settingToggle:
+			check := "[ ]"
+			if f.on {
+				check = lipgloss.NewStyle().Foreground(special).Render("[x]")
+			}
+			return marker + check + " " + style.Render(f.label)
+		case settingChoice:
+			return marker + style.Width(16).Render(f.label) + lipgloss.NewStyle().Foreground(text).Render("‹ "+f.value()+" ›")
+		case settingBranch:
+			return marker + style.Width(16).Render(f.label) + lipgloss.NewStyle().Foreground(text).Render(f.value()+" ▾")
+		}
+		label := f.label
+		if m.focusIndex == i && m.mode == ModeEdit {
+			label += " ✐"
+		}
+		return marker + style.Width(16).Render(label) + f.input.View()
+	}
+
+	rows := []string{heading.Render("Settings · " + m.repo.FullName), "", modeIndicator, "", sectionStyle.Render("General")}
+	for i := settingName; i <= settingDefaultBranch; i++ {
+		rows = append(rows, renderField(i))
+	}
+	rows = append(rows, "", sectionStyle.Render("Features"))
+	for i := settingIssues; i <= settingDiscussions; i++ {
+		rows = append(rows, renderField(i))
+	}
+	rows = append(rows, "", sectionStyle.Render("Pull requests"))
+	for i := settingMergeCommit; i <= settingDeleteBranch; i++ {
+		rows = append(rows, renderField(i))
+	}
+	rows = append(rows, "", sectionStyle.Render("Danger zone"), renderField(settingArchived), "")
+
+	saveBtn := fmt.Sprintf("[ Review %d changes ]", len(m.changes()))
+	if m.focusIndex == settingSave {
+		saveBtn = activeStyle.Bold(true).Render(saveBtn)
+	} else {
+		saveBtn = inactiveStyle.Render(saveBtn)
+	}
+	rows = append(rows, saveBtn, "")
+
+	switch {
+	case m.err != nil:
+		rows = append(rows, renderErrorState(m.err, 60))
+	case m.statusMsg != "":
+		rows = append(rows, lipgloss.NewStyle().Foreground(special).Bold(true).Render(m.statusMsg))
+	}
+	rows = append(rows, inactiveStyle.Render("j/k move • enter edit/toggle • ctrl+s review changes • u undo all • backspace back"))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m RepoSettingsPageModel) View() string {
+	if m.picker != nil {
+		return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, m.picker.View(m.Width-4, m.Height-4))
+	}
+
+	var content string
+	switch {
+	case m.loading:
+		content = fmt.Sprintf("%s Loading settings...", m.spinner.View())
+	case m.loadErr != nil:
+		content = renderErrorState(m.loadErr, 60)
+	case !m.admin():
+		content = lipgloss.JoinVertical(lipgloss.Left,
+			heading.Render("Settings · "+m.repo.FullName),
+			"",
+			lipgloss.NewStyle().Foreground(warning).Render("You need admin rights on this repository to change its settings."),
+			"",
+			lipgloss.NewStyle().Foreground(subtle).Render("backspace back"),
+		)
+	case m.reviewing:
+		content = m.renderReview()
+	default:
+		content = m.renderForm()
+	}
+
+	return lipgloss.Place(
+		m.Width, m.Height,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().Border(lipgloss.DoubleBorder()).BorderForeground(subtle).Padding(1, 2).Render(content),
+	)
+}
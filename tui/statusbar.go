@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chirag-diwan/RemGit/githubapi"
+)
+
+const StatusBarHeight = 1
+
+func renderStatusBar(client *githubapi.Client, width int) string {
+	host := client.WebURL.Host
+	left := lipgloss.NewStyle().Foreground(special).Render(host)
+
+	rates := client.Rates()
+	var parts []string
+	low := false
+	for _, r := range rates {
+		part := fmt.Sprintf("%s %d/%d", r.Resource, r.Remaining, r.Limit)
+		if r.Low() {
+			low = true
+			part = lipgloss.NewStyle().Foreground(warning).Bold(true).Render(part + " resets " + r.Reset.Format("15:04"))
+		}
+		parts = append(parts, part)
+	}
+
+	var right string
+	if len(parts) == 0 {
+		right = "API quota: unknown"
+	} else {
+		right = "API " + strings.Join(parts, " · ")
+		if low {
+			right = lipgloss.NewStyle().Foreground(warning).Render("⚠ quota low ") + right
+		} else if reset := earliestReset(rates); reset != "" {
+			right += " · resets " + reset
+		}
+	}
+	right = lipgloss.NewStyle().Foreground(subtle).Render(right)
+
+	gap := width - lipgloss.Width(left) - lipgloss.Width(right)
+	if gap < 1 {
+		gap = 1
+	}
+	return left + strings.Repeat(" ", gap) + right
+}
+
+func earliestReset(rates []githubapi.Rate) string {
+	var first githubapi.Rate
+	for _, r := range rates {
+		if r.Reset.IsZero() {
+			continue
+		}
+		if first.Reset.IsZero() || r.Reset.Before(first.Reset) {
+			first = r
+		}
+	}
+	if first.Reset.IsZero() {
+		return ""
+	}
+	return first.Reset.Format("15:04")
+}
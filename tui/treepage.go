@@ -0,0 +1,714 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blacktop/go-termimg"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/chirag-diwan/RemGit/diff"
+	"github.com/chirag-diwan/RemGit/githubapi"
+	"github.com/chirag-diwan/RemGit/markdown"
+)
+
+const (
+	treePaneWidth   = 36
+	previewMaxLines = 5000
+)
+
+type treeMsg struct {
+	Repo    string
+	Ref     string
+	Dir     string
+	Entries []githubapi.TreeEntry
+	// Lazy is set when the repository is too large for a recursive listing
+	// and Entries only covers Dir itself.
+	Lazy bool
+	Err  error
+}
+
+type fileMsg struct {
+	Repo string
+	Ref  string
+	Path string
+	Data []byte
+	Err  error
+}
+
+type refsMsg struct {
+	Repo  string
+	Items []pickerItem
+	Err   error
+}
+
+type savedMsg struct {
+	Repo string
+	Path string
+	Err  error
+}
+
+type TreePageModel struct {
+	Width  int
+	Height int
+
+	repo     githubapi.Repository
+	ref      string
+	CameFrom int
+
+	children map[string][]githubapi.TreeEntry
+	lazy     bool
+	dir      string
+	cursors  map[string]int
+	cursor   int
+
+	preview      viewport.Model
+	previewPath  string
+	previewData  []byte
+	previewFocus bool
+	previewErr   error
+	loadingFile  bool
+	prepared     string
+	rendered     string
+	picture      string
+	images       []markdown.Image
+	imgmap       map[string]string
+
+	refPicker *picker
+	status    string
+
+	loading bool
+	err     error
+	spinner spinner.Model
+	client  *githubapi.Client
+}
+
+func NewTreePageModel(client *githubapi.Client, repo githubapi.Repository, ref string, camefrom int) TreePageModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	if ref == "" {
+		ref = readmeRef(repo)
+	}
+	return TreePageModel{
+		repo:     repo,
+		ref:      ref,
+		CameFrom: camefrom,
+		children: make(map[string][]githubapi.TreeEntry),
+		cursors:  make(map[string]int),
+		preview:  viewport.New(0, 0),
+		loading:  true,
+		spinner:  s,
+		client:   client,
+	}
+}
+
+// fetchTreeCmd lists the whole repository in one request where GitHub
+// allows it, falling back to one directory at a time when the listing is
+// truncated. sha selects the subtree to list in that mode.
+func fetchTreeCmd(client *githubapi.Client, repo githubapi.Repository, ref, dir, sha string) tea.Cmd {
+	return func() tea.Msg {
+		if sha == "" {
+			tree, err := client.GetTree(repo, ref, true)
+			if err != nil {
+				return treeMsg{Repo: repo.FullName, Ref: ref, Dir: dir, Err: err}
+			}
+			if !tree.Truncated {
+				return treeMsg{Repo: repo.FullName, Ref: ref, Dir: dir, Entries: tree.Entries}
+			}
+			sha = ref
+		}
+
+		tree, err := client.GetTree(repo, sha, false)
+		if err != nil {
+			return treeMsg{Repo: repo.FullName, Ref: ref, Dir: dir, Lazy: true, Err: err}
+		}
+		for i := range tree.Entries {
+			tree.Entries[i].Path = path.Join(dir, tree.Entries[i].Path)
+		}
+		return treeMsg{Repo: repo.FullName, Ref: ref, Dir: dir, Entries: tree.Entries, Lazy: true}
+	}
+}
+
+func fetchFileCmd(client *githubapi.Client, repo githubapi.Repository, ref, filePath string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := client.GetFile(repo, filePath, ref)
+		return fileMsg{Repo: repo.FullName, Ref: ref, Path: filePath, Data: data, Err: err}
+	}
+}
+
+func fetchRefsCmd(client *githubapi.Client, repo githubapi.Repository) tea.Cmd {
+	return func() tea.Msg {
+		branches, _, err := client.ListBranches(repo, githubapi.ListOptions{PerPage: 100})
+		if err != nil {
+			return refsMsg{Repo: repo.FullName, Err: err}
+		}
+		tags, _, err := client.ListTags(repo, githubapi.ListOptions{PerPage: 100})
+		if err != nil {
+			return refsMsg{Repo: repo.FullName, Err: err}
+		}
+
+		var items []pickerItem
+		for _, b := range branches {
+			detail := "branch"
+			if b.Name == repo.DefaultBranch {
+				detail = "default branch"
+			}
+			items = append(items, pickerItem{Label: b.Name, Detail: detail, Value: b.Name})
+		}
+		for _, t := range tags {
+			items = append(items, pickerItem{Label: t.Name, Detail: "tag", Value: t.Name})
+		}
+		return refsMsg{Repo: repo.FullName, Items: items}
+	}
+}
+
+// saveFileCmd writes the file into the working directory, fetching it first
+// unless data is already at hand. Existing files are never overwritten.
+func saveFileCmd(client *githubapi.Client, repo githubapi.Repository, ref, filePath string, data []byte) tea.Cmd {
+	return func() tea.Msg {
+		name := path.Base(filePath)
+		if data == nil {
+			var err error
+			if data, err = client.GetFile(repo, filePath, ref); err != nil {
+				return savedMsg{Repo: repo.FullName, Path: name, Err: err}
+			}
+		}
+
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			if errors.Is(err, os.ErrExist) {
+				err = fmt.Errorf("%s already exists in this directory", name)
+			}
+			return savedMsg{Repo: repo.FullName, Path: name, Err: err}
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return savedMsg{Repo: repo.FullName, Path: name, Err: err}
+		}
+		return savedMsg{Repo: repo.FullName, Path: name, Err: f.Close()}
+	}
+}
+
+func (m TreePageModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, fetchTreeCmd(m.client, m.repo, m.ref, "", ""))
+}
+
+func (m TreePageModel) editing() bool {
+	return m.refPicker != nil
+}
+
+func (m TreePageModel) entries() []githubapi.TreeEntry {
+	return m.children[m.dir]
+}
+
+func (m TreePageModel) selected() (githubapi.TreeEntry, bool) {
+	entries := m.entries()
+	if m.cursor < 0 || m.cursor >= len(entries) {
+		return githubapi.TreeEntry{}, false
+	}
+	return entries[m.cursor], true
+}
+
+// addEntries files entries under their parent directories, directories
+// first and then by name, the way GitHub lists them.
+func (m *TreePageModel) addEntries(dir string, entries []githubapi.TreeEntry, lazy bool) {
+	if lazy {
+		m.children[dir] = append([]githubapi.TreeEntry(nil), entries...)
+	} else {
+		for _, e := range entries {
+			parent := path.Dir(e.Path)
+			if parent == "." {
+				parent = ""
+			}
+			m.children[parent] = append(m.children[parent], e)
+		}
+		if _, ok := m.children[""]; !ok {
+			m.children[""] = nil
+		}
+	}
+
+	for d, list := range m.children {
+		if lazy && d != dir {
+			continue
+		}
+		sort.SliceStable(list, func(i, j int) bool {
+			if (list[i].Type == "tree") != (list[j].Type == "tree") {
+				return list[i].Type == "tree"
+			}
+			return strings.ToLower(list[i].Path) < strings.ToLower(list[j].Path)
+		})
+	}
+}
+
+func (m *TreePageModel) enterDir(dir string) tea.Cmd {
+	m.cursors[m.dir] = m.cursor
+	m.dir = dir
+	m.cursor = m.cursors[dir]
+	if _, ok := m.children[dir]; ok || !m.lazy {
+		return nil
+	}
+	entry, _ := m.entryAt(dir)
+	m.loading = true
+	return tea.Batch(m.spinner.Tick, fetchTreeCmd(m.client, m.repo, m.ref, dir, entry.SHA))
+}
+
+func (m TreePageModel) entryAt(p string) (githubapi.TreeEntry, bool) {
+	parent := path.Dir(p)
+	if parent == "." {
+		parent = ""
+	}
+	for _, e := range m.children[parent] {
+		if e.Path == p {
+			return e, true
+		}
+	}
+	return githubapi.TreeEntry{}, false
+}
+
+func (m *TreePageModel) switchRef(ref string) tea.Cmd {
+	m.ref = ref
+	m.children = make(map[string][]githubapi.TreeEntry)
+	m.cursors = make(map[string]int)
+	m.dir = ""
+	m.cursor = 0
+	m.lazy = false
+	m.previewPath = ""
+	m.previewData = nil
+	m.previewErr = nil
+	m.previewFocus = false
+	m.status = ""
+	m.loading = true
+	m.err = nil
+	m.refreshPreview()
+	return tea.Batch(m.spinner.Tick, fetchTreeCmd(m.client, m.repo, m.ref, "", ""))
+}
+
+func (m *TreePageModel) layout() {
+	m.preview.Width = max(m.Width-treePaneWidth-2, 20)
+	m.preview.Height = max(m.Height-3, 1)
+	m.refreshPreview()
+}
+
+// showFile prepares the fetched file for the preview pane. Markdown goes
+// through the README pipeline, so its images are fetched afterwards.
+func (m *TreePageModel) showFile(filePath string, data []byte) tea.Cmd {
+	m.previewPath = filePath
+	m.previewData = data
+	m.previewErr = nil
+	m.images = nil
+	m.imgmap = nil
+	m.rendered = ""
+	m.picture = ""
+
+	if isImageFile(filePath) {
+		picture, err := renderImage(data, termimg.Halfblocks, m.preview.Width, max(m.preview.Height-2, 1))
+		if err != nil {
+			picture = lipgloss.NewStyle().Foreground(subtle).Render("Could not display image: " + err.Error())
+		}
+		m.picture = picture
+		return nil
+	}
+	if isMarkdownFile(filePath) && utf8.Valid(data) {
+		m.prepared, m.images = prepareMarkdown(m.client, m.repo, m.ref, filePath, string(data))
+		rendered, err := renderMarkdown(m.prepared)
+		if err != nil {
+			m.previewErr = err
+			return nil
+		}
+		m.rendered = rendered
+		m.imgmap = make(map[string]string)
+		for _, img := range m.images {
+			m.imgmap[img.Token] = imagePlaceholder(img, " (loading…)")
+		}
+		return fetchImagesCmd(m.client, m.repo, m.ref, filePath, m.images)
+	}
+	return nil
+}
+
+func (m *TreePageModel) refreshPreview() {
+	m.preview.SetContent(m.renderPreview())
+}
+
+func isImageFile(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".bmp", ".webp":
+		return true
+	}
+	return false
+}
+
+func isBinary(data []byte) bool {
+	head := data[:min(len(data), 8000)]
+	return bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(head)
+}
+
+func (m TreePageModel) renderPreview() string {
+	muted := lipgloss.NewStyle().Foreground(subtle)
+	switch {
+	case m.loadingFile:
+		return fmt.Sprintf("%s Loading %s...", m.spinner.View(), path.Base(m.previewPath))
+	case m.previewErr != nil:
+		return renderErrorState(m.previewErr, m.preview.Width-4)
+	case m.previewPath == "":
+		return muted.Render("Select a file and press enter to preview it.")
+	}
+
+	header := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(m.previewPath) +
+		muted.Render(fmt.Sprintf("  %s", humanSize(len(m.previewData))))
+
+	var body string
+	switch {
+	case m.rendered != "":
+		body = markdown.SpliceImages(m.rendered, m.imgmap)
+	case m.picture != "":
+		body = m.picture
+	case isBinary(m.previewData):
+		body = muted.Render("Binary file, not shown. Press w to download it.")
+	default:
+		body = m.renderCode()
+	}
+	return header + "\n\n" + body
+}
+
+func (m TreePageModel) renderCode() string {
+	source := strings.TrimSuffix(string(m.previewData), "\n")
+	lines := strings.Split(source, "\n")
+	truncated := len(lines) > previewMaxLines
+	if truncated {
+		lines = lines[:previewMaxLines]
+	}
+
+	hl := diff.NewHighlighter(path.Base(m.previewPath))
+	gutter := lipgloss.NewStyle().Foreground(subtle)
+	width := len(fmt.Sprint(len(lines)))
+	out := make([]string, 0, len(lines)+1)
+	for i, line := range lines {
+		row := gutter.Render(fmt.Sprintf("%*d ", width, i+1)) + hl.Line(strings.TrimSuffix(line, "\r"))
+		out = append(out, ansi.Truncate(row, m.preview.Width, "…"))
+	}
+	if truncated {
+		out = append(out, gutter.Render(fmt.Sprintf("… only the first %d lines are shown", previewMaxLines)))
+	}
+	return strings.Join(out, "\n")
+}
+
+func humanSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+func (m TreePageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.layout()
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading && !m.loadingFile {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		m.refreshPreview()
+		return m, cmd
+
+	case treeMsg:
+		if msg.Repo != m.repo.FullName || msg.Ref != m.ref || !m.loading {
+			return m, nil
+		}
+		m.loading = false
+		m.err = msg.Err
+		if msg.Err == nil {
+			m.lazy = m.lazy || msg.Lazy
+			m.addEntries(msg.Dir, msg.Entries, msg.Lazy)
+		}
+		return m, nil
+
+	case fileMsg:
+		if msg.Repo != m.repo.FullName || msg.Ref != m.ref || msg.Path != m.previewPath {
+			return m, nil
+		}
+		m.loadingFile = false
+		var cmd tea.Cmd
+		if msg.Err != nil {
+			m.previewErr = msg.Err
+		} else {
+			cmd = m.showFile(msg.Path, msg.Data)
+		}
+		m.refreshPreview()
+		m.preview.GotoTop()
+		return m, cmd
+
+	case readmeImagesMsg:
+		if msg.Repo != m.repo.FullName || msg.Ref != m.ref || msg.Path != m.previewPath || m.imgmap == nil {
+			return m, nil
+		}
+		for _, img := range m.images {
+			if block, ok := msg.Blocks[img.Token]; ok {
+				m.imgmap[img.Token] = block
+			} else if _, failed := msg.Errs[img.Token]; failed {
+				m.imgmap[img.Token] = imagePlaceholder(img, "")
+			}
+		}
+		m.refreshPreview()
+		return m, nil
+
+	case refsMsg:
+		if msg.Repo == m.repo.FullName && m.refPicker != nil {
+			m.refPicker.setItems(msg.Items, msg.Err)
+		}
+		return m, nil
+
+	case savedMsg:
+		if msg.Repo != m.repo.FullName {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.status = lipgloss.NewStyle().Foreground(warning).Render(errorTitle(msg.Err) + ": " + msg.Err.Error())
+		} else {
+			m.status = lipgloss.NewStyle().Foreground(special).Render("saved ./" + msg.Path)
+		}
+		return m, nil
+
+	case browserMsg:
+		if msg.Err != nil {
+			m.status = lipgloss.NewStyle().Foreground(warning).Render("could not open browser: " + msg.Err.Error())
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.refPicker != nil {
+			p, item, done, cmd := m.refPicker.update(msg)
+			m.refPicker = &p
+			if done {
+				m.refPicker = nil
+				if item != nil && item.Value != m.ref {
+					return m, m.switchRef(item.Value)
+				}
+			}
+			return m, cmd
+		}
+		if m.previewFocus {
+			return m.updatePreview(msg)
+		}
+		return m.updateTree(msg)
+	}
+	return m, nil
+}
+
+func (m TreePageModel) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "esc", "backspace", "h":
+		m.previewFocus = false
+	case "j", "down":
+		m.preview.ScrollDown(1)
+	case "k", "up":
+		m.preview.ScrollUp(1)
+	case "d", "ctrl+d":
+		m.preview.HalfPageDown()
+	case "u", "ctrl+u":
+		m.preview.HalfPageUp()
+	case "g":
+		m.preview.GotoTop()
+	case "G":
+		m.preview.GotoBottom()
+	case "w":
+		if m.previewPath != "" && m.previewData != nil {
+			return m, saveFileCmd(m.client, m.repo, m.ref, m.previewPath, m.previewData)
+		}
+	}
+	return m, nil
+}
+
+func (m TreePageModel) updateTree(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+	switch msg.String() {
+	case "backspace", "h", "left":
+		if m.dir == "" {
+			if msg.String() != "backspace" {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return NavMsg{to: m.CameFrom, from: TreePage, repodata: m.repo, back: true}
+			}
+		}
+		child := m.dir
+		parent := path.Dir(m.dir)
+		if parent == "." {
+			parent = ""
+		}
+		cmd := m.enterDir(parent)
+		for i, e := range m.entries() {
+			if e.Path == child {
+				m.cursor = i
+			}
+		}
+		return m, cmd
+	case "r":
+		if m.err != nil {
+			m.err = nil
+			m.loading = true
+			sha := ""
+			if m.lazy && m.dir != "" {
+				entry, _ := m.entryAt(m.dir)
+				sha = entry.SHA
+			}
+			return m, tea.Batch(m.spinner.Tick, fetchTreeCmd(m.client, m.repo, m.ref, m.dir, sha))
+		}
+		if m.previewErr != nil {
+			m.previewErr = nil
+			m.loadingFile = true
+			m.refreshPreview()
+			return m, tea.Batch(m.spinner.Tick, fetchFileCmd(m.client, m.repo, m.ref, m.previewPath))
+		}
+	case "j", "down":
+		m.cursor = min(m.cursor+1, max(len(m.entries())-1, 0))
+	case "k", "up":
+		m.cursor = max(m.cursor-1, 0)
+	case "g":
+		m.cursor = 0
+	case "G":
+		m.cursor = max(len(m.entries())-1, 0)
+	case "enter", "l", "right":
+		entry, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
+		switch entry.Type {
+		case "tree":
+			return m, m.enterDir(entry.Path)
+		case "commit":
+			m.status = lipgloss.NewStyle().Foreground(subtle).Render("submodule at " + shortRef(entry.SHA))
+			return m, nil
+		}
+		if entry.Path == m.previewPath && m.previewData != nil {
+			m.previewFocus = true
+			return m, nil
+		}
+		m.previewPath = entry.Path
+		m.previewData = nil
+		m.previewErr = nil
+		m.loadingFile = true
+		m.refreshPreview()
+		return m, tea.Batch(m.spinner.Tick, fetchFileCmd(m.client, m.repo, m.ref, entry.Path))
+	case "tab":
+		if m.previewPath != "" {
+			m.previewFocus = true
+		}
+	case "b":
+		p := newPicker("Switch branch or tag")
+		m.refPicker = &p
+		return m, fetchRefsCmd(m.client, m.repo)
+	case "w":
+		entry, ok := m.selected()
+		if !ok || entry.Type != "blob" {
+			return m, nil
+		}
+		var data []byte
+		if entry.Path == m.previewPath {
+			data = m.previewData
+		}
+		return m, saveFileCmd(m.client, m.repo, m.ref, entry.Path, data)
+	case "o":
+		if entry, ok := m.selected(); ok {
+			return m, openBrowserCmd(m.client.BlobURL(m.repo, m.ref, entry.Path))
+		}
+	}
+	return m, nil
+}
+
+func (m TreePageModel) renderTree() string {
+	height := max(m.Height-3, 1)
+	muted := lipgloss.NewStyle().Foreground(subtle)
+
+	dir := "/" + m.dir
+	title := muted.Bold(true).Render(ansi.TruncateLeft(dir, max(ansi.StringWidth(dir)-(treePaneWidth-2), 0), "…"))
+	rows := []string{title}
+
+	entries := m.entries()
+	switch {
+	case m.loading:
+		rows = append(rows, fmt.Sprintf("%s Loading tree...", m.spinner.View()))
+	case m.err != nil:
+		rows = append(rows, lipgloss.NewStyle().Foreground(warning).Width(treePaneWidth-1).Render(errorTitle(m.err)), muted.Render("r retry"))
+	case len(entries) == 0:
+		rows = append(rows, muted.Render("Empty directory."))
+	}
+
+	if !m.loading && m.err == nil {
+		visible := max(height-1, 1)
+		start := max(0, min(m.cursor-visible/2, len(entries)-visible))
+		for i := start; i < min(len(entries), start+visible); i++ {
+			e := entries[i]
+			name := path.Base(e.Path)
+			style := lipgloss.NewStyle().Foreground(text)
+			switch e.Type {
+			case "tree":
+				name += "/"
+				style = style.Foreground(special)
+			case "commit":
+				name += " @ " + shortRef(e.SHA)
+				style = style.Foreground(subtle)
+			}
+			pointer := "  "
+			if i == m.cursor {
+				style = style.Bold(true)
+				if !m.previewFocus {
+					style = style.Foreground(highlight)
+				}
+				pointer = "> "
+			}
+			rows = append(rows, pointer+style.Render(ansi.Truncate(name, treePaneWidth-3, "…")))
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Width(treePaneWidth).
+		Height(height).
+		Border(lipgloss.NormalBorder(), false, true, false, false).
+		BorderForeground(subtle).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func (m TreePageModel) View() string {
+	header := lipgloss.NewStyle().Foreground(highlight).Bold(true).Render(m.repo.FullName) +
+		lipgloss.NewStyle().Foreground(special).Render(" @ "+m.ref)
+	if m.lazy {
+		header += lipgloss.NewStyle().Foreground(subtle).Render("  · large repository, directories load as you open them")
+	}
+
+	if m.refPicker != nil {
+		body := lipgloss.Place(m.Width, max(m.Height-1, 1), lipgloss.Center, lipgloss.Center, m.refPicker.View(m.Width-4, m.Height-4))
+		return lipgloss.JoinVertical(lipgloss.Left, header, body)
+	}
+
+	hint := "j/k move • enter open • h/backspace up • tab preview • b branch/tag • w download • o browser"
+	if m.previewFocus {
+		hint = "j/k d/u scroll • w download • tab/esc back to files"
+	}
+	footer := lipgloss.NewStyle().Foreground(subtle).Render(hint)
+	if m.status != "" {
+		footer += "  " + m.status
+	}
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top, m.renderTree(), " ", m.preview.View())
+	return lipgloss.JoinVertical(lipgloss.Left, ansi.Truncate(header, m.Width, "…"), body, ansi.Truncate(footer, m.Width, "…"))
+}
@@ -1,7 +1,10 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -9,16 +12,65 @@ import (
 	"github.com/chirag-diwan/RemGit/githubapi"
 )
 
+// UserReposMsg carries one load of the repository list. Request tells the
+// user's own repositories apart from an organization's after o toggles.
 type UserReposMsg struct {
+	Login   string
+	Request int
+	Repos   []githubapi.Repository
+	Err     error
+}
+
+type userDetailsMsg struct {
+	Login string
+	User  githubapi.User
+	Err   error
+}
+
+type pinnedReposMsg struct {
 	Login string
 	Repos []githubapi.Repository
+	Err   error
 }
 
+type avatarMsg struct {
+	Login    string
+	Rendered string
+	Err      error
+}
+
+type orgMembersMsg struct {
+	Login string
+	Count int
+	Err   error
+}
+
+type openRepoMsg struct {
+	Login   string
+	Request int
+	Repo    githubapi.Repository
+	Err     error
+}
+
+const (
+	userTabPinned int = iota
+	userTabRepos
+)
+
+var errNoPins = errors.New("no pinned repositories")
+
+const (
+	avatarWidth  = 20
+	avatarHeight = 10
+	pinnedLimit  = 6
+)
+
 var (
 	styleTitle      lipgloss.Style
 	styleRepoCard   lipgloss.Style
 	styleRepoActive lipgloss.Style
 	styleMeta       lipgloss.Style
+	styleCount      lipgloss.Style
 )
 
 type UserPageModel struct {
@@ -26,15 +78,33 @@ type UserPageModel struct {
 	Height          int
 	currentUserData githubapi.UserSummary
 
+	profile    *githubapi.User
+	profileErr error
+	avatar     string
+	members    int
+
+	pinned        []githubapi.Repository
+	pinnedErr     error
+	loadingPinned bool
+
+	tab      int
+	orgRepos bool
+
 	repos       []githubapi.Repository
 	cursor      int
 	windowStart int
+	request     int
 	loading     bool
+	opening     bool
+	openRequest int
+	err         error
 	spinner     spinner.Model
 	CameFrom    int
+
+	client *githubapi.Client
 }
 
-func NewUserPageModel(data githubapi.UserSummary, camefrom int) UserPageModel {
+func NewUserPageModel(client *githubapi.Client, data githubapi.UserSummary, camefrom int) UserPageModel {
 	styleTitle = lipgloss.NewStyle().
 		Foreground(highlight).
 		Bold(true).
@@ -53,6 +123,7 @@ func NewUserPageModel(data githubapi.UserSummary, camefrom int) UserPageModel {
 		BorderForeground(special)
 
 	styleMeta = lipgloss.NewStyle().Foreground(lipgloss.Color(subtle))
+	styleCount = lipgloss.NewStyle().Foreground(special).Bold(true)
 
 	s := spinner.New()
 	s.Spinner = spinner.Dot
@@ -62,8 +133,12 @@ func NewUserPageModel(data githubapi.UserSummary, camefrom int) UserPageModel {
 		currentUserData: data,
 		repos:           []githubapi.Repository{},
 		loading:         true,
+		loadingPinned:   true,
+		members:         -1,
+		request:         nextRequestID(),
 		spinner:         s,
 		CameFrom:        camefrom,
+		client:          client,
 	}
 }
 
@@ -72,25 +147,121 @@ func (model UserPageModel) SetUserData(data githubapi.UserSummary) UserPageModel
 	model.repos = []githubapi.Repository{}
 	model.cursor = 0
 	model.windowStart = 0
+	model.request = nextRequestID()
 	model.loading = true
+	model.err = nil
 	return model
 }
 
-func fetchReposCmd(username, url string) tea.Cmd {
+func fetchReposCmd(client *githubapi.Client, username, url string, request int) tea.Cmd {
 	return func() tea.Msg {
 
-		data := githubapi.GetRepoFromUrl(url)
+		data, err := client.GetRepoFromUrl(url)
 
-		return UserReposMsg{Login: username, Repos: data}
+		return UserReposMsg{Login: username, Request: request, Repos: data, Err: err}
 	}
 }
 
-func (model UserPageModel) Init() tea.Cmd {
+func fetchOrgReposCmd(client *githubapi.Client, org string, request int) tea.Cmd {
+	return func() tea.Msg {
+		data, _, err := client.ListOrgRepos(org, githubapi.ListOptions{PerPage: 100})
+		return UserReposMsg{Login: org, Request: request, Repos: data, Err: err}
+	}
+}
 
-	return tea.Batch(
+func fetchUserDetailsCmd(client *githubapi.Client, login string) tea.Cmd {
+	return func() tea.Msg {
+		user, err := client.GetUser(login)
+		return userDetailsMsg{Login: login, User: user, Err: err}
+	}
+}
+
+func fetchPinnedCmd(client *githubapi.Client, login string) tea.Cmd {
+	return func() tea.Msg {
+		repos, err := client.GetPinnedRepos(login)
+		return pinnedReposMsg{Login: login, Repos: repos, Err: err}
+	}
+}
+
+func fetchAvatarCmd(client *githubapi.Client, login, url string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := client.Download(url)
+		if err != nil {
+			return avatarMsg{Login: login, Err: err}
+		}
+		rendered, err := renderImage(data, imageProtocol(), avatarWidth, avatarHeight)
+		return avatarMsg{Login: login, Rendered: rendered, Err: err}
+	}
+}
+
+func fetchOrgMembersCmd(client *githubapi.Client, org string) tea.Cmd {
+	return func() tea.Msg {
+		count, err := client.CountOrgMembers(org)
+		return orgMembersMsg{Login: org, Count: count, Err: err}
+	}
+}
+
+func openRepoCmd(client *githubapi.Client, login string, request int, repo githubapi.Repository) tea.Cmd {
+	return func() tea.Msg {
+		full, err := client.GetRepo(repo.Owner.Login, repo.Name)
+		return openRepoMsg{Login: login, Request: request, Repo: full, Err: err}
+	}
+}
+
+func (model UserPageModel) Init() tea.Cmd {
+	login := model.currentUserData.Login
+	cmds := []tea.Cmd{
 		model.spinner.Tick,
-		fetchReposCmd(model.currentUserData.Login, model.currentUserData.ReposURL),
-	)
+		fetchReposCmd(model.client, login, model.currentUserData.ReposURL, model.request),
+		fetchUserDetailsCmd(model.client, login),
+		fetchPinnedCmd(model.client, login),
+	}
+	if model.currentUserData.AvatarURL != "" {
+		cmds = append(cmds, fetchAvatarCmd(model.client, login, model.currentUserData.AvatarURL))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (model UserPageModel) isOrg() bool {
+	if model.profile != nil {
+		return model.profile.IsOrganization()
+	}
+	return model.currentUserData.Type == "Organization"
+}
+
+// pinnedList falls back to the most starred repositories, the way GitHub
+// does for profiles without pins, and when GraphQL is unavailable.
+func (model UserPageModel) pinnedList() []githubapi.Repository {
+	if len(model.pinned) > 0 {
+		return model.pinned
+	}
+	popular := append([]githubapi.Repository(nil), model.repos...)
+	sort.SliceStable(popular, func(i, j int) bool {
+		return popular[i].StargazersCount > popular[j].StargazersCount
+	})
+	if len(popular) > pinnedLimit {
+		popular = popular[:pinnedLimit]
+	}
+	return popular
+}
+
+func (model UserPageModel) currentList() []githubapi.Repository {
+	if model.tab == userTabPinned {
+		return model.pinnedList()
+	}
+	return model.repos
+}
+
+// reloadRepos starts a new load of the repository list, so that replies to
+// earlier ones are dropped.
+func (model *UserPageModel) reloadRepos() tea.Cmd {
+	model.request = nextRequestID()
+	model.loading = true
+	model.err = nil
+	if model.orgRepos {
+		return fetchOrgReposCmd(model.client, model.currentUserData.Login, model.request)
+	}
+	return fetchReposCmd(model.client, model.currentUserData.Login, model.currentUserData.ReposURL, model.request)
 }
 
 func (model UserPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -98,25 +269,92 @@ func (model UserPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 
+	case tea.WindowSizeMsg:
+		model.Width = msg.Width
+		model.Height = msg.Height
+
 	case spinner.TickMsg:
-		if model.loading {
+		if model.loading || model.loadingPinned || model.opening {
 			model.spinner, cmd = model.spinner.Update(msg)
 			return model, cmd
 		}
 
 	case UserReposMsg:
-
-		if msg.Login != model.currentUserData.Login {
+		if msg.Login != model.currentUserData.Login || msg.Request != model.request {
 			return model, nil
 		}
 		model.loading = false
+		model.err = msg.Err
 		model.repos = msg.Repos
 		return model, nil
 
+	case userDetailsMsg:
+		if msg.Login != model.currentUserData.Login {
+			return model, nil
+		}
+		model.profileErr = msg.Err
+		if msg.Err != nil {
+			return model, nil
+		}
+		user := msg.User
+		model.profile = &user
+
+		var cmds []tea.Cmd
+		if model.avatar == "" && model.currentUserData.AvatarURL == "" && user.AvatarURL != "" {
+			cmds = append(cmds, fetchAvatarCmd(model.client, user.Login, user.AvatarURL))
+		}
+		if user.IsOrganization() {
+			cmds = append(cmds, fetchOrgMembersCmd(model.client, user.Login))
+		}
+		return model, tea.Batch(cmds...)
+
+	case pinnedReposMsg:
+		if msg.Login != model.currentUserData.Login {
+			return model, nil
+		}
+		model.loadingPinned = false
+		model.pinned = msg.Repos
+		model.pinnedErr = msg.Err
+		if len(model.pinned) == 0 && msg.Err == nil {
+			model.pinnedErr = errNoPins
+		}
+		return model, nil
+
+	case avatarMsg:
+		if msg.Login == model.currentUserData.Login && msg.Err == nil {
+			model.avatar = msg.Rendered
+		}
+		return model, nil
+
+	case orgMembersMsg:
+		if msg.Login == model.currentUserData.Login && msg.Err == nil {
+			model.members = msg.Count
+		}
+		return model, nil
+
+	case openRepoMsg:
+		if msg.Login != model.currentUserData.Login || msg.Request != model.openRequest || !model.opening {
+			return model, nil
+		}
+		model.opening = false
+		if msg.Err != nil {
+			model.err = msg.Err
+			return model, nil
+		}
+		return model, func() tea.Msg {
+			return NavMsg{
+				to:       RepoPage,
+				from:     UserPage,
+				repodata: msg.Repo,
+				userdata: model.currentUserData,
+			}
+		}
+
 	case tea.KeyMsg:
+		list := model.currentList()
 		switch msg.String() {
 		case "j", "down":
-			if len(model.repos) > 0 && model.cursor < len(model.repos)-1 {
+			if len(list) > 0 && model.cursor < len(list)-1 {
 				model.cursor++
 
 				if model.cursor >= model.windowStart+5 {
@@ -130,12 +368,42 @@ func (model UserPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					model.windowStart--
 				}
 			}
+		case "tab":
+			if model.tab == userTabPinned {
+				model.tab = userTabRepos
+			} else {
+				model.tab = userTabPinned
+			}
+			model.cursor = 0
+			model.windowStart = 0
+		case "o":
+			if model.isOrg() {
+				model.orgRepos = !model.orgRepos
+				model.tab = userTabRepos
+				model.cursor = 0
+				model.windowStart = 0
+				return model, tea.Batch(model.spinner.Tick, model.reloadRepos())
+			}
+		case "r":
+			if model.err != nil {
+				return model, tea.Batch(model.spinner.Tick, model.reloadRepos())
+			}
 		case "enter":
+			if len(list) == 0 || model.opening {
+				return model, nil
+			}
+			repo := list[model.cursor]
+			if repo.CloneURL == "" {
+				// Pinned entries come from GraphQL with only listing fields.
+				model.opening = true
+				model.openRequest = nextRequestID()
+				return model, tea.Batch(model.spinner.Tick, openRepoCmd(model.client, model.currentUserData.Login, model.openRequest, repo))
+			}
 			return model, func() tea.Msg {
 				return NavMsg{
 					to:       RepoPage,
 					from:     UserPage,
-					repodata: model.repos[model.cursor],
+					repodata: repo,
 					userdata: model.currentUserData,
 				}
 			}
@@ -144,6 +412,7 @@ func (model UserPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return NavMsg{
 					to:   SearchPage,
 					from: UserPage,
+					back: true,
 				}
 			}
 		}
@@ -151,33 +420,137 @@ func (model UserPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return model, nil
 }
 
+func (model UserPageModel) renderProfileHeader() string {
+	login := model.currentUserData.Login
+	p := model.profile
+
+	title := login
+	if p != nil && p.Name != "" {
+		title = fmt.Sprintf("%s (%s)", p.Name, login)
+	}
+
+	lines := []string{
+		styleTitle.Render(title),
+		styleMeta.Render(model.currentUserData.HTMLURL),
+	}
+
+	switch {
+	case p != nil:
+		if p.Bio != "" {
+			lines = append(lines, "", lipgloss.NewStyle().Foreground(text).Italic(true).Width(50).Render(p.Bio))
+		}
+
+		var facts []string
+		for _, f := range []string{p.Company, p.Blog, p.Location} {
+			if f != "" {
+				facts = append(facts, f)
+			}
+		}
+		if len(facts) > 0 {
+			lines = append(lines, "", styleMeta.Render(strings.Join(facts, " • ")))
+		}
+
+		lines = append(lines, "",
+			fmt.Sprintf("%s followers · %s following · %s public repos",
+				styleCount.Render(fmt.Sprintf("%d", p.Followers)),
+				styleCount.Render(fmt.Sprintf("%d", p.Following)),
+				styleCount.Render(fmt.Sprintf("%d", p.PublicRepos)),
+			),
+			styleMeta.Render("Joined "+formatDate(p.CreatedAt)),
+		)
+
+		if p.IsOrganization() {
+			members := "…"
+			if model.members >= 0 {
+				members = fmt.Sprintf("%d", model.members)
+			}
+			source := "o: switch to all organisation repositories"
+			if model.orgRepos {
+				source = "o: switch back to public repositories"
+			}
+			lines = append(lines,
+				fmt.Sprintf("%s public members", styleCount.Render(members)),
+				styleMeta.Render(source),
+			)
+		}
+	case model.profileErr != nil:
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(warning).Render("Profile unavailable: "+errorTitle(model.profileErr)))
+	default:
+		lines = append(lines, "", fmt.Sprintf("%s Loading profile...", model.spinner.View()))
+	}
+
+	info := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	if model.avatar == "" {
+		return info
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().MarginRight(3).Render(model.avatar),
+		info,
+	)
+}
+
+func (model UserPageModel) renderTabs() string {
+	pinnedLabel := "Pinned"
+	if len(model.pinned) == 0 && !model.loadingPinned {
+		pinnedLabel = "Popular"
+	}
+	reposLabel := "Repositories"
+	if model.orgRepos {
+		reposLabel = "Organisation Repositories"
+	}
+
+	tabStyle := func(active bool) lipgloss.Style {
+		if active {
+			return lipgloss.NewStyle().Foreground(special).Bold(true).Underline(true).Padding(0, 2)
+		}
+		return lipgloss.NewStyle().Foreground(subtle).Padding(0, 2)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		tabStyle(model.tab == userTabPinned).Render(pinnedLabel),
+		tabStyle(model.tab == userTabRepos).Render(reposLabel),
+	)
+}
+
 func (model UserPageModel) View() string {
 
 	header := lipgloss.JoinVertical(
 		lipgloss.Center,
-		styleTitle.Render(model.currentUserData.Login),
-		styleMeta.Render(model.currentUserData.HTMLURL),
+		model.renderProfileHeader(),
+		" ",
+		model.renderTabs(),
 		" ",
 	)
 
 	var content string
+	list := model.currentList()
 
-	if model.loading {
+	if model.opening {
+		content = fmt.Sprintf("%s Opening repository...", model.spinner.View())
+	} else if model.tab == userTabPinned && model.loadingPinned {
+		content = fmt.Sprintf("%s Loading pinned repositories...", model.spinner.View())
+	} else if model.loading {
 		content = fmt.Sprintf("%s Loading repositories...", model.spinner.View())
-	} else if len(model.repos) == 0 {
+	} else if model.err != nil {
+		content = renderErrorState(model.err, model.Width-4)
+	} else if len(list) == 0 {
 		content = styleMeta.Render("No public repositories found.")
 	} else {
 
 		var listItems []string
+		if model.tab == userTabPinned && len(model.pinned) == 0 && model.pinnedErr != nil &&
+			!errors.Is(model.pinnedErr, errNoPins) {
+			listItems = append(listItems, styleMeta.Render("Pinned repositories unavailable ("+errorTitle(model.pinnedErr)+"); showing most starred."), "")
+		}
+
 		itemsPerPage := 5
 
 		endIndex := model.windowStart + itemsPerPage
-		if endIndex > len(model.repos) {
-			endIndex = len(model.repos)
+		if endIndex > len(list) {
+			endIndex = len(list)
 		}
 
 		for i := model.windowStart; i < endIndex; i++ {
-			repo := model.repos[i]
+			repo := list[i]
 
 			isActive := i == model.cursor
 			style := styleRepoCard
@@ -213,6 +586,7 @@ func (model UserPageModel) View() string {
 		lipgloss.Center,
 		header,
 		content,
+		styleMeta.Render("tab pinned/repositories • enter open • backspace back"),
 	)
 
 	return lipgloss.Place(